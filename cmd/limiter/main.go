@@ -31,7 +31,7 @@ func run() int {
 	flag.Parse()
 
 	ctx, cancel := signal.NotifyContext(context.Background(),
-		syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
 	configR, err := os.Open(configFile)
@@ -55,6 +55,23 @@ func run() int {
 		return 1
 	}
 
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-hup:
+				logg.Info("SIGHUP received, reloading limits..")
+				if err := application.ReloadLimits(); err != nil {
+					logg.Error("Error reloading limits", "error", err)
+				}
+			}
+		}
+	}()
+
 	srv := server.New(
 		server.Options{
 			GRPC: serverGRPC.Options{
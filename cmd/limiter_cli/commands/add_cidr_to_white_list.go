@@ -3,18 +3,25 @@ package commands
 import (
 	"context"
 	"log"
+	"os"
 	"time"
 
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
 	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 var addCidrToWhiteListCmd = &cobra.Command{
 	Use:   "add_cidr_to_white_list [cidr]",
 	Short: "Добавить подсеть в белый список",
+	Long:  "Подсеть или отдельный адрес IPv4/IPv6, например 192.168.1.0/24 или 2001:db8::/48",
 	Run: func(_ *cobra.Command, args []string) {
-		cidr := args[0]
+		cidr, err := rule.NormalizeIPNet(args[0])
+		if err != nil {
+			log.Fatalf("incorrect cidr %q: %v", args[0], err)
+		}
 
 		grpcClient, err := limiter.NewClient(cfg.GRPC.Host, cfg.GRPC.Port)
 		if err != nil {
@@ -22,12 +29,21 @@ var addCidrToWhiteListCmd = &cobra.Command{
 		}
 		defer grpcClient.Close()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(actorContext(), 5*time.Second)
 		defer cancel()
 
-		ok, err := grpcClient.WhiteListAdd(ctx, &proto.WhiteListAddRequest{
-			IpNet: cidr,
-		})
+		req := &proto.WhiteListAddRequest{
+			IpNet:     cidr,
+			Reason:    addCidrToWhiteListReason,
+			CreatedBy: addCidrToWhiteListCreatedBy,
+			Source:    proto.RuleSource_RULE_SOURCE_CLI,
+			Force:     addCidrToWhiteListForce,
+		}
+		if addCidrToWhiteListTTL > 0 {
+			req.ExpiresAt = timestamppb.New(time.Now().Add(addCidrToWhiteListTTL))
+		}
+
+		ok, err := grpcClient.WhiteListAdd(ctx, req)
 		if err != nil {
 			log.Printf("WhiteListAdd error: %v", err)
 		} else {
@@ -36,6 +52,23 @@ var addCidrToWhiteListCmd = &cobra.Command{
 	},
 }
 
+var (
+	addCidrToWhiteListTTL       time.Duration
+	addCidrToWhiteListReason    string
+	addCidrToWhiteListCreatedBy string
+	addCidrToWhiteListForce     bool
+)
+
 func init() {
+	addCidrToWhiteListCmd.Flags().DurationVar(
+		&addCidrToWhiteListTTL, "ttl", 0, "Срок действия правила, например 6h (по умолчанию бессрочно)",
+	)
+	addCidrToWhiteListCmd.Flags().StringVar(&addCidrToWhiteListReason, "reason", "", "Причина добавления правила")
+	addCidrToWhiteListCmd.Flags().StringVar(
+		&addCidrToWhiteListCreatedBy, "created-by", os.Getenv("USER"), "Автор правила (по умолчанию текущий пользователь)",
+	)
+	addCidrToWhiteListCmd.Flags().BoolVar(
+		&addCidrToWhiteListForce, "force", false, "Добавить правило, даже если оно конфликтует с существующими",
+	)
 	rootCmd.AddCommand(addCidrToWhiteListCmd)
 }
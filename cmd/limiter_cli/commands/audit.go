@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var auditFlags struct {
+	pageSize  int32
+	pageToken string
+	action    string
+	actor     string
+	target    string
+	output    string
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Показать журнал административных изменений",
+	Long: "Выводит изменения списков, сбросы bucket'ов и переключения режима наблюдения от новых к старым. " +
+		"Токен следующей страницы передается через --page-token",
+	Args: cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		grpcClient, err := limiter.NewClient(cfg.GRPC.Host, cfg.GRPC.Port)
+		if err != nil {
+			log.Fatalf("failed to create gRPC client: %v", err)
+		}
+		defer grpcClient.Close()
+
+		ctx, cancel := context.WithTimeout(actorContext(), 5*time.Second)
+		defer cancel()
+
+		resp, err := grpcClient.AuditLog(ctx, &proto.AuditLogRequest{
+			PageSize:  auditFlags.pageSize,
+			PageToken: auditFlags.pageToken,
+			Action:    auditFlags.action,
+			Actor:     auditFlags.actor,
+			Target:    auditFlags.target,
+		})
+		if err != nil {
+			log.Fatalf("AuditLog error: %v", err)
+		}
+
+		switch auditFlags.output {
+		case "json":
+			out, err := protojson.MarshalOptions{Multiline: true}.Marshal(resp)
+			if err != nil {
+				log.Fatalf("failed to marshal audit log: %v", err)
+			}
+			fmt.Println(string(out))
+		case "table":
+			printAuditEntries(resp.Entries)
+			if resp.NextPageToken != "" {
+				fmt.Printf("\nNext page: --page-token %s\n", resp.NextPageToken)
+			}
+		default:
+			log.Fatalf("unknown output format %q, expected table or json", auditFlags.output)
+		}
+	},
+}
+
+// printAuditEntries выводит записи журнала таблицей.
+func printAuditEntries(entries []*proto.AuditEntry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTIME\tACTION\tACTOR\tPEER\tTARGET\tREQUEST ID\tBEFORE\tAFTER")
+
+	for _, e := range entries {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Id,
+			e.CreatedAt.AsTime().Local().Format(time.DateTime),
+			e.Action,
+			e.Actor,
+			orDash(e.Peer),
+			e.Target,
+			e.RequestId,
+			orDash(e.Before),
+			orDash(e.After),
+		)
+	}
+
+	w.Flush()
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+func init() {
+	auditCmd.Flags().Int32Var(&auditFlags.pageSize, "page-size", 0, "Количество записей на странице (по умолчанию 50)")
+	auditCmd.Flags().StringVar(&auditFlags.pageToken, "page-token", "", "Токен следующей страницы")
+	auditCmd.Flags().StringVar(
+		&auditFlags.action, "action", "",
+		"Действие: whitelist.add, whitelist.delete, blacklist.add, blacklist.delete, rules.import, limit.reset, shadow.set",
+	)
+	auditCmd.Flags().StringVar(&auditFlags.actor, "by", "", "Инициатор изменения")
+	auditCmd.Flags().StringVar(&auditFlags.target, "target", "", "Объект изменения, например подсеть")
+	auditCmd.Flags().StringVarP(&auditFlags.output, "output", "o", "table", "Формат вывода: table или json")
+
+	rootCmd.AddCommand(auditCmd)
+}
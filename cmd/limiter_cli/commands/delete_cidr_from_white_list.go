@@ -5,6 +5,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
 	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
 	"github.com/spf13/cobra"
@@ -13,8 +14,12 @@ import (
 var deleteCidrFromWhiteListCmd = &cobra.Command{
 	Use:   "delete_cidr_from_white_list [cidr]",
 	Short: "Удалить подсеть из белого списка",
+	Long:  "Подсеть или отдельный адрес IPv4/IPv6, например 192.168.1.0/24 или 2001:db8::/48",
 	Run: func(_ *cobra.Command, args []string) {
-		cidr := args[0]
+		cidr, err := rule.NormalizeIPNet(args[0])
+		if err != nil {
+			log.Fatalf("incorrect cidr %q: %v", args[0], err)
+		}
 
 		grpcClient, err := limiter.NewClient(cfg.GRPC.Host, cfg.GRPC.Port)
 		if err != nil {
@@ -22,7 +27,7 @@ var deleteCidrFromWhiteListCmd = &cobra.Command{
 		}
 		defer grpcClient.Close()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(actorContext(), 5*time.Second)
 		defer cancel()
 
 		ok, err := grpcClient.WhiteListDelete(ctx, &proto.WhiteListDeleteRequest{
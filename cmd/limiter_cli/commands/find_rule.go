@@ -0,0 +1,79 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/spf13/cobra"
+)
+
+var findRuleCmd = &cobra.Command{
+	Use:   "find_rule [cidr]",
+	Short: "Показать правила белого и черного списков для подсети",
+	Long:  "Выводит правила подсети вместе с причиной, автором, временем и способом добавления",
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		cidr, err := rule.NormalizeIPNet(args[0])
+		if err != nil {
+			log.Fatalf("incorrect cidr %q: %v", args[0], err)
+		}
+
+		grpcClient, err := limiter.NewClient(cfg.GRPC.Host, cfg.GRPC.Port)
+		if err != nil {
+			log.Fatalf("failed to create gRPC client: %v", err)
+		}
+		defer grpcClient.Close()
+
+		ctx, cancel := context.WithTimeout(actorContext(), 5*time.Second)
+		defer cancel()
+
+		resp, err := grpcClient.RuleFind(ctx, &proto.RuleFindRequest{IpNet: cidr})
+		if err != nil {
+			log.Fatalf("RuleFind error: %v", err)
+		}
+
+		if len(resp.Rules) == 0 {
+			log.Printf("No rules found for %s", cidr)
+			return
+		}
+
+		printRules(resp.Rules)
+	},
+}
+
+// printRules выводит правила таблицей.
+func printRules(rules []*proto.Rule) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tIP NET\tLIST\tSOURCE\tCREATED BY\tCREATED AT\tEXPIRES AT\tREASON")
+
+	for _, r := range rules {
+		expiresAt := "-"
+		if r.ExpiresAt != nil {
+			expiresAt = r.ExpiresAt.AsTime().Local().Format(time.DateTime)
+		}
+
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Id,
+			r.IpNet,
+			r.ListType,
+			r.Source,
+			r.CreatedBy,
+			r.CreatedAt.AsTime().Local().Format(time.DateTime),
+			expiresAt,
+			r.Reason,
+		)
+	}
+
+	w.Flush()
+}
+
+func init() {
+	rootCmd.AddCommand(findRuleCmd)
+}
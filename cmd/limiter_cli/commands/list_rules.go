@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// listRulesFlags параметры выборки правил списка.
+type listRulesFlags struct {
+	pageSize   int32
+	pageToken  string
+	containsIP string
+	prefix     string
+	reason     string
+	createdBy  string
+	source     string
+	output     string
+}
+
+type listRulesFunc func(
+	client proto.AuthLimiterClient,
+	ctx context.Context,
+	req *proto.RuleListRequest,
+	opts ...grpc.CallOption,
+) (*proto.RuleListResponse, error)
+
+var ruleSources = map[string]proto.RuleSource{
+	"manual":   proto.RuleSource_RULE_SOURCE_MANUAL,
+	"cli":      proto.RuleSource_RULE_SOURCE_CLI,
+	"auto-ban": proto.RuleSource_RULE_SOURCE_AUTO_BAN,
+	"import":   proto.RuleSource_RULE_SOURCE_IMPORT,
+	"feed":     proto.RuleSource_RULE_SOURCE_FEED,
+}
+
+func newListRulesCmd(use, short string, list listRulesFunc) *cobra.Command {
+	flags := &listRulesFlags{}
+
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Long:  "Выводит действующие правила постранично. Токен следующей страницы передается через --page-token",
+		Args:  cobra.NoArgs,
+		Run: func(_ *cobra.Command, _ []string) {
+			req := &proto.RuleListRequest{
+				PageSize:   flags.pageSize,
+				PageToken:  flags.pageToken,
+				ContainsIp: flags.containsIP,
+				Prefix:     flags.prefix,
+				Reason:     flags.reason,
+				CreatedBy:  flags.createdBy,
+			}
+			if flags.source != "" {
+				source, ok := ruleSources[flags.source]
+				if !ok {
+					log.Fatalf("unknown source %q, expected manual, cli, auto-ban, import or feed", flags.source)
+				}
+				req.Source = source
+			}
+
+			grpcClient, err := limiter.NewClient(cfg.GRPC.Host, cfg.GRPC.Port)
+			if err != nil {
+				log.Fatalf("failed to create gRPC client: %v", err)
+			}
+			defer grpcClient.Close()
+
+			ctx, cancel := context.WithTimeout(actorContext(), 5*time.Second)
+			defer cancel()
+
+			resp, err := list(grpcClient.AuthLimiterClient, ctx, req)
+			if err != nil {
+				log.Fatalf("List error: %v", err)
+			}
+
+			switch flags.output {
+			case "json":
+				out, err := protojson.MarshalOptions{Multiline: true}.Marshal(resp)
+				if err != nil {
+					log.Fatalf("failed to marshal rules: %v", err)
+				}
+				fmt.Println(string(out))
+			case "table":
+				printRules(resp.Rules)
+				if resp.NextPageToken != "" {
+					fmt.Printf("\nNext page: --page-token %s\n", resp.NextPageToken)
+				}
+			default:
+				log.Fatalf("unknown output format %q, expected table or json", flags.output)
+			}
+		},
+	}
+
+	cmd.Flags().Int32Var(&flags.pageSize, "page-size", 0, "Количество правил на странице (по умолчанию 50)")
+	cmd.Flags().StringVar(&flags.pageToken, "page-token", "", "Токен следующей страницы")
+	cmd.Flags().StringVar(&flags.containsIP, "contains", "", "Только правила, подсеть которых содержит IP")
+	cmd.Flags().StringVar(&flags.prefix, "prefix", "", "Только правила внутри подсети")
+	cmd.Flags().StringVar(&flags.reason, "reason", "", "Подстрока причины")
+	cmd.Flags().StringVar(&flags.createdBy, "created-by", "", "Автор правила")
+	cmd.Flags().StringVar(&flags.source, "source", "", "Способ добавления: manual, cli, auto-ban, import, feed")
+	cmd.Flags().StringVarP(&flags.output, "output", "o", "table", "Формат вывода: table или json")
+
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(
+		newListRulesCmd("list_white_list", "Показать правила белого списка", proto.AuthLimiterClient.WhiteListList),
+		newListRulesCmd("list_black_list", "Показать правила черного списка", proto.AuthLimiterClient.BlackListList),
+	)
+}
@@ -15,7 +15,7 @@ var resetBucketCmd = &cobra.Command{
 	Short: "Сброс бакета",
 	Run: func(_ *cobra.Command, args []string) {
 		login := args[0]
-		ip := args[0]
+		ip := args[1]
 
 		log.Println("clear_bucket,", "login:", login, " ip:", ip)
 
@@ -25,7 +25,7 @@ var resetBucketCmd = &cobra.Command{
 		}
 		defer grpcClient.Close()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(actorContext(), 5*time.Second)
 		defer cancel()
 
 		ok, err := grpcClient.BucketReset(ctx, &proto.BucketResetRequest{
@@ -1,17 +1,21 @@
 package commands
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
 
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/config"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
 )
 
 var (
 	cfg        *config.Config
 	configFile string
+	actor      string
 )
 
 var rootCmd = &cobra.Command{
@@ -49,12 +53,22 @@ func Execute() {
 		"config",
 		"/configs/config.yml",
 		"Path to configuration file")
+	rootCmd.PersistentFlags().StringVar(
+		&actor,
+		"actor",
+		os.Getenv("USER"),
+		"Пользователь, от имени которого изменения записываются в журнал аудита")
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Fail start: %v", err)
 	}
 }
 
+// actorContext передает серверу пользователя для журнала аудита.
+func actorContext() context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), limiter.XActorKey, actor)
+}
+
 func runApp() error {
 	return nil
 }
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/spf13/cobra"
+)
+
+var setShadowModeCmd = &cobra.Command{
+	Use:   "set_shadow_mode [enabled] [limit types...]",
+	Short: "Режим наблюдения: лимиты считаются, но не отклоняют запросы",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		enabled, err := strconv.ParseBool(args[0])
+		if err != nil {
+			log.Fatalf("incorrect enabled value: %v", err)
+		}
+		limitTypes := args[1:]
+
+		grpcClient, err := limiter.NewClient(cfg.GRPC.Host, cfg.GRPC.Port)
+		if err != nil {
+			log.Fatalf("failed to create gRPC client: %v", err)
+		}
+		defer grpcClient.Close()
+
+		ctx, cancel := context.WithTimeout(actorContext(), 5*time.Second)
+		defer cancel()
+
+		ok, err := grpcClient.ShadowModeSet(ctx, &proto.ShadowModeSetRequest{
+			Enabled:    enabled,
+			LimitTypes: limitTypes,
+		})
+		if err != nil {
+			log.Printf("ShadowModeSet error: %v", err)
+		} else {
+			log.Printf("ShadowModeSet success: %v", ok)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setShadowModeCmd)
+}
@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/spf13/cobra"
+)
+
+// transferChunkSize размер части файла в запросе ImportRules.
+const transferChunkSize = 64 * 1024
+
+// transferTimeout время на загрузку или выгрузку всего файла.
+const transferTimeout = 5 * time.Minute
+
+var transferListTypes = map[string]proto.ListType{
+	"white": proto.ListType_LIST_TYPE_WHITELIST,
+	"black": proto.ListType_LIST_TYPE_BLACKLIST,
+}
+
+var transferFormats = map[string]proto.RuleFormat{
+	"plain": proto.RuleFormat_RULE_FORMAT_PLAIN,
+	"csv":   proto.RuleFormat_RULE_FORMAT_CSV,
+	"jsonl": proto.RuleFormat_RULE_FORMAT_JSON_LINES,
+}
+
+var importModes = map[string]proto.ImportMode{
+	"merge":   proto.ImportMode_IMPORT_MODE_MERGE,
+	"replace": proto.ImportMode_IMPORT_MODE_REPLACE,
+}
+
+var (
+	transferList     string
+	transferFormat   string
+	importMode       string
+	importReason     string
+	importCreatedBy  string
+	exportOutputFile string
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Импортировать правила белого или черного списка из файла",
+	Long: "Загружает подсети из файла (или stdin, если файл не указан) в формате plain, csv или jsonl. " +
+		"В режиме replace предварительно удаляются ранее импортированные правила списка",
+	Args: cobra.MaximumNArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		header := &proto.ImportRulesHeader{
+			ListType:  lookupFlag("list", transferList, transferListTypes),
+			Format:    lookupFlag("format", transferFormat, transferFormats),
+			Mode:      lookupFlag("mode", importMode, importModes),
+			Reason:    importReason,
+			CreatedBy: importCreatedBy,
+		}
+
+		input := io.Reader(os.Stdin)
+		if len(args) == 1 {
+			file, err := os.Open(args[0])
+			if err != nil {
+				log.Fatalf("failed to open %s: %v", args[0], err)
+			}
+			defer file.Close()
+			input = file
+		}
+
+		grpcClient, err := limiter.NewClient(cfg.GRPC.Host, cfg.GRPC.Port)
+		if err != nil {
+			log.Fatalf("failed to create gRPC client: %v", err)
+		}
+		defer grpcClient.Close()
+
+		ctx, cancel := context.WithTimeout(actorContext(), transferTimeout)
+		defer cancel()
+
+		stream, err := grpcClient.ImportRules(ctx)
+		if err != nil {
+			log.Fatalf("ImportRules error: %v", err)
+		}
+
+		err = stream.Send(&proto.ImportRulesRequest{Payload: &proto.ImportRulesRequest_Header{Header: header}})
+		buf := make([]byte, transferChunkSize)
+		for err == nil {
+			var n int
+			n, err = input.Read(buf)
+			if n > 0 {
+				chunk := make([]byte, n)
+				copy(chunk, buf[:n])
+				if sendErr := stream.Send(&proto.ImportRulesRequest{
+					Payload: &proto.ImportRulesRequest_Chunk{Chunk: chunk},
+				}); sendErr != nil {
+					err = sendErr
+				}
+			}
+		}
+		// ошибка отправки в поток (io.EOF) раскрывается в CloseAndRecv
+		if !errors.Is(err, io.EOF) {
+			log.Fatalf("failed to read rules: %v", err)
+		}
+
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			log.Fatalf("ImportRules error: %v", err)
+		}
+
+		log.Printf(
+			"Imported: added %d, skipped %d, invalid %d, removed %d",
+			resp.Added, resp.Skipped, resp.Invalid, resp.Removed,
+		)
+		for _, lineErr := range resp.Errors {
+			log.Printf("line %d: %s", lineErr.Line, lineErr.Message)
+		}
+	},
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Экспортировать правила белого или черного списка",
+	Long:  "Выгружает действующие правила списка в формате plain, csv или jsonl в stdout или файл",
+	Args:  cobra.NoArgs,
+	Run: func(_ *cobra.Command, _ []string) {
+		req := &proto.ExportRulesRequest{
+			ListType: lookupFlag("list", transferList, transferListTypes),
+			Format:   lookupFlag("format", transferFormat, transferFormats),
+		}
+
+		output := io.Writer(os.Stdout)
+		if exportOutputFile != "" {
+			file, err := os.Create(exportOutputFile)
+			if err != nil {
+				log.Fatalf("failed to create %s: %v", exportOutputFile, err)
+			}
+			defer file.Close()
+			output = file
+		}
+
+		grpcClient, err := limiter.NewClient(cfg.GRPC.Host, cfg.GRPC.Port)
+		if err != nil {
+			log.Fatalf("failed to create gRPC client: %v", err)
+		}
+		defer grpcClient.Close()
+
+		ctx, cancel := context.WithTimeout(actorContext(), transferTimeout)
+		defer cancel()
+
+		stream, err := grpcClient.ExportRules(ctx, req)
+		if err != nil {
+			log.Fatalf("ExportRules error: %v", err)
+		}
+
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if err != nil {
+				log.Fatalf("ExportRules error: %v", err)
+			}
+			if _, err := output.Write(resp.Chunk); err != nil {
+				log.Fatalf("failed to write rules: %v", err)
+			}
+		}
+	},
+}
+
+// lookupFlag возвращает значение перечисления для флага или завершает работу с ошибкой.
+func lookupFlag[T any](name, value string, values map[string]T) T {
+	result, ok := values[value]
+	if !ok {
+		log.Fatalf("unknown --%s value %q", name, value)
+	}
+	return result
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{importCmd, exportCmd} {
+		cmd.Flags().StringVar(&transferList, "list", "black", "Список: white или black")
+		cmd.Flags().StringVar(&transferFormat, "format", "plain", "Формат файла: plain, csv или jsonl")
+	}
+	importCmd.Flags().StringVar(&importMode, "mode", "merge", "Режим: merge или replace")
+	importCmd.Flags().StringVar(&importReason, "reason", "", "Причина для строк без причины")
+	importCmd.Flags().StringVar(
+		&importCreatedBy, "created-by", os.Getenv("USER"), "Автор правил (по умолчанию текущий пользователь)",
+	)
+	exportCmd.Flags().StringVarP(&exportOutputFile, "output", "o", "", "Файл для выгрузки (по умолчанию stdout)")
+
+	rootCmd.AddCommand(importCmd, exportCmd)
+}
@@ -2,28 +2,70 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/snapshot"
+	redisstore "github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/store/redis"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/config"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/interfaces"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/auth"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/composite"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/gcra"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/keyhash"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/tokenbucket/gb"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/metrics"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule/feed"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/storage/postgres"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const (
+	BucketStoreMemory = "memory"
+	BucketStoreRedis  = "redis"
+
+	LimiterBucket = "bucket"
+	LimiterGCRA   = "gcra"
+)
+
+var (
+	ErrUnknownBucketStore = errors.New("unknown bucket store type")
+	ErrUnknownLimiter     = errors.New("unknown limiter type")
+	// ErrKeyHashSecret без заданного секрета ключи bucket'ов меняются при перезапуске и не совпадают
+	// с сохраненными в снимках и Redis.
+	ErrKeyHashSecret = errors.New("key hash secret is required when buckets are persisted")
+	// ErrKeyHashRotatedAt grace-период предыдущего секрета отсчитывается от времени смены, а не от запуска.
+	ErrKeyHashRotatedAt = errors.New("key hash rotatedAt in RFC3339 format is required with previousSecret")
+	// ErrGCRAPersistence GCRA хранит TAT клиентов только в памяти процесса и не поддерживает снимки и Redis.
+	ErrGCRAPersistence = errors.New("gcra limiter does not support snapshots and redis bucket store")
+	// ErrRuleReaperInterval при нулевом или отрицательном интервале удаление истекших правил шло бы без пауз.
+	ErrRuleReaperInterval = errors.New("rule reaper interval must be positive")
 )
 
 type App struct {
-	rule    rule.IService
-	limiter limiter.IService
+	rule          rule.IService
+	audit         audit.IService
+	limiter       *auth.Limiter
+	bucketLimiter *composite.Limiter
 
 	logger appinterfaces.Logger
 	config *config.Config
 }
 
 func New(ctx context.Context, config *config.Config, logger appinterfaces.Logger) (appinterfaces.Application, error) {
+	if config.App.RuleReaper.Interval <= 0 {
+		return nil, ErrRuleReaperInterval
+	}
+
 	postgresStorage := postgres.New()
 
 	if err := postgresStorage.Connect(ctx); err != nil {
@@ -37,7 +79,37 @@ func New(ctx context.Context, config *config.Config, logger appinterfaces.Logger
 		limitStorage,
 		refillrate.New(config.App.RefillRate.Count, config.App.RefillRate.Time),
 	)
+	if err := setupBucketStore(ctx, config, bucketLimiter); err != nil {
+		return nil, err
+	}
+	if err := setupLimiter(config, bucketLimiter); err != nil {
+		return nil, err
+	}
+	if err := prometheus.Register(metrics.NewBucketCollector(bucketLimiter)); err != nil {
+		return nil, err
+	}
 	limiterService := auth.New(ruleService, bucketLimiter)
+	limiterService.SetShadow(config.App.Shadow.Enabled, config.App.Shadow.Types)
+	if config.App.IPv6.Aggregate {
+		limiterService.SetIPv6Prefix(config.App.IPv6.PrefixLen)
+	}
+	if !config.App.KeyHash.Disabled {
+		keyHasher, err := setupKeyHash(config, logger)
+		if err != nil {
+			return nil, err
+		}
+		limiterService.SetKeyHasher(keyHasher)
+	}
+	if config.App.AutoBan.Enabled {
+		limiterService.SetAutoBan(auth.AutoBan{
+			Threshold:  config.App.AutoBan.Threshold,
+			Window:     config.App.AutoBan.Window,
+			TTL:        config.App.AutoBan.TTL,
+			Backoff:    config.App.AutoBan.Backoff,
+			MaxTTL:     config.App.AutoBan.MaxTTL,
+			ForgetTime: config.App.AutoBan.ForgetTime,
+		})
+	}
 
 	// Init Limiter Garbage Collector
 	limiterGB := gb.New(bucketLimiter, config.App.GarbageCollector.TTL)
@@ -62,42 +134,489 @@ func New(ctx context.Context, config *config.Config, logger appinterfaces.Logger
 		}()
 	}
 
-	return &App{
-		rule:    ruleService,
-		limiter: limiterService,
+	// Init rule reaper
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				logger.Info("Rule reaper finished.")
+
+				return
+			case <-time.After(config.App.RuleReaper.Interval):
+				deleted, err := ruleService.DeleteExpired()
+				if err != nil {
+					logger.Error("Rule reaper error", "error", err)
+				} else if deleted > 0 {
+					logger.Info("Expired rules removed", "count", deleted)
+				}
+				limiterService.SweepAutoBan()
+			}
+		}
+	}()
+
+	if config.App.Snapshot.Enabled {
+		if err := startSnapshots(ctx, config, logger, postgresStorage, bucketLimiter); err != nil {
+			return nil, err
+		}
+	}
+
+	application := &App{
+		rule:          ruleService,
+		audit:         audit.NewService(audit.NewStorage(postgresStorage)),
+		limiter:       limiterService,
+		bucketLimiter: bucketLimiter,
 
 		logger: logger,
 		config: config,
-	}, nil
+	}
+
+	application.startThreatFeeds(ctx, ruleService)
+
+	if config.App.Reload.Enabled || config.App.Reload.Listen {
+		application.startReload(ctx, postgresStorage)
+	}
+
+	// Индексы правил других реплик сбрасываются по уведомлениям об изменении ip_net_rule.
+	if config.App.RuleIndex.Listen {
+		go application.listen(
+			ctx, postgresStorage, rule.RulesChangedChannel, config.App.RuleIndex.RetryInterval, ruleService.Invalidate,
+		)
+	}
+
+	return application, nil
+}
+
+// setupBucketStore подключает внешнее хранилище bucket'ов, если оно задано в конфигурации.
+func setupBucketStore(ctx context.Context, config *config.Config, bucketLimiter *composite.Limiter) error {
+	switch config.App.BucketStore.Type {
+	case BucketStoreMemory:
+		return nil
+	case BucketStoreRedis:
+		redisConfig := config.App.BucketStore.Redis
+		client := goredis.NewClient(&goredis.Options{
+			Addr:     redisConfig.Addr,
+			Password: redisConfig.Password,
+			DB:       redisConfig.DB,
+		})
+		if err := client.Ping(ctx).Err(); err != nil {
+			return err
+		}
+
+		bucketLimiter.SetBucketStoreFactory(func(limitType string, algorithm bucket.Algorithm) bucket.IBucketStore {
+			// Состояния разных алгоритмов хранятся в разных структурах Redis и не должны пересекаться.
+			prefix := redisConfig.KeyPrefix + limitType + ":"
+			if algorithm != "" && algorithm != bucket.TokenBucket {
+				prefix += string(algorithm) + ":"
+			}
+
+			return redisstore.NewWithAlgorithm(ctx, client, prefix, algorithm)
+		})
+
+		return nil
+	default:
+		return ErrUnknownBucketStore
+	}
+}
+
+// setupLimiter выбирает реализацию token bucket'а. GCRA допустим только без внешнего хранения bucket'ов.
+func setupLimiter(config *config.Config, bucketLimiter *composite.Limiter) error {
+	switch config.App.Limiter.Type {
+	case LimiterBucket:
+		return nil
+	case LimiterGCRA:
+		if config.App.Snapshot.Enabled || config.App.BucketStore.Type != BucketStoreMemory {
+			return ErrGCRAPersistence
+		}
+		bucketLimiter.SetLimiterFactory(gcra.New)
+
+		return nil
+	default:
+		return ErrUnknownLimiter
+	}
+}
+
+// setupKeyHash создает Hasher из секретов конфигурации. Предыдущий секрет действует grace-период
+// от времени смены секрета, поэтому перезапуск не продлевает его.
+func setupKeyHash(config *config.Config, logger appinterfaces.Logger) (*keyhash.Hasher, error) {
+	keyHashConfig := config.App.KeyHash
+
+	secret := []byte(keyHashConfig.Secret)
+	if len(secret) == 0 {
+		if config.App.Snapshot.Enabled || config.App.BucketStore.Type == BucketStoreRedis {
+			return nil, ErrKeyHashSecret
+		}
+
+		logger.Warn("Key hash secret is not set, using a random one: hashed bucket keys change on restart")
+
+		var err error
+		if secret, err = keyhash.RandomSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	if keyHashConfig.PreviousSecret == "" {
+		return keyhash.New(secret, keyHashConfig.Types)
+	}
+
+	rotatedAt, err := time.Parse(time.RFC3339, keyHashConfig.RotatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrKeyHashRotatedAt, err)
+	}
+
+	keyHasher, err := keyhash.New([]byte(keyHashConfig.PreviousSecret), keyHashConfig.Types)
+	if err != nil {
+		return nil, err
+	}
+	if err = keyHasher.Rotate(secret, keyHashConfig.GracePeriod, rotatedAt); err != nil {
+		return nil, err
+	}
+
+	return keyHasher, nil
+}
+
+// startSnapshots восстанавливает bucket'ы из PostgreSQL и запускает их периодическое сохранение.
+// Последнее сохранение выполняется при остановке, поэтому хранилище снимков не зависит от отмены ctx.
+func startSnapshots(
+	ctx context.Context,
+	config *config.Config,
+	logger appinterfaces.Logger,
+	postgresStorage *postgres.Storage,
+	bucketLimiter *composite.Limiter,
+) error {
+	snapshotStorage := snapshot.NewStorage(&postgres.Storage{
+		DB:  postgresStorage.DB,
+		Ctx: context.WithoutCancel(ctx),
+	})
+	snapshotService := snapshot.NewService(bucketLimiter, snapshotStorage)
+
+	restored, err := snapshotService.Restore()
+	if err != nil {
+		return err
+	}
+	logger.Info("Buckets restored from snapshot", "count", restored)
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				if err := snapshotService.Flush(); err != nil {
+					logger.Error("Snapshot error", "error", err)
+				}
+				logger.Info("Snapshot finished.")
+
+				return
+			case <-time.After(config.App.Snapshot.Interval):
+				logger.Debug("Snapshot flushing..")
+
+				if err := snapshotService.Flush(); err != nil {
+					logger.Error("Snapshot error", "error", err)
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// startThreatFeeds запускает синхронизацию включенных блоклистов: сразу при старте и далее с интервалом блоклиста.
+func (a *App) startThreatFeeds(ctx context.Context, ruleService rule.IService) {
+	for _, feedConfig := range a.config.App.ThreatFeeds {
+		if !feedConfig.Enabled {
+			continue
+		}
+
+		threatFeed := feed.New(feed.Options{
+			Name: feedConfig.Name,
+			URL:  feedConfig.URL,
+			Path: feedConfig.Path,
+		}, ruleService, &http.Client{Timeout: feedConfig.Timeout})
+
+		go func() {
+			for {
+				summary, err := threatFeed.Sync(ctx)
+				if err != nil {
+					a.logger.Error("Threat feed error", "feed", threatFeed.Name(), "error", err)
+				} else {
+					a.logger.Info(
+						"Threat feed synced",
+						"feed", threatFeed.Name(),
+						"added", summary.Added,
+						"removed", summary.Removed,
+						"skipped", summary.Skipped,
+						"invalid", summary.Invalid,
+					)
+				}
+
+				select {
+				case <-ctx.Done():
+					a.logger.Info("Threat feed finished.", "feed", threatFeed.Name())
+
+					return
+				case <-time.After(feedConfig.Interval):
+				}
+			}
+		}()
+	}
+}
+
+// startReload запускает перезагрузку лимитов: периодическую и/или по уведомлениям PostgreSQL.
+// При разрыве соединения подписка на уведомления восстанавливается через интервал перезагрузки.
+func (a *App) startReload(ctx context.Context, postgresStorage *postgres.Storage) {
+	reloadConfig := a.config.App.Reload
+	notifications := make(chan struct{}, 1)
+
+	if reloadConfig.Listen {
+		go a.listen(ctx, postgresStorage, limiter.LimitsChangedChannel, reloadConfig.Interval, func() {
+			select {
+			case notifications <- struct{}{}:
+			default:
+			}
+		})
+	}
+
+	go func() {
+		for {
+			var tick <-chan time.Time
+			if reloadConfig.Enabled {
+				tick = time.After(reloadConfig.Interval)
+			}
+
+			select {
+			case <-ctx.Done():
+				a.logger.Info("Reload finished.")
+
+				return
+			case <-tick:
+			case <-notifications:
+			}
+
+			a.logger.Debug("Limits reloading..")
+			if err := a.ReloadLimits(); err != nil {
+				a.logger.Error("Reload error", "error", err)
+			}
+		}
+	}()
+}
+
+// listen подписывается на канал уведомлений PostgreSQL и вызывает onNotify на каждое уведомление.
+// При разрыве соединения подписка восстанавливается через retryInterval.
+func (a *App) listen(
+	ctx context.Context,
+	postgresStorage *postgres.Storage,
+	channel string,
+	retryInterval time.Duration,
+	onNotify func(),
+) {
+	for ctx.Err() == nil {
+		err := postgresStorage.Listen(ctx, channel, func(string) {
+			onNotify()
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		a.logger.Error("Listen error", "channel", channel, "error", err)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(retryInterval):
+		}
+	}
+}
+
+// ReloadLimits перечитывает лимиты из rate_limit без сброса текущих bucket'ов.
+func (a *App) ReloadLimits() error {
+	return a.bucketLimiter.Reload()
+}
+
+// ShadowModeSet переключает режим наблюдения: превышение лимитов только логируется.
+func (a *App) ShadowModeSet(actor audit.Actor, enabled bool, limitTypes []string) {
+	before := shadowState{}
+	before.Enabled, before.LimitTypes = a.limiter.Shadow()
+
+	a.limiter.SetShadow(enabled, limitTypes)
+	a.logger.Info("Shadow mode changed", "enabled", enabled, "limitTypes", limitTypes)
+
+	after := shadowState{}
+	after.Enabled, after.LimitTypes = a.limiter.Shadow()
+	a.record(actor, audit.ActionShadowModeSet, "", before, after)
 }
 
-func (a *App) LimitCheck(ip, login, password string) (bool, error) {
-	return a.limiter.SatisfyLimit(limiter.UserIdentityDto{
+func (a *App) LimitCheck(ip, login, password string) (limiter.Decision, error) {
+	return a.Check(limiter.UserIdentityDto{
 		limiter.IPLimit.String():       ip,
 		limiter.LoginLimit.String():    login,
 		limiter.PasswordLimit.String(): password,
 	})
 }
 
-func (a *App) LimitReset(ip, login string) error {
-	return a.limiter.ResetLimit(limiter.UserIdentityDto{
+func (a *App) Check(dimensions limiter.UserIdentityDto) (limiter.Decision, error) {
+	decision, err := a.limiter.Check(dimensions)
+	if errors.Is(err, auth.ErrAutoBan) {
+		a.logger.Error("Auto-ban error", "error", err)
+	} else if err != nil {
+		return decision, err
+	}
+
+	metrics.ObserveDecision(decision.Allowed, decision.Reason.String(), decision.LimitType.String(), decision.Shadow)
+	if decision.Shadow {
+		a.logger.Info(
+			"Shadow mode: limit exceeded",
+			"limitType", decision.LimitType,
+			"tier", decision.Tier,
+			"ip", dimensions[limiter.IPLimit.String()],
+			"login", dimensions[limiter.LoginLimit.String()],
+		)
+	}
+
+	return decision, nil
+}
+
+// LimitReset сбрасывает bucket'ы ip и login и записывает в журнал аудита их остаток до и после сброса.
+func (a *App) LimitReset(actor audit.Actor, ip, login string) error {
+	identity := limiter.UserIdentityDto{
 		limiter.IPLimit.String():    ip,
 		limiter.LoginLimit.String(): login,
+	}
+
+	before := a.remaining(identity)
+	if err := a.limiter.ResetLimit(identity); err != nil {
+		return err
+	}
+	a.record(actor, audit.ActionLimitReset, "ip="+ip+" login="+login, before, a.remaining(identity))
+
+	return nil
+}
+
+func (a *App) WhiteListAdd(actor audit.Actor, ip string, options rule.AddOptions) error {
+	return a.changeRule(actor, audit.ActionWhiteListAdd, ip, rule.WhiteList, func() error {
+		return a.rule.WhiteListAdd(ip, options)
 	})
 }
 
-func (a *App) WhiteListAdd(ip string) error {
-	return a.rule.WhiteListAdd(ip)
+func (a *App) WhiteListDelete(actor audit.Actor, ip string) error {
+	return a.changeRule(actor, audit.ActionWhiteListDelete, ip, rule.WhiteList, func() error {
+		return a.rule.WhiteListDelete(ip)
+	})
 }
 
-func (a *App) WhiteListDelete(ip string) error {
-	return a.rule.WhiteListDelete(ip)
+func (a *App) BlackListAdd(actor audit.Actor, ip string, options rule.AddOptions) error {
+	return a.changeRule(actor, audit.ActionBlackListAdd, ip, rule.BlackList, func() error {
+		return a.rule.BlackListAdd(ip, options)
+	})
+}
+
+func (a *App) BlackListDelete(actor audit.Actor, ip string) error {
+	return a.changeRule(actor, audit.ActionBlackListDelete, ip, rule.BlackList, func() error {
+		return a.rule.BlackListDelete(ip)
+	})
+}
+
+func (a *App) RuleFind(ip string) (*rule.Rules, error) {
+	return a.rule.Find(ip)
+}
+
+func (a *App) RuleList(filter rule.ListFilter) (*rule.Page, error) {
+	return a.rule.List(filter)
+}
+
+func (a *App) RuleImport(actor audit.Actor, r io.Reader, options rule.ImportOptions) (*rule.ImportSummary, error) {
+	summary, err := a.rule.Import(r, options)
+	if err != nil {
+		return nil, err
+	}
+	a.record(actor, audit.ActionRulesImport, string(options.RuleType), nil, importState{
+		Format:  options.Format,
+		Mode:    options.Mode,
+		Summary: summary,
+	})
+
+	a.logger.Info(
+		"Rules imported",
+		"listType", options.RuleType,
+		"mode", options.Mode,
+		"added", summary.Added,
+		"skipped", summary.Skipped,
+		"invalid", summary.Invalid,
+		"removed", summary.Removed,
+	)
+
+	return summary, nil
+}
+
+func (a *App) RuleExport(w io.Writer, ruleType rule.Type, format rule.Format) error {
+	return a.rule.Export(w, ruleType, format)
+}
+
+func (a *App) AuditLog(filter audit.ListFilter) (*audit.Page, error) {
+	return a.audit.List(filter)
 }
 
-func (a *App) BlackListAdd(ip string) error {
-	return a.rule.BlackListAdd(ip)
+// shadowState режим наблюдения в журнале аудита.
+type shadowState struct {
+	Enabled    bool     `json:"enabled"`
+	LimitTypes []string `json:"limitTypes"`
 }
 
-func (a *App) BlackListDelete(ip string) error {
-	return a.rule.BlackListDelete(ip)
+// importState параметры и результат импорта в журнале аудита.
+type importState struct {
+	Format  rule.Format         `json:"format"`
+	Mode    rule.ImportMode     `json:"mode"`
+	Summary *rule.ImportSummary `json:"summary"`
+}
+
+// changeRule выполняет изменение списка listType и записывает в журнал аудита правила подсети ip до и после него.
+func (a *App) changeRule(
+	actor audit.Actor,
+	action audit.Action,
+	ip string,
+	listType rule.Type,
+	change func() error,
+) error {
+	target, err := rule.NormalizeIPNet(ip)
+	if err != nil {
+		target = ip
+	}
+
+	before := a.listRules(target, listType)
+	if err := change(); err != nil {
+		return err
+	}
+	a.record(actor, action, target, before, a.listRules(target, listType))
+
+	return nil
+}
+
+// listRules возвращает правила списка listType для подсети ip, nil - правил нет или их не удалось получить.
+func (a *App) listRules(ip string, listType rule.Type) rule.Rules {
+	rules, err := a.rule.Find(ip)
+	if err != nil {
+		return nil
+	}
+
+	var result rule.Rules
+	for _, r := range *rules {
+		if r.RuleType == listType {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+// remaining возвращает остаток bucket'ов identity по лимитам, nil - остаток не удалось получить.
+func (a *App) remaining(identity limiter.UserIdentityDto) map[string]int {
+	remaining, err := a.limiter.Remaining(identity)
+	if err != nil {
+		return nil
+	}
+
+	return remaining
+}
+
+// record добавляет изменение в журнал аудита. Журнал ведется по возможности (best-effort): изменение уже
+// применено, поэтому ошибка записи не отменяет его и не возвращается вызывающему, а только логируется.
+func (a *App) record(actor audit.Actor, action audit.Action, target string, before, after any) {
+	if err := a.audit.Record(actor, action, target, before, after); err != nil {
+		a.logger.Error("Audit log error", "action", action, "target", target, "error", err)
+	}
 }
@@ -0,0 +1,72 @@
+package audit
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Action вид административного изменения.
+type Action string
+
+const (
+	ActionWhiteListAdd    Action = "whitelist.add"
+	ActionWhiteListDelete Action = "whitelist.delete"
+	ActionBlackListAdd    Action = "blacklist.add"
+	ActionBlackListDelete Action = "blacklist.delete"
+	ActionRulesImport     Action = "rules.import"
+	ActionLimitReset      Action = "limit.reset"
+	ActionShadowModeSet   Action = "shadow.set"
+)
+
+// Actor инициатор изменения.
+type Actor struct {
+	// Пользователь или адрес клиента, как их назвал сам клиент (x-actor, X-Forwarded-For), иначе адрес соединения.
+	Identity  string
+	RequestID string
+	// Адрес соединения, с которого пришел запрос. В отличие от Identity клиент не может его подменить.
+	Peer string
+}
+
+type Entries []Entry
+
+// Entry запись журнала аудита. Before и After - JSON состояния объекта до и после изменения, nil - нет состояния.
+type Entry struct {
+	ID        int
+	Action    Action
+	Actor     string
+	RequestID string
+	Peer      string
+	// Объект изменения: подсеть, ключи bucket'а, список.
+	Target    string
+	Before    json.RawMessage
+	After     json.RawMessage
+	CreatedAt time.Time
+}
+
+// ListFilter отбор и постраничная выборка записей от новых к старым. Пустые поля не ограничивают выборку.
+type ListFilter struct {
+	Action Action
+	Actor  string
+	Target string
+	// Выборка записей с ID меньше BeforeID, 0 - с самой новой.
+	BeforeID int
+	Limit    int
+}
+
+// Page страница журнала. NextBeforeID - значение BeforeID для следующей страницы, 0 для последней.
+type Page struct {
+	Entries      Entries
+	NextBeforeID int
+}
+
+type IStorage interface {
+	Create(entry Entry) (int, error)
+	// List возвращает не более filter.Limit записей, подходящих под фильтр, от новых к старым.
+	List(filter ListFilter) (*Entries, error)
+}
+
+type IService interface {
+	// Record добавляет в журнал изменение target, before и after сериализуются в JSON.
+	Record(actor Actor, action Action, target string, before, after any) error
+	List(filter ListFilter) (*Page, error)
+}
@@ -0,0 +1,159 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package audit
+
+import (
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIStorage creates a new instance of MockIStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIStorage {
+	mock := &MockIStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIStorage is an autogenerated mock type for the IStorage type
+type MockIStorage struct {
+	mock.Mock
+}
+
+type MockIStorage_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIStorage) EXPECT() *MockIStorage_Expecter {
+	return &MockIStorage_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) Create(entry audit.Entry) (int, error) {
+	ret := _mock.Called(entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(audit.Entry) (int, error)); ok {
+		return returnFunc(entry)
+	}
+	if returnFunc, ok := ret.Get(0).(func(audit.Entry) int); ok {
+		r0 = returnFunc(entry)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(audit.Entry) error); ok {
+		r1 = returnFunc(entry)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStorage_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIStorage_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - entry audit.Entry
+func (_e *MockIStorage_Expecter) Create(entry interface{}) *MockIStorage_Create_Call {
+	return &MockIStorage_Create_Call{Call: _e.mock.On("Create", entry)}
+}
+
+func (_c *MockIStorage_Create_Call) Run(run func(entry audit.Entry)) *MockIStorage_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 audit.Entry
+		if args[0] != nil {
+			arg0 = args[0].(audit.Entry)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_Create_Call) Return(n int, err error) *MockIStorage_Create_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockIStorage_Create_Call) RunAndReturn(run func(entry audit.Entry) (int, error)) *MockIStorage_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) List(filter audit.ListFilter) (*audit.Entries, error) {
+	ret := _mock.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *audit.Entries
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(audit.ListFilter) (*audit.Entries, error)); ok {
+		return returnFunc(filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(audit.ListFilter) *audit.Entries); ok {
+		r0 = returnFunc(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*audit.Entries)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(audit.ListFilter) error); ok {
+		r1 = returnFunc(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStorage_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockIStorage_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - filter audit.ListFilter
+func (_e *MockIStorage_Expecter) List(filter interface{}) *MockIStorage_List_Call {
+	return &MockIStorage_List_Call{Call: _e.mock.On("List", filter)}
+}
+
+func (_c *MockIStorage_List_Call) Run(run func(filter audit.ListFilter)) *MockIStorage_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 audit.ListFilter
+		if args[0] != nil {
+			arg0 = args[0].(audit.ListFilter)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_List_Call) Return(entries *audit.Entries, err error) *MockIStorage_List_Call {
+	_c.Call.Return(entries, err)
+	return _c
+}
+
+func (_c *MockIStorage_List_Call) RunAndReturn(run func(filter audit.ListFilter) (*audit.Entries, error)) *MockIStorage_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
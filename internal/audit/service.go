@@ -0,0 +1,79 @@
+package audit
+
+import "encoding/json"
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 1000
+)
+
+type Service struct {
+	auditStorage IStorage
+}
+
+func NewService(auditStorage IStorage) *Service {
+	return &Service{auditStorage: auditStorage}
+}
+
+func (s Service) Record(actor Actor, action Action, target string, before, after any) error {
+	beforeJSON, err := marshal(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := marshal(after)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.auditStorage.Create(Entry{
+		Action:    action,
+		Actor:     actor.Identity,
+		RequestID: actor.RequestID,
+		Peer:      actor.Peer,
+		Target:    target,
+		Before:    beforeJSON,
+		After:     afterJSON,
+	})
+
+	return err
+}
+
+// List возвращает страницу журнала. Размер страницы ограничивается MaxPageSize, по умолчанию DefaultPageSize.
+func (s Service) List(filter ListFilter) (*Page, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	filter.Limit = min(filter.Limit, MaxPageSize)
+
+	// лишняя запись показывает, что есть следующая страница
+	limit := filter.Limit
+	filter.Limit++
+
+	entries, err := s.auditStorage.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Entries: *entries}
+	if len(page.Entries) > limit {
+		page.Entries = page.Entries[:limit]
+		page.NextBeforeID = page.Entries[limit-1].ID
+	}
+
+	return page, nil
+}
+
+// marshal сериализует состояние объекта. nil, в том числе пустой срез или указатель, означает отсутствие состояния.
+func marshal(value any) (json.RawMessage, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil || string(data) == "null" {
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,87 @@
+package audit_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	auditmocks "github.com/rainb0w-clwn/go_auth_limiter/internal/audit/mocks"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Record(t *testing.T) {
+	storage := auditmocks.NewMockIStorage(t)
+	service := audit.NewService(storage)
+
+	storage.EXPECT().Create(audit.Entry{
+		Action:    audit.ActionShadowModeSet,
+		Actor:     "soc",
+		RequestID: "req-1",
+		Peer:      "127.0.0.1:5000",
+		Before:    json.RawMessage(`{"enabled":false}`),
+		After:     json.RawMessage(`{"enabled":true}`),
+	}).Return(1, nil).Once()
+
+	err := service.Record(
+		audit.Actor{Identity: "soc", RequestID: "req-1", Peer: "127.0.0.1:5000"},
+		audit.ActionShadowModeSet,
+		"",
+		map[string]bool{"enabled": false},
+		map[string]bool{"enabled": true},
+	)
+
+	require.NoError(t, err)
+}
+
+func TestService_Record_NoState(t *testing.T) {
+	storage := auditmocks.NewMockIStorage(t)
+	service := audit.NewService(storage)
+
+	errDB := errors.New("db error")
+	storage.EXPECT().Create(audit.Entry{
+		Action: audit.ActionLimitReset,
+		Target: "ip=1.2.3.4",
+	}).Return(0, errDB).Once()
+
+	err := service.Record(audit.Actor{}, audit.ActionLimitReset, "ip=1.2.3.4", []string(nil), nil)
+
+	require.ErrorIs(t, err, errDB)
+}
+
+func TestService_List(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		stored       int
+		expected     int
+		storageLimit int
+		next         int
+	}{
+		{name: "default page size", limit: 0, stored: 3, expected: 3, storageLimit: audit.DefaultPageSize + 1},
+		{name: "next page", limit: 2, stored: 3, expected: 2, storageLimit: 3, next: 9},
+		{name: "max page size", limit: 5000, stored: 0, expected: 0, storageLimit: audit.MaxPageSize + 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := auditmocks.NewMockIStorage(t)
+			service := audit.NewService(storage)
+
+			entries := make(audit.Entries, 0, tt.stored)
+			for i := range tt.stored {
+				entries = append(entries, audit.Entry{ID: 10 - i})
+			}
+			storage.EXPECT().
+				List(audit.ListFilter{Actor: "soc", Limit: tt.storageLimit}).
+				Return(&entries, nil).
+				Once()
+
+			page, err := service.List(audit.ListFilter{Actor: "soc", Limit: tt.limit})
+
+			require.NoError(t, err)
+			require.Len(t, page.Entries, tt.expected)
+			require.Equal(t, tt.next, page.NextBeforeID)
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package audit
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/metrics"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/storage/postgres"
+)
+
+type sqlEntity struct {
+	ID        int            `db:"id"`
+	Action    string         `db:"action"`
+	Actor     string         `db:"actor"`
+	RequestID string         `db:"request_id"`
+	Peer      string         `db:"peer"`
+	Target    string         `db:"target"`
+	Before    sql.NullString `db:"before"`
+	After     sql.NullString `db:"after"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+type Storage struct {
+	*postgres.Storage
+}
+
+func NewStorage(storage *postgres.Storage) *Storage {
+	return &Storage{storage}
+}
+
+func (s *Storage) Create(entry Entry) (int, error) {
+	defer metrics.ObserveQuery("audit", "Create", time.Now())
+
+	query := `
+		INSERT INTO audit_log(action, actor, request_id, peer, target, before, after)
+		VALUES (:action, :actor, :request_id, :peer, :target, CAST(:before AS jsonb), CAST(:after AS jsonb))
+		RETURNING id
+	`
+
+	params := map[string]any{
+		"action":     entry.Action,
+		"actor":      entry.Actor,
+		"request_id": entry.RequestID,
+		"peer":       entry.Peer,
+		"target":     entry.Target,
+		"before":     nullJSON(entry.Before),
+		"after":      nullJSON(entry.After),
+	}
+
+	var id int
+	stmt, err := s.DB.PrepareNamedContext(s.Ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	if err = stmt.GetContext(s.Ctx, &id, params); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *Storage) List(filter ListFilter) (*Entries, error) {
+	defer metrics.ObserveQuery("audit", "List", time.Now())
+
+	query := `
+		SELECT *
+		FROM audit_log
+		WHERE (:before_id = 0 OR id < :before_id)
+			AND (:action = '' OR action = :action)
+			AND (:actor = '' OR actor = :actor)
+			AND (:target = '' OR target = :target)
+		ORDER BY id DESC
+		LIMIT :limit
+	`
+
+	stmt, err := s.DB.PrepareNamedContext(s.Ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var rows []sqlEntity
+
+	err = stmt.SelectContext(
+		s.Ctx,
+		&rows,
+		map[string]any{
+			"before_id": filter.BeforeID,
+			"action":    filter.Action,
+			"actor":     filter.Actor,
+			"target":    filter.Target,
+			"limit":     filter.Limit,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(Entries, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, *s.sqlEntityToEntity(&r))
+	}
+
+	return &result, nil
+}
+
+func (s *Storage) sqlEntityToEntity(se *sqlEntity) *Entry {
+	e := &Entry{
+		ID:        se.ID,
+		Action:    Action(se.Action),
+		Actor:     se.Actor,
+		RequestID: se.RequestID,
+		Peer:      se.Peer,
+		Target:    se.Target,
+		CreatedAt: se.CreatedAt,
+	}
+
+	if se.Before.Valid {
+		e.Before = json.RawMessage(se.Before.String)
+	}
+	if se.After.Valid {
+		e.After = json.RawMessage(se.After.String)
+	}
+
+	return e
+}
+
+func nullJSON(value json.RawMessage) sql.NullString {
+	return sql.NullString{String: string(value), Valid: value != nil}
+}
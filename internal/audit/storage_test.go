@@ -0,0 +1,85 @@
+package audit_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/storage/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorage(t *testing.T) (*audit.Storage, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	pg := &postgres.Storage{
+		DB:  sqlx.NewDb(db, "sqlmock"),
+		Ctx: context.Background(),
+	}
+
+	return audit.NewStorage(pg), mock
+}
+
+func TestStorage_Create(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	mock.ExpectPrepare("INSERT INTO audit_log").
+		ExpectQuery().
+		WithArgs(
+			audit.ActionBlackListAdd,
+			"soc",
+			"req-1",
+			"127.0.0.1:5000",
+			"10.0.0.0/8",
+			sql.NullString{},
+			sql.NullString{String: `{"ip":"10.0.0.0/8"}`, Valid: true},
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+
+	id, err := storage.Create(audit.Entry{
+		Action:    audit.ActionBlackListAdd,
+		Actor:     "soc",
+		RequestID: "req-1",
+		Peer:      "127.0.0.1:5000",
+		Target:    "10.0.0.0/8",
+		After:     json.RawMessage(`{"ip":"10.0.0.0/8"}`),
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 3, id)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_List(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	createdAt := time.Date(2025, 12, 29, 10, 0, 0, 0, time.UTC)
+	columns := []string{"id", "action", "actor", "request_id", "target", "before", "after", "created_at", "peer"}
+	rows := sqlmock.NewRows(columns).
+		AddRow(9, "blacklist.delete", "soc", "req-2", "10.0.0.0/8", `{"ip":"10.0.0.0/8"}`, nil, createdAt, "10.0.0.1:5000").
+		AddRow(8, "blacklist.add", "soc", "req-1", "10.0.0.0/8", nil, `{"ip":"10.0.0.0/8"}`, createdAt, "")
+
+	mock.ExpectPrepare("ORDER BY id DESC").
+		ExpectQuery().
+		WillReturnRows(rows)
+
+	result, err := storage.List(audit.ListFilter{Actor: "soc", BeforeID: 10, Limit: 2})
+
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+	require.Equal(t, audit.ActionBlackListDelete, (*result)[0].Action)
+	require.JSONEq(t, `{"ip":"10.0.0.0/8"}`, string((*result)[0].Before))
+	require.Nil(t, (*result)[0].After)
+	require.Equal(t, "10.0.0.1:5000", (*result)[0].Peer)
+	require.Equal(t, "req-1", (*result)[1].RequestID)
+	require.Equal(t, createdAt, (*result)[1].CreatedAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
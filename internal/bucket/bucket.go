@@ -1,6 +1,32 @@
 package bucket
 
-import "time"
+import (
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+)
+
+// Algorithm алгоритм ограничения частоты запросов, по которому работает bucket.
+type Algorithm string
+
+const (
+	// TokenBucket пополнение токенов с заданной скоростью.
+	TokenBucket Algorithm = "token_bucket"
+	// SlidingLog не более size запросов в любом окне: хранит время каждого запроса.
+	SlidingLog Algorithm = "sliding_log"
+	// SlidingCounter приближение SlidingLog по счетчикам текущего и предыдущего окна.
+	SlidingCounter Algorithm = "sliding_counter"
+)
+
+// Valid проверяет, что алгоритм поддерживается. Пустое значение соответствует TokenBucket.
+func (a Algorithm) Valid() bool {
+	switch a {
+	case "", TokenBucket, SlidingLog, SlidingCounter:
+		return true
+	default:
+		return false
+	}
+}
 
 type IBucket interface {
 	GetSize() int
@@ -11,3 +37,60 @@ type IBucket interface {
 	Reset()
 	Full() bool
 }
+
+// IRefundableBucket bucket, в который можно вернуть забранные токены, например, если запрос не прошел
+// другие лимиты после списания. Количество токенов не превышает размер bucket'а.
+type IRefundableBucket interface {
+	PutToken(int)
+}
+
+// IResizableBucket bucket, который создает свою копию с новыми размером и скоростью пополнения точнее,
+// чем восстановление из State: например, сохраняя время каждого запроса журнала.
+// Израсходованная часть bucket'а сохраняется, как в IResizableBucketStore.
+type IResizableBucket interface {
+	Resize(size int, refillRate refillrate.RefillRate) IBucket
+}
+
+// IBucketStore хранилище состояний bucket'ов.
+// Размер и скорость пополнения передаются в каждом вызове, поэтому одно хранилище
+// может обслуживать bucket'ы с разными параметрами.
+type IBucketStore interface {
+	// Take пополняет bucket по ключу key и забирает из него cost токенов, если их достаточно.
+	Take(key string, size int, refillRate refillrate.RefillRate, cost int) (bool, error)
+	// Tokens пополняет bucket по ключу key и возвращает текущее количество токенов.
+	Tokens(key string, size int, refillRate refillrate.RefillRate) (int, error)
+	// Reset возвращает bucket в исходное (полное) состояние.
+	Reset(key string) error
+	// Delete удаляет bucket из хранилища.
+	Delete(key string) error
+	// Buckets возвращает bucket'ы, которые хранятся в памяти процесса.
+	// Внешние хранилища удаляют устаревшие bucket'ы сами и возвращают пустой набор.
+	Buckets() map[string]*IBucket
+}
+
+// State сохраняемое состояние bucket'а.
+type State struct {
+	Tokens     int
+	LastRefill time.Time
+}
+
+// ISnapshotBucketStore хранилище bucket'ов, состояние которых можно выгружать и восстанавливать.
+type ISnapshotBucketStore interface {
+	IBucketStore
+
+	// Dirty возвращает состояния bucket'ов, изменившихся с прошлого вызова, и ключи удаленных bucket'ов.
+	Dirty() (map[string]State, []string)
+	// Restore восстанавливает bucket из сохраненного состояния.
+	Restore(key string, size int, refillRate refillrate.RefillRate, state State)
+}
+
+// IRefundableBucketStore хранилище, которое умеет возвращать в bucket токены, забранные Take.
+type IRefundableBucketStore interface {
+	Refund(key string, size int, refillRate refillrate.RefillRate, cost int) error
+}
+
+// IResizableBucketStore хранилище, которое умеет менять размер и скорость пополнения уже созданных bucket'ов.
+// Израсходованная часть bucket'а сохраняется: при увеличении размера на N количество токенов тоже растет на N.
+type IResizableBucketStore interface {
+	Resize(size int, refillRate refillrate.RefillRate)
+}
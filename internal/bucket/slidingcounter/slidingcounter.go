@@ -0,0 +1,146 @@
+package slidingcounter
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+)
+
+// Bucket скользящее окно по счетчикам: запросы предыдущего окна учитываются пропорционально
+// его части, попадающей в скользящее окно. Требует O(1) памяти в отличие от журнала запросов.
+// Длина окна берется из периода скорости пополнения, количество токенов пополнения не используется.
+type Bucket struct {
+	sync.RWMutex
+
+	size   int
+	window time.Duration
+
+	windowStart time.Time
+	previous    int
+	current     int
+	lastRefill  time.Time
+}
+
+func New(size int, refillRate refillrate.RefillRate) bucket.IBucket {
+	now := time.Now()
+
+	return &Bucket{
+		size:   size,
+		window: refillRate.GetTime(),
+
+		windowStart: now,
+		lastRefill:  now,
+	}
+}
+
+// Restore создает bucket из сохраненного состояния.
+// Израсходованные токены считаются взятыми в окне, начавшемся в lastRefill.
+func Restore(size int, refillRate refillrate.RefillRate, tokensCount int, lastRefill time.Time) bucket.IBucket {
+	return &Bucket{
+		size:   size,
+		window: refillRate.GetTime(),
+
+		windowStart: lastRefill,
+		current:     max(0, size-tokensCount),
+		lastRefill:  lastRefill,
+	}
+}
+
+// Refill переводит счетчики на окно, в которое попадает текущее время.
+func (b *Bucket) Refill() {
+	b.Lock()
+
+	b.windowStart, b.previous, b.current = b.advance(time.Now())
+
+	b.Unlock()
+}
+
+func (b *Bucket) GetTokenCount() int {
+	b.RLock()
+	defer b.RUnlock()
+
+	return max(0, b.size-b.used(time.Now()))
+}
+
+func (b *Bucket) GetSize() int {
+	return b.size
+}
+
+func (b *Bucket) GetToken(tokenCount int) {
+	b.Lock()
+
+	now := time.Now()
+	b.windowStart, b.previous, b.current = b.advance(now)
+	b.current += tokenCount
+	b.lastRefill = now
+
+	b.Unlock()
+}
+
+// PutToken уменьшает счетчик текущего окна. Токены, забранные в уже завершившемся окне, не возвращаются.
+func (b *Bucket) PutToken(tokenCount int) {
+	b.Lock()
+
+	b.windowStart, b.previous, b.current = b.advance(time.Now())
+	b.current = max(0, b.current-tokenCount)
+
+	b.Unlock()
+}
+
+func (b *Bucket) Reset() {
+	b.Lock()
+
+	now := time.Now()
+	b.windowStart = now
+	b.previous = 0
+	b.current = 0
+	b.lastRefill = now
+
+	b.Unlock()
+}
+
+// GetLastRefill возвращает время последнего забора токенов.
+func (b *Bucket) GetLastRefill() time.Time {
+	b.RLock()
+	defer b.RUnlock()
+
+	return b.lastRefill
+}
+
+func (b *Bucket) Full() bool {
+	b.RLock()
+	defer b.RUnlock()
+
+	return b.used(time.Now()) == 0
+}
+
+// used возвращает оценку количества запросов в окне, заканчивающемся в now.
+func (b *Bucket) used(now time.Time) int {
+	windowStart, previous, current := b.advance(now)
+	if b.window <= 0 {
+		return current
+	}
+
+	remaining := int64(b.window - now.Sub(windowStart))
+	window := int64(b.window)
+
+	return current + int((int64(previous)*remaining+window-1)/window)
+}
+
+// advance возвращает начало окна, в которое попадает now, и счетчики предыдущего и текущего окна.
+func (b *Bucket) advance(now time.Time) (time.Time, int, int) {
+	elapsed := now.Sub(b.windowStart)
+	if b.window <= 0 || elapsed < b.window {
+		return b.windowStart, b.previous, b.current
+	}
+
+	windows := elapsed / b.window
+	previous := 0
+	if windows == 1 {
+		previous = b.current
+	}
+
+	return b.windowStart.Add(windows * b.window), previous, 0
+}
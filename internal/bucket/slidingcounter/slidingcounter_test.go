@@ -0,0 +1,60 @@
+package slidingcounter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/slidingcounter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBucket(t *testing.T) {
+	b := slidingcounter.New(10, refillrate.New(1, time.Minute))
+
+	require.Equal(t, 10, b.GetSize())
+	require.Equal(t, 10, b.GetTokenCount())
+	require.True(t, b.Full())
+}
+
+func TestGetTokenAndReset(t *testing.T) {
+	b := slidingcounter.New(10, refillrate.New(1, time.Minute))
+
+	b.GetToken(3)
+	require.Equal(t, 7, b.GetTokenCount())
+	require.False(t, b.Full())
+
+	b.Reset()
+	require.Equal(t, 10, b.GetTokenCount())
+	require.True(t, b.Full())
+}
+
+func TestPreviousWindowWeighted(t *testing.T) {
+	window := 200 * time.Millisecond
+	b := slidingcounter.New(10, refillrate.New(1, window))
+
+	b.GetToken(10)
+	require.Equal(t, 0, b.GetTokenCount())
+
+	// в следующем окне запросы предыдущего учитываются частично
+	time.Sleep(window + window/2)
+	b.Refill()
+	tokens := b.GetTokenCount()
+	require.Greater(t, tokens, 0)
+	require.Less(t, tokens, 10)
+
+	// через два окна предыдущие запросы не учитываются
+	time.Sleep(window)
+	require.Equal(t, 10, b.GetTokenCount())
+	require.True(t, b.Full())
+}
+
+func TestRestore(t *testing.T) {
+	window := 100 * time.Millisecond
+	b := slidingcounter.Restore(5, refillrate.New(1, window), 2, time.Now())
+
+	require.Equal(t, 2, b.GetTokenCount())
+
+	time.Sleep(2*window + window/2)
+	require.Equal(t, 5, b.GetTokenCount())
+}
@@ -0,0 +1,145 @@
+package slidinglog
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+)
+
+// Bucket скользящее окно по журналу запросов: в любом окне длиной window разрешено не более size токенов.
+// Длина окна берется из периода скорости пополнения, количество токенов пополнения не используется.
+type Bucket struct {
+	sync.RWMutex
+
+	size   int
+	window time.Duration
+
+	// Время забора каждого токена в порядке возрастания.
+	log        []time.Time
+	lastRefill time.Time
+}
+
+func New(size int, refillRate refillrate.RefillRate) bucket.IBucket {
+	return &Bucket{
+		size:   size,
+		window: refillRate.GetTime(),
+
+		lastRefill: time.Now(),
+	}
+}
+
+// Restore создает bucket из сохраненного состояния.
+// Время отдельных запросов не сохраняется, поэтому израсходованные токены считаются взятыми в lastRefill.
+func Restore(size int, refillRate refillrate.RefillRate, tokensCount int, lastRefill time.Time) bucket.IBucket {
+	used := max(0, size-tokensCount)
+
+	log := make([]time.Time, used)
+	for i := range log {
+		log[i] = lastRefill
+	}
+
+	return &Bucket{
+		size:   size,
+		window: refillRate.GetTime(),
+
+		log:        log,
+		lastRefill: lastRefill,
+	}
+}
+
+// Resize возвращает bucket с новыми размером и длиной окна и тем же журналом запросов.
+func (b *Bucket) Resize(size int, refillRate refillrate.RefillRate) bucket.IBucket {
+	b.RLock()
+	defer b.RUnlock()
+
+	return &Bucket{
+		size:   size,
+		window: refillRate.GetTime(),
+
+		log:        slices.Clone(b.log),
+		lastRefill: b.lastRefill,
+	}
+}
+
+// Refill удаляет из журнала запросы, вышедшие за пределы окна.
+func (b *Bucket) Refill() {
+	b.Lock()
+
+	b.log = b.log[b.expired(time.Now()):]
+
+	b.Unlock()
+}
+
+func (b *Bucket) GetTokenCount() int {
+	b.RLock()
+	defer b.RUnlock()
+
+	return max(0, b.size-b.used(time.Now()))
+}
+
+func (b *Bucket) GetSize() int {
+	return b.size
+}
+
+func (b *Bucket) GetToken(tokenCount int) {
+	b.Lock()
+
+	now := time.Now()
+	for range tokenCount {
+		b.log = append(b.log, now)
+	}
+	b.lastRefill = now
+
+	b.Unlock()
+}
+
+// PutToken удаляет из журнала последние tokenCount запросов.
+func (b *Bucket) PutToken(tokenCount int) {
+	b.Lock()
+
+	b.log = b.log[:max(0, len(b.log)-tokenCount)]
+
+	b.Unlock()
+}
+
+func (b *Bucket) Reset() {
+	b.Lock()
+
+	b.log = nil
+	b.lastRefill = time.Now()
+
+	b.Unlock()
+}
+
+// GetLastRefill возвращает время последнего изменения журнала.
+func (b *Bucket) GetLastRefill() time.Time {
+	b.RLock()
+	defer b.RUnlock()
+
+	return b.lastRefill
+}
+
+func (b *Bucket) Full() bool {
+	b.RLock()
+	defer b.RUnlock()
+
+	return b.used(time.Now()) == 0
+}
+
+// used возвращает количество запросов в окне, заканчивающемся в now.
+func (b *Bucket) used(now time.Time) int {
+	return len(b.log) - b.expired(now)
+}
+
+// expired возвращает количество запросов в начале журнала, вышедших за пределы окна.
+func (b *Bucket) expired(now time.Time) int {
+	windowStart := now.Add(-b.window)
+
+	return sort.Search(len(b.log), func(i int) bool {
+		return b.log[i].After(windowStart)
+	})
+}
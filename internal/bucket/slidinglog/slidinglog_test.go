@@ -0,0 +1,60 @@
+package slidinglog_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/slidinglog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBucket(t *testing.T) {
+	b := slidinglog.New(10, refillrate.New(1, time.Minute))
+
+	require.Equal(t, 10, b.GetSize())
+	require.Equal(t, 10, b.GetTokenCount())
+	require.True(t, b.Full())
+}
+
+func TestGetTokenAndReset(t *testing.T) {
+	b := slidinglog.New(10, refillrate.New(1, time.Minute))
+
+	b.GetToken(3)
+	require.Equal(t, 7, b.GetTokenCount())
+	require.False(t, b.Full())
+
+	b.Reset()
+	require.Equal(t, 10, b.GetTokenCount())
+	require.True(t, b.Full())
+}
+
+func TestWindowSlides(t *testing.T) {
+	window := 200 * time.Millisecond
+	b := slidinglog.New(3, refillrate.New(1, window))
+
+	b.GetToken(2)
+	time.Sleep(window / 2)
+	b.GetToken(1)
+	require.Equal(t, 0, b.GetTokenCount())
+
+	// первые два запроса вышли из окна, последний - еще нет
+	time.Sleep(window/2 + window/4)
+	b.Refill()
+	require.Equal(t, 2, b.GetTokenCount())
+	require.False(t, b.Full())
+
+	time.Sleep(window / 2)
+	require.Equal(t, 3, b.GetTokenCount())
+	require.True(t, b.Full())
+}
+
+func TestRestore(t *testing.T) {
+	window := 100 * time.Millisecond
+	b := slidinglog.Restore(5, refillrate.New(1, window), 2, time.Now())
+
+	require.Equal(t, 2, b.GetTokenCount())
+
+	time.Sleep(window + window/2)
+	require.Equal(t, 5, b.GetTokenCount())
+}
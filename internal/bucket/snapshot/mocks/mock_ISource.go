@@ -0,0 +1,134 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package snapshot
+
+import (
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/snapshot"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockISource creates a new instance of MockISource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockISource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockISource {
+	mock := &MockISource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockISource is an autogenerated mock type for the ISource type
+type MockISource struct {
+	mock.Mock
+}
+
+type MockISource_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockISource) EXPECT() *MockISource_Expecter {
+	return &MockISource_Expecter{mock: &_m.Mock}
+}
+
+// RestoreSnapshot provides a mock function for the type MockISource
+func (_mock *MockISource) RestoreSnapshot(snapshots snapshot.Snapshots) {
+	_mock.Called(snapshots)
+	return
+}
+
+// MockISource_RestoreSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreSnapshot'
+type MockISource_RestoreSnapshot_Call struct {
+	*mock.Call
+}
+
+// RestoreSnapshot is a helper method to define mock.On call
+//   - snapshots snapshot.Snapshots
+func (_e *MockISource_Expecter) RestoreSnapshot(snapshots interface{}) *MockISource_RestoreSnapshot_Call {
+	return &MockISource_RestoreSnapshot_Call{Call: _e.mock.On("RestoreSnapshot", snapshots)}
+}
+
+func (_c *MockISource_RestoreSnapshot_Call) Run(run func(snapshots snapshot.Snapshots)) *MockISource_RestoreSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 snapshot.Snapshots
+		if args[0] != nil {
+			arg0 = args[0].(snapshot.Snapshots)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockISource_RestoreSnapshot_Call) Return() *MockISource_RestoreSnapshot_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockISource_RestoreSnapshot_Call) RunAndReturn(run func(snapshots snapshot.Snapshots)) *MockISource_RestoreSnapshot_Call {
+	_c.Run(run)
+	return _c
+}
+
+// TakeSnapshot provides a mock function for the type MockISource
+func (_mock *MockISource) TakeSnapshot() (snapshot.Snapshots, snapshot.Snapshots) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakeSnapshot")
+	}
+
+	var r0 snapshot.Snapshots
+	var r1 snapshot.Snapshots
+	if returnFunc, ok := ret.Get(0).(func() (snapshot.Snapshots, snapshot.Snapshots)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() snapshot.Snapshots); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(snapshot.Snapshots)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() snapshot.Snapshots); ok {
+		r1 = returnFunc()
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(snapshot.Snapshots)
+		}
+	}
+	return r0, r1
+}
+
+// MockISource_TakeSnapshot_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakeSnapshot'
+type MockISource_TakeSnapshot_Call struct {
+	*mock.Call
+}
+
+// TakeSnapshot is a helper method to define mock.On call
+func (_e *MockISource_Expecter) TakeSnapshot() *MockISource_TakeSnapshot_Call {
+	return &MockISource_TakeSnapshot_Call{Call: _e.mock.On("TakeSnapshot")}
+}
+
+func (_c *MockISource_TakeSnapshot_Call) Run(run func()) *MockISource_TakeSnapshot_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockISource_TakeSnapshot_Call) Return(changed snapshot.Snapshots, deleted snapshot.Snapshots) *MockISource_TakeSnapshot_Call {
+	_c.Call.Return(changed, deleted)
+	return _c
+}
+
+func (_c *MockISource_TakeSnapshot_Call) RunAndReturn(run func() (snapshot.Snapshots, snapshot.Snapshots)) *MockISource_TakeSnapshot_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,194 @@
+// Code generated by mockery; DO NOT EDIT.
+// github.com/vektra/mockery
+// template: testify
+
+package snapshot
+
+import (
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/snapshot"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewMockIStorage creates a new instance of MockIStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIStorage {
+	mock := &MockIStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIStorage is an autogenerated mock type for the IStorage type
+type MockIStorage struct {
+	mock.Mock
+}
+
+type MockIStorage_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIStorage) EXPECT() *MockIStorage_Expecter {
+	return &MockIStorage_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) Delete(snapshots snapshot.Snapshots) error {
+	ret := _mock.Called(snapshots)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(snapshot.Snapshots) error); ok {
+		r0 = returnFunc(snapshots)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIStorage_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockIStorage_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - snapshots snapshot.Snapshots
+func (_e *MockIStorage_Expecter) Delete(snapshots interface{}) *MockIStorage_Delete_Call {
+	return &MockIStorage_Delete_Call{Call: _e.mock.On("Delete", snapshots)}
+}
+
+func (_c *MockIStorage_Delete_Call) Run(run func(snapshots snapshot.Snapshots)) *MockIStorage_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 snapshot.Snapshots
+		if args[0] != nil {
+			arg0 = args[0].(snapshot.Snapshots)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_Delete_Call) Return(err error) *MockIStorage_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIStorage_Delete_Call) RunAndReturn(run func(snapshots snapshot.Snapshots) error) *MockIStorage_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Load provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) Load() (*snapshot.Snapshots, error) {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Load")
+	}
+
+	var r0 *snapshot.Snapshots
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func() (*snapshot.Snapshots, error)); ok {
+		return returnFunc()
+	}
+	if returnFunc, ok := ret.Get(0).(func() *snapshot.Snapshots); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*snapshot.Snapshots)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func() error); ok {
+		r1 = returnFunc()
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStorage_Load_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Load'
+type MockIStorage_Load_Call struct {
+	*mock.Call
+}
+
+// Load is a helper method to define mock.On call
+func (_e *MockIStorage_Expecter) Load() *MockIStorage_Load_Call {
+	return &MockIStorage_Load_Call{Call: _e.mock.On("Load")}
+}
+
+func (_c *MockIStorage_Load_Call) Run(run func()) *MockIStorage_Load_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockIStorage_Load_Call) Return(snapshots *snapshot.Snapshots, err error) *MockIStorage_Load_Call {
+	_c.Call.Return(snapshots, err)
+	return _c
+}
+
+func (_c *MockIStorage_Load_Call) RunAndReturn(run func() (*snapshot.Snapshots, error)) *MockIStorage_Load_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) Save(snapshots snapshot.Snapshots) error {
+	ret := _mock.Called(snapshots)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(snapshot.Snapshots) error); ok {
+		r0 = returnFunc(snapshots)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIStorage_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockIStorage_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - snapshots snapshot.Snapshots
+func (_e *MockIStorage_Expecter) Save(snapshots interface{}) *MockIStorage_Save_Call {
+	return &MockIStorage_Save_Call{Call: _e.mock.On("Save", snapshots)}
+}
+
+func (_c *MockIStorage_Save_Call) Run(run func(snapshots snapshot.Snapshots)) *MockIStorage_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 snapshot.Snapshots
+		if args[0] != nil {
+			arg0 = args[0].(snapshot.Snapshots)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_Save_Call) Return(err error) *MockIStorage_Save_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIStorage_Save_Call) RunAndReturn(run func(snapshots snapshot.Snapshots) error) *MockIStorage_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -0,0 +1,85 @@
+package snapshot
+
+import "sync"
+
+type snapshotKey struct {
+	limitType string
+	bucketKey string
+}
+
+// Service периодически сохраняет состояние bucket'ов и восстанавливает его при запуске.
+// Изменения, которые не удалось сохранить, остаются в очереди до следующего вызова Flush.
+type Service struct {
+	sync.Mutex
+
+	source  ISource
+	storage IStorage
+
+	changed map[snapshotKey]Snapshot
+	deleted map[snapshotKey]Snapshot
+}
+
+func NewService(source ISource, storage IStorage) *Service {
+	return &Service{
+		source:  source,
+		storage: storage,
+
+		changed: make(map[snapshotKey]Snapshot),
+		deleted: make(map[snapshotKey]Snapshot),
+	}
+}
+
+// Restore загружает сохраненные bucket'ы в лимитер и возвращает их количество.
+func (s *Service) Restore() (int, error) {
+	snapshots, err := s.storage.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	s.source.RestoreSnapshot(*snapshots)
+
+	return len(*snapshots), nil
+}
+
+// Flush сохраняет bucket'ы, изменившиеся с прошлого вызова, и удаляет снимки удаленных bucket'ов.
+func (s *Service) Flush() error {
+	s.Lock()
+	defer s.Unlock()
+
+	changed, deleted := s.source.TakeSnapshot()
+	for _, snapshot := range changed {
+		key := snapshotKey{snapshot.LimitType, snapshot.BucketKey}
+		s.changed[key] = snapshot
+		delete(s.deleted, key)
+	}
+	for _, snapshot := range deleted {
+		key := snapshotKey{snapshot.LimitType, snapshot.BucketKey}
+		s.deleted[key] = snapshot
+		delete(s.changed, key)
+	}
+
+	if len(s.changed) > 0 {
+		if err := s.storage.Save(values(s.changed)); err != nil {
+			return err
+		}
+		s.changed = make(map[snapshotKey]Snapshot)
+	}
+
+	if len(s.deleted) > 0 {
+		if err := s.storage.Delete(values(s.deleted)); err != nil {
+			return err
+		}
+		s.deleted = make(map[snapshotKey]Snapshot)
+	}
+
+	return nil
+}
+
+func values(snapshots map[snapshotKey]Snapshot) Snapshots {
+	result := make(Snapshots, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		result = append(result, snapshot)
+	}
+
+	return result
+}
@@ -0,0 +1,81 @@
+package snapshot_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/snapshot"
+	snapshotmocks "github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/snapshot/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Restore(t *testing.T) {
+	source := snapshotmocks.NewMockISource(t)
+	storage := snapshotmocks.NewMockIStorage(t)
+	service := snapshot.NewService(source, storage)
+
+	snapshots := snapshot.Snapshots{
+		{LimitType: "login", BucketKey: "lucky", Tokens: 1, LastRefill: time.Now()},
+		{LimitType: "ip", BucketKey: "10.0.0.1", Tokens: 5, LastRefill: time.Now()},
+	}
+	storage.EXPECT().Load().Return(&snapshots, nil).Once()
+	source.EXPECT().RestoreSnapshot(snapshots).Return().Once()
+
+	restored, err := service.Restore()
+	require.NoError(t, err)
+	require.Equal(t, 2, restored)
+}
+
+func TestService_Restore_Error(t *testing.T) {
+	source := snapshotmocks.NewMockISource(t)
+	storage := snapshotmocks.NewMockIStorage(t)
+	service := snapshot.NewService(source, storage)
+
+	errDB := errors.New("db error")
+	storage.EXPECT().Load().Return(nil, errDB).Once()
+
+	_, err := service.Restore()
+	require.ErrorIs(t, err, errDB)
+}
+
+func TestService_Flush(t *testing.T) {
+	source := snapshotmocks.NewMockISource(t)
+	storage := snapshotmocks.NewMockIStorage(t)
+	service := snapshot.NewService(source, storage)
+
+	changed := snapshot.Snapshots{{LimitType: "login", BucketKey: "lucky", Tokens: 1}}
+	deleted := snapshot.Snapshots{{LimitType: "ip", BucketKey: "10.0.0.1"}}
+	source.EXPECT().TakeSnapshot().Return(changed, deleted).Once()
+	storage.EXPECT().Save(changed).Return(nil).Once()
+	storage.EXPECT().Delete(deleted).Return(nil).Once()
+
+	require.NoError(t, service.Flush())
+
+	// без изменений хранилище не вызывается
+	source.EXPECT().TakeSnapshot().Return(snapshot.Snapshots{}, snapshot.Snapshots{}).Once()
+	require.NoError(t, service.Flush())
+}
+
+func TestService_Flush_RetryAfterError(t *testing.T) {
+	source := snapshotmocks.NewMockISource(t)
+	storage := snapshotmocks.NewMockIStorage(t)
+	service := snapshot.NewService(source, storage)
+
+	errDB := errors.New("db error")
+	first := snapshot.Snapshots{{LimitType: "login", BucketKey: "lucky", Tokens: 1}}
+	source.EXPECT().TakeSnapshot().Return(first, snapshot.Snapshots{}).Once()
+	storage.EXPECT().Save(mock.Anything).Return(errDB).Once()
+
+	require.ErrorIs(t, service.Flush(), errDB)
+
+	// несохраненные изменения объединяются с новыми, удаление отменяет сохранение
+	second := snapshot.Snapshots{{LimitType: "login", BucketKey: "root", Tokens: 2}}
+	deleted := snapshot.Snapshots{{LimitType: "login", BucketKey: "lucky"}}
+	source.EXPECT().TakeSnapshot().Return(second, deleted).Once()
+	storage.EXPECT().Save(second).Return(nil).Once()
+	storage.EXPECT().Delete(deleted).Return(nil).Once()
+
+	require.NoError(t, service.Flush())
+}
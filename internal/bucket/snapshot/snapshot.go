@@ -0,0 +1,27 @@
+package snapshot
+
+import "time"
+
+type Snapshots []Snapshot
+
+// Snapshot сохраненное состояние bucket'а лимитера.
+type Snapshot struct {
+	LimitType  string
+	BucketKey  string
+	Tokens     int
+	LastRefill time.Time
+}
+
+// IStorage хранилище снимков bucket'ов.
+type IStorage interface {
+	Load() (*Snapshots, error)
+	Save(snapshots Snapshots) error
+	Delete(snapshots Snapshots) error
+}
+
+// ISource лимитер, bucket'ы которого сохраняются в снимки.
+type ISource interface {
+	// TakeSnapshot возвращает bucket'ы, изменившиеся с прошлого вызова, и удаленные bucket'ы.
+	TakeSnapshot() (changed Snapshots, deleted Snapshots)
+	RestoreSnapshot(snapshots Snapshots)
+}
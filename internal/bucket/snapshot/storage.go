@@ -0,0 +1,138 @@
+package snapshot
+
+import (
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/storage/postgres"
+)
+
+// batchSize ограничивает количество строк в одном запросе (лимит параметров PostgreSQL - 65535).
+const batchSize = 1000
+
+type sqlEntity struct {
+	LimitType  string    `db:"limit_type"`
+	BucketKey  string    `db:"bucket_key"`
+	Tokens     int       `db:"tokens"`
+	LastRefill time.Time `db:"last_refill"`
+}
+
+type Storage struct {
+	*postgres.Storage
+}
+
+func NewStorage(storage *postgres.Storage) *Storage {
+	return &Storage{storage}
+}
+
+func (s *Storage) Load() (*Snapshots, error) {
+	query := `
+		SELECT limit_type, bucket_key, tokens, last_refill
+		FROM bucket_snapshot
+	`
+
+	stmt, err := s.DB.PreparexContext(s.Ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var rows []sqlEntity
+
+	err = stmt.SelectContext(
+		s.Ctx,
+		&rows,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(Snapshots, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, *s.sqlEntityToEntity(&r))
+	}
+
+	return &result, nil
+}
+
+func (s *Storage) Save(snapshots Snapshots) error {
+	query := `
+		INSERT INTO bucket_snapshot(limit_type, bucket_key, tokens, last_refill)
+		VALUES (:limit_type, :bucket_key, :tokens, :last_refill)
+		ON CONFLICT (limit_type, bucket_key)
+			DO UPDATE SET tokens = EXCLUDED.tokens, last_refill = EXCLUDED.last_refill
+	`
+
+	rows := make([]sqlEntity, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		rows = append(rows, *s.entityToSQLEntity(&snapshot))
+	}
+
+	return s.inTx(func(tx *sqlx.Tx) error {
+		for start := 0; start < len(rows); start += batchSize {
+			end := min(start+batchSize, len(rows))
+			if _, err := tx.NamedExecContext(s.Ctx, query, rows[start:end]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Storage) Delete(snapshots Snapshots) error {
+	query := `
+		DELETE FROM bucket_snapshot
+		WHERE limit_type = :limit_type
+			AND bucket_key = :bucket_key
+	`
+
+	return s.inTx(func(tx *sqlx.Tx) error {
+		stmt, err := tx.PrepareNamedContext(s.Ctx, query)
+		if err != nil {
+			return err
+		}
+		defer stmt.Close()
+
+		for _, snapshot := range snapshots {
+			if _, err = stmt.ExecContext(s.Ctx, s.entityToSQLEntity(&snapshot)); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *Storage) inTx(f func(tx *sqlx.Tx) error) error {
+	tx, err := s.DB.BeginTxx(s.Ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err = f(tx); err != nil {
+		_ = tx.Rollback()
+
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s *Storage) sqlEntityToEntity(se *sqlEntity) *Snapshot {
+	return &Snapshot{
+		LimitType:  se.LimitType,
+		BucketKey:  se.BucketKey,
+		Tokens:     se.Tokens,
+		LastRefill: se.LastRefill,
+	}
+}
+
+func (s *Storage) entityToSQLEntity(e *Snapshot) *sqlEntity {
+	return &sqlEntity{
+		LimitType:  e.LimitType,
+		BucketKey:  e.BucketKey,
+		Tokens:     e.Tokens,
+		LastRefill: e.LastRefill,
+	}
+}
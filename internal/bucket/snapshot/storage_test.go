@@ -0,0 +1,109 @@
+package snapshot_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/snapshot"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/storage/postgres"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStorage(t *testing.T) (*snapshot.Storage, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+
+	pg := &postgres.Storage{
+		DB:  sqlxDB,
+		Ctx: context.Background(),
+	}
+
+	return snapshot.NewStorage(pg), mock
+}
+
+func TestStorage_Load(t *testing.T) {
+	storage, mock := newTestStorage(t)
+	lastRefill := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	rows := sqlmock.NewRows([]string{"limit_type", "bucket_key", "tokens", "last_refill"}).
+		AddRow("login", "lucky", 3, lastRefill).
+		AddRow("ip", "10.0.0.1", 0, lastRefill)
+
+	mock.ExpectPrepare("SELECT (.+) FROM bucket_snapshot").
+		ExpectQuery().
+		WillReturnRows(rows)
+
+	result, err := storage.Load()
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+
+	require.Equal(t, "login", (*result)[0].LimitType)
+	require.Equal(t, "lucky", (*result)[0].BucketKey)
+	require.Equal(t, 3, (*result)[0].Tokens)
+	require.Equal(t, lastRefill, (*result)[0].LastRefill)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_Save(t *testing.T) {
+	storage, mock := newTestStorage(t)
+	lastRefill := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO bucket_snapshot").
+		WithArgs("login", "lucky", 3, lastRefill, "ip", "10.0.0.1", 0, lastRefill).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := storage.Save(snapshot.Snapshots{
+		{LimitType: "login", BucketKey: "lucky", Tokens: 3, LastRefill: lastRefill},
+		{LimitType: "ip", BucketKey: "10.0.0.1", Tokens: 0, LastRefill: lastRefill},
+	})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_Save_Error(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO bucket_snapshot").
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	err := storage.Save(snapshot.Snapshots{{LimitType: "login", BucketKey: "lucky"}})
+
+	require.Error(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_Delete(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	mock.ExpectBegin()
+	prepare := mock.ExpectPrepare("DELETE FROM bucket_snapshot")
+	prepare.ExpectExec().
+		WithArgs("login", "lucky").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	prepare.ExpectExec().
+		WithArgs("ip", "10.0.0.1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := storage.Delete(snapshot.Snapshots{
+		{LimitType: "login", BucketKey: "lucky"},
+		{LimitType: "ip", BucketKey: "10.0.0.1"},
+	})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,220 @@
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/slidingcounter"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/slidinglog"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/token"
+)
+
+// Store хранилище bucket'ов в памяти процесса.
+// Состояние не разделяется между репликами и теряется при перезапуске, если не сохраняется через Dirty/Restore.
+type Store struct {
+	sync.Mutex
+
+	buckets map[string]*bucket.IBucket
+
+	// Конструкторы bucket'ов выбранного алгоритма.
+	newBucket     func(size int, refillRate refillrate.RefillRate) bucket.IBucket
+	restoreBucket func(size int, refillRate refillrate.RefillRate, tokensCount int, lastRefill time.Time) bucket.IBucket
+
+	// Ключи bucket'ов, измененных и удаленных с момента последнего вызова Dirty.
+	dirty   map[string]struct{}
+	deleted map[string]struct{}
+}
+
+func New() *Store {
+	return NewWithAlgorithm(bucket.TokenBucket)
+}
+
+// NewWithAlgorithm создает хранилище, bucket'ы которого работают по алгоритму algorithm.
+func NewWithAlgorithm(algorithm bucket.Algorithm) *Store {
+	s := &Store{
+		buckets: make(map[string]*bucket.IBucket),
+		dirty:   make(map[string]struct{}),
+		deleted: make(map[string]struct{}),
+
+		newBucket:     token.New,
+		restoreBucket: token.Restore,
+	}
+
+	switch algorithm {
+	case bucket.SlidingLog:
+		s.newBucket, s.restoreBucket = slidinglog.New, slidinglog.Restore
+	case bucket.SlidingCounter:
+		s.newBucket, s.restoreBucket = slidingcounter.New, slidingcounter.Restore
+	case bucket.TokenBucket:
+	}
+
+	return s
+}
+
+func (s *Store) Take(key string, size int, refillRate refillrate.RefillRate, cost int) (bool, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	b := s.initBucket(key, size, refillRate)
+	s.markDirty(key)
+
+	(*b).Refill()
+
+	if (*b).GetTokenCount() > 0 && cost <= (*b).GetTokenCount() {
+		(*b).GetToken(cost)
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (s *Store) Tokens(key string, size int, refillRate refillrate.RefillRate) (int, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	// Чтение не меняет сохраняемое состояние bucket'а: пополнение восстанавливается по времени.
+	b := s.initBucket(key, size, refillRate)
+	(*b).Refill()
+
+	return (*b).GetTokenCount(), nil
+}
+
+// Refund возвращает cost токенов в bucket по ключу key, если bucket существует и поддерживает возврат.
+func (s *Store) Refund(key string, _ int, _ refillrate.RefillRate, cost int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	b, found := s.buckets[key]
+	if !found {
+		return nil
+	}
+
+	refundable, ok := (*b).(bucket.IRefundableBucket)
+	if !ok {
+		return nil
+	}
+
+	refundable.PutToken(cost)
+	s.markDirty(key)
+
+	return nil
+}
+
+func (s *Store) Reset(key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	b, found := s.buckets[key]
+	if !found {
+		return nil
+	}
+
+	(*b).Reset()
+	s.markDirty(key)
+
+	return nil
+}
+
+func (s *Store) Delete(key string) error {
+	s.Lock()
+
+	delete(s.buckets, key)
+	delete(s.dirty, key)
+	s.deleted[key] = struct{}{}
+
+	s.Unlock()
+
+	return nil
+}
+
+func (s *Store) Dirty() (map[string]bucket.State, []string) {
+	s.Lock()
+	defer s.Unlock()
+
+	changed := make(map[string]bucket.State, len(s.dirty))
+	for key := range s.dirty {
+		b, found := s.buckets[key]
+		if !found {
+			continue
+		}
+
+		changed[key] = bucket.State{
+			Tokens:     (*b).GetTokenCount(),
+			LastRefill: (*b).GetLastRefill(),
+		}
+	}
+
+	deleted := make([]string, 0, len(s.deleted))
+	for key := range s.deleted {
+		deleted = append(deleted, key)
+	}
+
+	s.dirty = make(map[string]struct{})
+	s.deleted = make(map[string]struct{})
+
+	return changed, deleted
+}
+
+func (s *Store) Restore(key string, size int, refillRate refillrate.RefillRate, state bucket.State) {
+	s.Lock()
+
+	restored := s.restoreBucket(size, refillRate, state.Tokens, state.LastRefill)
+	s.buckets[key] = &restored
+
+	s.Unlock()
+}
+
+func (s *Store) Buckets() map[string]*bucket.IBucket {
+	s.Lock()
+	defer s.Unlock()
+
+	buckets := make(map[string]*bucket.IBucket, len(s.buckets))
+	for key, b := range s.buckets {
+		buckets[key] = b
+	}
+
+	return buckets
+}
+
+// Resize пополняет bucket'ы по прежней скорости и переносит израсходованную часть на новый размер.
+// Bucket'ы, которые умеют менять размер сами (bucket.IResizableBucket), сохраняют свое состояние целиком.
+func (s *Store) Resize(size int, refillRate refillrate.RefillRate) {
+	s.Lock()
+	defer s.Unlock()
+
+	for key, b := range s.buckets {
+		(*b).Refill()
+
+		if resizable, ok := (*b).(bucket.IResizableBucket); ok {
+			resized := resizable.Resize(size, refillRate)
+			s.buckets[key] = &resized
+			s.markDirty(key)
+
+			continue
+		}
+
+		tokens := max(0, (*b).GetTokenCount()+size-(*b).GetSize())
+		resized := s.restoreBucket(size, refillRate, tokens, (*b).GetLastRefill())
+		s.buckets[key] = &resized
+		s.markDirty(key)
+	}
+}
+
+func (s *Store) markDirty(key string) {
+	s.dirty[key] = struct{}{}
+	delete(s.deleted, key)
+}
+
+func (s *Store) initBucket(key string, size int, refillRate refillrate.RefillRate) *bucket.IBucket {
+	b, found := s.buckets[key]
+	if !found {
+		newBucket := s.newBucket(size, refillRate)
+		b = &newBucket
+		s.buckets[key] = b
+	}
+
+	return b
+}
@@ -0,0 +1,160 @@
+package memory_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/store/memory"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Take(t *testing.T) {
+	store := memory.New()
+	refillRate := refillrate.New(1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		taken, err := store.Take("lucky", 3, refillRate, 1)
+		require.NoError(t, err)
+		require.True(t, taken)
+	}
+
+	taken, err := store.Take("lucky", 3, refillRate, 1)
+	require.NoError(t, err)
+	require.False(t, taken)
+
+	tokens, err := store.Tokens("other", 3, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 3, tokens)
+	require.Len(t, store.Buckets(), 2)
+}
+
+func TestStore_ResetAndDelete(t *testing.T) {
+	store := memory.New()
+	refillRate := refillrate.New(1, time.Hour)
+
+	// reset несуществующего bucket'а не создает его
+	require.NoError(t, store.Reset("lucky"))
+	require.Empty(t, store.Buckets())
+
+	_, err := store.Take("lucky", 3, refillRate, 3)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Reset("lucky"))
+	tokens, err := store.Tokens("lucky", 3, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 3, tokens)
+
+	require.NoError(t, store.Delete("lucky"))
+	require.Empty(t, store.Buckets())
+}
+
+func TestStore_Refund(t *testing.T) {
+	refillRate := refillrate.New(1, time.Hour)
+
+	for _, algorithm := range []bucket.Algorithm{bucket.TokenBucket, bucket.SlidingLog, bucket.SlidingCounter} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			store := memory.NewWithAlgorithm(algorithm)
+
+			// возврат в несуществующий bucket не создает его
+			require.NoError(t, store.Refund("lucky", 3, refillRate, 1))
+			require.Empty(t, store.Buckets())
+
+			_, err := store.Take("lucky", 3, refillRate, 2)
+			require.NoError(t, err)
+			store.Dirty()
+
+			require.NoError(t, store.Refund("lucky", 3, refillRate, 1))
+			tokens, err := store.Tokens("lucky", 3, refillRate)
+			require.NoError(t, err)
+			require.Equal(t, 2, tokens)
+
+			changed, _ := store.Dirty()
+			require.Equal(t, 2, changed["lucky"].Tokens)
+
+			// возврат не превышает размер bucket'а
+			require.NoError(t, store.Refund("lucky", 3, refillRate, 5))
+			tokens, err = store.Tokens("lucky", 3, refillRate)
+			require.NoError(t, err)
+			require.Equal(t, 3, tokens)
+		})
+	}
+}
+
+func TestStore_DirtyAndRestore(t *testing.T) {
+	store := memory.New()
+	refillRate := refillrate.New(1, time.Hour)
+
+	_, err := store.Take("lucky", 3, refillRate, 2)
+	require.NoError(t, err)
+	_, err = store.Take("root", 3, refillRate, 1)
+	require.NoError(t, err)
+	require.NoError(t, store.Delete("root"))
+
+	changed, deleted := store.Dirty()
+	require.Len(t, changed, 1)
+	require.Equal(t, 1, changed["lucky"].Tokens)
+	require.Equal(t, []string{"root"}, deleted)
+
+	// повторный вызов возвращает только новые изменения, чтение остатка изменением не считается
+	_, err = store.Tokens("lucky", 3, refillRate)
+	require.NoError(t, err)
+	changed, deleted = store.Dirty()
+	require.Empty(t, changed)
+	require.Empty(t, deleted)
+
+	restored := memory.New()
+	restored.Restore("lucky", 3, refillRate, bucket.State{Tokens: 1, LastRefill: time.Now()})
+	tokens, err := restored.Tokens("lucky", 3, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 1, tokens)
+}
+
+func TestStore_Resize(t *testing.T) {
+	store := memory.New()
+	refillRate := refillrate.New(1, time.Hour)
+
+	_, err := store.Take("lucky", 5, refillRate, 2)
+	require.NoError(t, err)
+	_, err = store.Take("looser", 5, refillRate, 5)
+	require.NoError(t, err)
+	store.Dirty()
+
+	// израсходованная часть сохраняется
+	store.Resize(10, refillRate)
+	tokens, err := store.Tokens("lucky", 10, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 8, tokens)
+
+	// при уменьшении токенов не бывает меньше нуля
+	store.Resize(2, refillRate)
+	tokens, err = store.Tokens("looser", 2, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 0, tokens)
+
+	changed, _ := store.Dirty()
+	require.Len(t, changed, 2)
+}
+
+func TestStore_ResizeSlidingLog(t *testing.T) {
+	store := memory.NewWithAlgorithm(bucket.SlidingLog)
+	window := refillrate.New(1, 200*time.Millisecond)
+
+	_, err := store.Take("lucky", 3, window, 1)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	_, err = store.Take("lucky", 3, window, 1)
+	require.NoError(t, err)
+
+	// время запросов журнала сохраняется: первый запрос выходит из окна раньше второго
+	store.Resize(5, window)
+	tokens, err := store.Tokens("lucky", 5, window)
+	require.NoError(t, err)
+	require.Equal(t, 3, tokens)
+
+	time.Sleep(150 * time.Millisecond)
+	tokens, err = store.Tokens("lucky", 5, window)
+	require.NoError(t, err)
+	require.Equal(t, 4, tokens)
+}
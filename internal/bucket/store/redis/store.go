@@ -0,0 +1,268 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// takeScript атомарно пополняет bucket и забирает из него токены на стороне сервера.
+// Логика пополнения повторяет token.Bucket: добавляются только целые токены,
+// время последнего пополнения сдвигается лишь тогда, когда токены были добавлены.
+// Отсутствующий ключ соответствует полному bucket'у, поэтому ключ живет ровно столько,
+// сколько нужно для полного пополнения, и затем удаляется самим сервером.
+// Вместе с токенами хранится размер bucket'а: при его изменении израсходованная часть сохраняется.
+//
+// Отрицательная стоимость возвращает токены в bucket, но не больше его размера.
+//
+// KEYS[1] - ключ bucket'а.
+// ARGV: размер, количество токенов пополнения, период пополнения (мс), стоимость запроса.
+// Возвращает {1|0 - удалось ли забрать токены, количество токенов после операции}.
+var takeScript = goredis.NewScript(`
+local size = tonumber(ARGV[1])
+local count = tonumber(ARGV[2])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts', 'size')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+local storedSize = tonumber(state[3])
+if tokens == nil or ts == nil then
+	tokens = size
+	ts = now
+elseif storedSize ~= nil and storedSize ~= size then
+	tokens = math.max(0, math.min(tokens + size - storedSize, size))
+end
+
+if period > 0 and count > 0 then
+	local add = math.floor((now - ts) * count / period)
+	if add > 0 then
+		tokens = math.min(tokens + add, size)
+		ts = now
+	end
+end
+
+local taken = 0
+if cost > 0 and tokens > 0 and cost <= tokens then
+	tokens = tokens - cost
+	taken = 1
+elseif cost < 0 then
+	tokens = math.min(tokens - cost, size)
+end
+
+if tokens >= size then
+	redis.call('DEL', KEYS[1])
+	return {taken, tokens}
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tokens, 'ts', ts, 'size', size)
+local ttl = period
+if count > 0 then
+	ttl = math.ceil((size - tokens) * period / count)
+end
+redis.call('PEXPIRE', KEYS[1], math.max(ttl, 1))
+
+return {taken, tokens}
+`)
+
+// slidingLogScript скользящее окно по журналу запросов (см. slidinglog.Bucket) на основе sorted set.
+// Каждый забранный токен - элемент со временем забора в качестве score. Элементы старше окна удаляются,
+// ключ живет, пока в окне есть хотя бы один запрос.
+// Имя элемента - время и номер, которого еще нет в журнале: после возврата токенов или нескольких запросов
+// в одну миллисекунду размер журнала повторяется, и ZADD с тем же именем обновил бы элемент вместо добавления.
+// Номер проверяется в самом журнале, а не отдельным счетчиком: ключ bucket'а - значение identity,
+// и любой соседний ключ может совпасть с ключом другого bucket'а.
+// Отрицательная стоимость удаляет последние элементы журнала.
+//
+// KEYS[1] - ключ bucket'а.
+// ARGV: размер, количество токенов пополнения (не используется), длина окна (мс), стоимость запроса.
+// Возвращает {1|0 - удалось ли забрать токены, количество токенов после операции}.
+var slidingLogScript = goredis.NewScript(`
+local size = tonumber(ARGV[1])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - period)
+local used = redis.call('ZCARD', KEYS[1])
+
+if cost < 0 then
+	redis.call('ZPOPMAX', KEYS[1], -cost)
+	used = redis.call('ZCARD', KEYS[1])
+	cost = 0
+end
+
+local taken = 0
+if used + cost <= size then
+	taken = 1
+	if cost > 0 then
+		local seq = used
+		for _ = 1, cost do
+			local member
+			repeat
+				seq = seq + 1
+				member = now .. '-' .. seq
+			until not redis.call('ZSCORE', KEYS[1], member)
+			redis.call('ZADD', KEYS[1], now, member)
+		end
+		used = used + cost
+		redis.call('PEXPIRE', KEYS[1], math.max(period, 1))
+	end
+end
+
+return {taken, math.max(size - used, 0)}
+`)
+
+// slidingCounterScript скользящее окно по счетчикам текущего и предыдущего окна (см. slidingcounter.Bucket).
+// Ключ удаляется, когда оба счетчика обнулились, иначе живет до конца следующего окна.
+// Отрицательная стоимость уменьшает счетчик текущего окна.
+//
+// KEYS[1] - ключ bucket'а.
+// ARGV: размер, количество токенов пополнения (не используется), длина окна (мс), стоимость запроса.
+// Возвращает {1|0 - удалось ли забрать токены, количество токенов после операции}.
+var slidingCounterScript = goredis.NewScript(`
+local size = tonumber(ARGV[1])
+local period = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'start', 'prev', 'curr')
+local start = tonumber(state[1]) or now
+local prev = tonumber(state[2]) or 0
+local curr = tonumber(state[3]) or 0
+
+local elapsed = now - start
+if period > 0 and elapsed >= period then
+	local windows = math.floor(elapsed / period)
+	if windows == 1 then
+		prev = curr
+	else
+		prev = 0
+	end
+	curr = 0
+	start = start + windows * period
+	elapsed = now - start
+end
+
+local used = curr
+if period > 0 then
+	used = used + math.ceil(prev * (period - elapsed) / period)
+end
+
+local taken = 0
+if cost < 0 then
+	local refund = math.min(-cost, curr)
+	curr = curr - refund
+	used = used - refund
+elseif used + cost <= size then
+	taken = 1
+	curr = curr + cost
+	used = used + cost
+end
+
+if prev + curr == 0 then
+	redis.call('DEL', KEYS[1])
+else
+	redis.call('HSET', KEYS[1], 'start', start, 'prev', prev, 'curr', curr)
+	redis.call('PEXPIRE', KEYS[1], math.max(start + 2 * period - now, 1))
+end
+
+return {taken, math.max(size - used, 0)}
+`)
+
+// Store хранилище bucket'ов в Redis (или любом сервере с протоколом RESP и поддержкой Lua).
+// Позволяет нескольким репликам сервиса делить одни и те же bucket'ы.
+type Store struct {
+	client goredis.UniversalClient
+	prefix string
+	ctx    context.Context
+	script *goredis.Script
+}
+
+func New(ctx context.Context, client goredis.UniversalClient, prefix string) *Store {
+	return NewWithAlgorithm(ctx, client, prefix, bucket.TokenBucket)
+}
+
+// NewWithAlgorithm создает хранилище, bucket'ы которого работают по алгоритму algorithm.
+func NewWithAlgorithm(
+	ctx context.Context,
+	client goredis.UniversalClient,
+	prefix string,
+	algorithm bucket.Algorithm,
+) *Store {
+	script := takeScript
+	switch algorithm {
+	case bucket.SlidingLog:
+		script = slidingLogScript
+	case bucket.SlidingCounter:
+		script = slidingCounterScript
+	case bucket.TokenBucket:
+	}
+
+	return &Store{
+		client: client,
+		prefix: prefix,
+		ctx:    ctx,
+		script: script,
+	}
+}
+
+func (s *Store) Take(key string, size int, refillRate refillrate.RefillRate, cost int) (bool, error) {
+	taken, _, err := s.run(key, size, refillRate, cost)
+
+	return taken, err
+}
+
+func (s *Store) Tokens(key string, size int, refillRate refillrate.RefillRate) (int, error) {
+	_, tokens, err := s.run(key, size, refillRate, 0)
+
+	return tokens, err
+}
+
+// Refund возвращает в bucket cost токенов, забранных Take.
+func (s *Store) Refund(key string, size int, refillRate refillrate.RefillRate, cost int) error {
+	_, _, err := s.run(key, size, refillRate, -cost)
+
+	return err
+}
+
+// Reset удаляет ключ: отсутствующий bucket считается полным.
+func (s *Store) Reset(key string) error {
+	return s.Delete(key)
+}
+
+func (s *Store) Delete(key string) error {
+	return s.client.Del(s.ctx, s.prefix+key).Err()
+}
+
+// Buckets всегда возвращает пустой набор: устаревшие ключи удаляются сервером по TTL.
+func (s *Store) Buckets() map[string]*bucket.IBucket {
+	return map[string]*bucket.IBucket{}
+}
+
+func (s *Store) run(key string, size int, refillRate refillrate.RefillRate, cost int) (bool, int, error) {
+	result, err := s.script.Run(
+		s.ctx,
+		s.client,
+		[]string{s.prefix + key},
+		size,
+		refillRate.GetCount(),
+		refillRate.GetTime().Milliseconds(),
+		cost,
+	).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+
+	return result[0] == 1, int(result[1]), nil
+}
@@ -0,0 +1,283 @@
+package redis_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	redisstore "github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/store/redis"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestStore(t *testing.T) (*redisstore.Store, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return redisstore.New(context.Background(), client, "test:"), server
+}
+
+func TestStore_Take(t *testing.T) {
+	store, _ := newTestStore(t)
+	refillRate := refillrate.New(1, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		taken, err := store.Take("lucky", 3, refillRate, 1)
+		require.NoError(t, err)
+		require.True(t, taken)
+	}
+
+	taken, err := store.Take("lucky", 3, refillRate, 1)
+	require.NoError(t, err)
+	require.False(t, taken)
+
+	// другой ключ не затронут
+	tokens, err := store.Tokens("other", 3, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 3, tokens)
+}
+
+func TestStore_TooExpensiveRequest(t *testing.T) {
+	store, _ := newTestStore(t)
+	refillRate := refillrate.New(1, time.Hour)
+
+	taken, err := store.Take("lucky", 3, refillRate, 4)
+	require.NoError(t, err)
+	require.False(t, taken)
+
+	tokens, err := store.Tokens("lucky", 3, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 3, tokens)
+}
+
+func TestStore_Refill(t *testing.T) {
+	store, server := newTestStore(t)
+	refillRate := refillrate.New(2, time.Second)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	taken, err := store.Take("lucky", 10, refillRate, 10)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	// 0.4 сек - ни одного целого токена
+	server.SetTime(now.Add(400 * time.Millisecond))
+	tokens, err := store.Tokens("lucky", 10, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 0, tokens)
+
+	// 1.5 сек - 3 токена
+	server.SetTime(now.Add(1500 * time.Millisecond))
+	tokens, err = store.Tokens("lucky", 10, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 3, tokens)
+
+	// пополнение не превышает размер
+	server.SetTime(now.Add(time.Hour))
+	tokens, err = store.Tokens("lucky", 10, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 10, tokens)
+}
+
+func TestStore_ResetAndDelete(t *testing.T) {
+	store, server := newTestStore(t)
+	refillRate := refillrate.New(1, time.Hour)
+
+	_, err := store.Take("lucky", 3, refillRate, 3)
+	require.NoError(t, err)
+	require.True(t, server.Exists("test:lucky"))
+
+	require.NoError(t, store.Reset("lucky"))
+	require.False(t, server.Exists("test:lucky"))
+
+	tokens, err := store.Tokens("lucky", 3, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 3, tokens)
+
+	_, err = store.Take("lucky", 3, refillRate, 1)
+	require.NoError(t, err)
+	require.NoError(t, store.Delete("lucky"))
+	require.False(t, server.Exists("test:lucky"))
+}
+
+func TestStore_KeyExpiresWhenFull(t *testing.T) {
+	store, server := newTestStore(t)
+	refillRate := refillrate.New(1, time.Second)
+
+	_, err := store.Take("lucky", 3, refillRate, 2)
+	require.NoError(t, err)
+	require.Equal(t, 2*time.Second, server.TTL("test:lucky"))
+
+	server.FastForward(2 * time.Second)
+	require.False(t, server.Exists("test:lucky"))
+	require.Empty(t, store.Buckets())
+}
+
+func TestStore_SharedBetweenInstances(t *testing.T) {
+	store, server := newTestStore(t)
+	refillRate := refillrate.New(1, time.Hour)
+
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	defer client.Close()
+	replica := redisstore.New(context.Background(), client, "test:")
+
+	taken, err := store.Take("lucky", 2, refillRate, 1)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	taken, err = replica.Take("lucky", 2, refillRate, 1)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	taken, err = store.Take("lucky", 2, refillRate, 1)
+	require.NoError(t, err)
+	require.False(t, taken)
+}
+
+func TestStore_SizeChanged(t *testing.T) {
+	store, _ := newTestStore(t)
+	refillRate := refillrate.New(1, time.Hour)
+
+	_, err := store.Take("lucky", 5, refillRate, 2)
+	require.NoError(t, err)
+	_, err = store.Take("looser", 5, refillRate, 5)
+	require.NoError(t, err)
+
+	// израсходованная часть сохраняется
+	tokens, err := store.Tokens("lucky", 10, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 8, tokens)
+
+	// при уменьшении токенов не бывает меньше нуля
+	tokens, err = store.Tokens("looser", 2, refillRate)
+	require.NoError(t, err)
+	require.Equal(t, 0, tokens)
+}
+
+func TestStore_SlidingLog(t *testing.T) {
+	server := miniredis.RunT(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.SetTime(now)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	store := redisstore.NewWithAlgorithm(context.Background(), client, "test:", bucket.SlidingLog)
+	window := refillrate.New(1, time.Minute)
+
+	taken, err := store.Take("lucky", 3, window, 2)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	server.SetTime(now.Add(30 * time.Second))
+	taken, err = store.Take("lucky", 3, window, 1)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	taken, err = store.Take("lucky", 3, window, 1)
+	require.NoError(t, err)
+	require.False(t, taken)
+
+	// первые два запроса вышли из окна
+	server.SetTime(now.Add(61 * time.Second))
+	tokens, err := store.Tokens("lucky", 3, window)
+	require.NoError(t, err)
+	require.Equal(t, 2, tokens)
+}
+
+func TestStore_SlidingLogUniqueMembers(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	store := redisstore.NewWithAlgorithm(context.Background(), client, "test:", bucket.SlidingLog)
+	window := refillrate.New(1, time.Minute)
+
+	// все запросы в одну миллисекунду: после возврата последним по имени удаляется элемент с номером 9,
+	// и следующий запрос не должен перезаписать элемент с номером 10
+	for range 10 {
+		taken, err := store.Take("lucky", 20, window, 1)
+		require.NoError(t, err)
+		require.True(t, taken)
+	}
+	require.NoError(t, store.Refund("lucky", 20, window, 1))
+
+	taken, err := store.Take("lucky", 20, window, 2)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	tokens, err := store.Tokens("lucky", 20, window)
+	require.NoError(t, err)
+	require.Equal(t, 9, tokens)
+}
+
+func TestStore_SlidingCounter(t *testing.T) {
+	server := miniredis.RunT(t)
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	server.SetTime(now)
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	store := redisstore.NewWithAlgorithm(context.Background(), client, "test:", bucket.SlidingCounter)
+	window := refillrate.New(1, time.Minute)
+
+	taken, err := store.Take("lucky", 10, window, 10)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	taken, err = store.Take("lucky", 10, window, 1)
+	require.NoError(t, err)
+	require.False(t, taken)
+
+	// середина следующего окна: половина запросов предыдущего окна еще учитывается
+	server.SetTime(now.Add(90 * time.Second))
+	tokens, err := store.Tokens("lucky", 10, window)
+	require.NoError(t, err)
+	require.Equal(t, 5, tokens)
+
+	// через два окна счетчики обнуляются
+	server.SetTime(now.Add(3 * time.Minute))
+	tokens, err = store.Tokens("lucky", 10, window)
+	require.NoError(t, err)
+	require.Equal(t, 10, tokens)
+	require.False(t, server.Exists("test:lucky"))
+}
+
+func TestStore_Refund(t *testing.T) {
+	server := miniredis.RunT(t)
+	server.SetTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	client := goredis.NewClient(&goredis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	refillRate := refillrate.New(1, time.Hour)
+	for _, algorithm := range []bucket.Algorithm{bucket.TokenBucket, bucket.SlidingLog, bucket.SlidingCounter} {
+		t.Run(string(algorithm), func(t *testing.T) {
+			store := redisstore.NewWithAlgorithm(context.Background(), client, string(algorithm)+":", algorithm)
+
+			taken, err := store.Take("lucky", 3, refillRate, 2)
+			require.NoError(t, err)
+			require.True(t, taken)
+
+			require.NoError(t, store.Refund("lucky", 3, refillRate, 1))
+			tokens, err := store.Tokens("lucky", 3, refillRate)
+			require.NoError(t, err)
+			require.Equal(t, 2, tokens)
+
+			// возврат не превышает размер bucket'а, полный bucket удаляется
+			require.NoError(t, store.Refund("lucky", 3, refillRate, 5))
+			tokens, err = store.Tokens("lucky", 3, refillRate)
+			require.NoError(t, err)
+			require.Equal(t, 3, tokens)
+			require.False(t, server.Exists(string(algorithm)+":lucky"))
+		})
+	}
+}
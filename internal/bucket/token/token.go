@@ -28,6 +28,18 @@ func New(size int, refillRate refillrate.RefillRate) bucket.IBucket {
 	}
 }
 
+// Restore создает bucket из сохраненного состояния.
+// Токены, накопившиеся с момента lastRefill, будут добавлены при следующем вызове Refill.
+func Restore(size int, refillRate refillrate.RefillRate, tokensCount int, lastRefill time.Time) bucket.IBucket {
+	return &Bucket{
+		size:       size,
+		refillRate: refillRate,
+
+		tokensCount: min(tokensCount, size),
+		lastRefill:  lastRefill,
+	}
+}
+
 func (b *Bucket) Refill() {
 	b.Lock()
 
@@ -62,6 +74,14 @@ func (b *Bucket) GetToken(tokenCount int) {
 	b.Unlock()
 }
 
+func (b *Bucket) PutToken(tokenCount int) {
+	b.Lock()
+
+	b.tokensCount = min(b.tokensCount+tokenCount, b.size)
+
+	b.Unlock()
+}
+
 func (b *Bucket) Reset() {
 	b.Lock()
 
@@ -99,3 +99,22 @@ func TestFull(t *testing.T) {
 	b.GetToken(1)
 	require.False(t, b.Full())
 }
+
+func TestRestoreAppliesDowntimeRefill(t *testing.T) {
+	refill := refillrate.New(1, time.Second)
+	b := token.Restore(10, refill, 2, time.Now().Add(-3*time.Second))
+
+	require.Equal(t, 2, b.GetTokenCount())
+
+	b.Refill()
+
+	require.Equal(t, 5, b.GetTokenCount())
+}
+
+func TestRestoreDoesNotOverflow(t *testing.T) {
+	refill := refillrate.New(1, time.Second)
+	b := token.Restore(5, refill, 8, time.Now())
+
+	require.Equal(t, 5, b.GetTokenCount())
+	require.True(t, b.Full())
+}
@@ -52,6 +52,72 @@ type Config struct {
 			TTL      time.Duration `default:"600s" yaml:"ttl" env:"APP_TTL"`
 			Interval time.Duration `default:"60s" yaml:"interval" env:"APP_INTERVAL"`
 		} `yaml:"garbageCollector"`
+		Limiter struct {
+			Type string `default:"bucket" yaml:"type" env:"APP_LIMITER_TYPE"`
+		} `yaml:"limiter"`
+		BucketStore struct {
+			Type  string `default:"memory" yaml:"type" env:"APP_BUCKET_STORE_TYPE"`
+			Redis struct {
+				Addr      string `yaml:"addr" env:"APP_BUCKET_STORE_REDIS_ADDR"`
+				Password  string `yaml:"password" env:"APP_BUCKET_STORE_REDIS_PASSWORD"`
+				DB        int    `yaml:"db" env:"APP_BUCKET_STORE_REDIS_DB"`
+				KeyPrefix string `default:"limiter:" yaml:"keyPrefix" env:"APP_BUCKET_STORE_REDIS_KEY_PREFIX"`
+			} `yaml:"redis"`
+		} `yaml:"bucketStore"`
+		Snapshot struct {
+			Enabled  bool          `default:"false" yaml:"enabled" env:"APP_SNAPSHOT_ENABLED"`
+			Interval time.Duration `default:"10s" yaml:"interval" env:"APP_SNAPSHOT_INTERVAL"`
+		} `yaml:"snapshot"`
+		Reload struct {
+			Enabled  bool          `default:"false" yaml:"enabled" env:"APP_RELOAD_ENABLED"`
+			Interval time.Duration `default:"60s" yaml:"interval" env:"APP_RELOAD_INTERVAL"`
+			Listen   bool          `default:"false" yaml:"listen" env:"APP_RELOAD_LISTEN"`
+		} `yaml:"reload"`
+		RuleReaper struct {
+			Interval time.Duration `default:"60s" yaml:"interval" env:"APP_RULE_REAPER_INTERVAL"`
+		} `yaml:"ruleReaper"`
+		IPv6 struct {
+			Aggregate bool `default:"false" yaml:"aggregate" env:"APP_IPV6_AGGREGATE"`
+			PrefixLen int  `default:"64" yaml:"prefixLen" env:"APP_IPV6_PREFIX_LEN"`
+		} `yaml:"ipv6"`
+		RuleIndex struct {
+			Listen        bool          `default:"false" yaml:"listen" env:"APP_RULE_INDEX_LISTEN"`
+			RetryInterval time.Duration `default:"10s" yaml:"retryInterval" env:"APP_RULE_INDEX_RETRY_INTERVAL"`
+		} `yaml:"ruleIndex"`
+		AutoBan struct {
+			Enabled    bool          `default:"false" yaml:"enabled" env:"APP_AUTO_BAN_ENABLED"`
+			Threshold  int           `default:"10" yaml:"threshold" env:"APP_AUTO_BAN_THRESHOLD"`
+			Window     time.Duration `default:"10m" yaml:"window" env:"APP_AUTO_BAN_WINDOW"`
+			TTL        time.Duration `default:"1h" yaml:"ttl" env:"APP_AUTO_BAN_TTL"`
+			Backoff    float64       `default:"2" yaml:"backoff" env:"APP_AUTO_BAN_BACKOFF"`
+			MaxTTL     time.Duration `default:"24h" yaml:"maxTtl" env:"APP_AUTO_BAN_MAX_TTL"`
+			ForgetTime time.Duration `default:"24h" yaml:"forgetTime" env:"APP_AUTO_BAN_FORGET_TIME"`
+		} `yaml:"autoBan"`
+		// Хеширование значений чувствительных ключей identity (HMAC-SHA256) перед bucket'ами.
+		KeyHash struct {
+			Disabled bool     `default:"false" yaml:"disabled" env:"APP_KEY_HASH_DISABLED"`
+			Types    []string `default:"[\"password\"]" yaml:"types" env:"APP_KEY_HASH_TYPES"`
+			// Пустой секрет заменяется случайным при каждом запуске. Обязателен при снимках и Redis.
+			Secret string `yaml:"secret" env:"APP_KEY_HASH_SECRET"`
+			// Секрет до смены, действует GracePeriod после RotatedAt.
+			PreviousSecret string `yaml:"previousSecret" env:"APP_KEY_HASH_PREVIOUS_SECRET"`
+			// Время смены секрета в формате RFC3339, обязательно вместе с PreviousSecret.
+			RotatedAt   string        `yaml:"rotatedAt" env:"APP_KEY_HASH_ROTATED_AT"`
+			GracePeriod time.Duration `default:"1h" yaml:"gracePeriod" env:"APP_KEY_HASH_GRACE_PERIOD"`
+		} `yaml:"keyHash"`
+		// Блоклисты, синхронизируемые в черный список. Каждый задается url или path.
+		ThreatFeeds []struct {
+			Name     string        `yaml:"name"`
+			URL      string        `yaml:"url"`
+			Path     string        `yaml:"path"`
+			Enabled  bool          `default:"false" yaml:"enabled"`
+			Interval time.Duration `default:"1h" yaml:"interval"`
+			Timeout  time.Duration `default:"30s" yaml:"timeout"`
+		} `yaml:"threatFeeds"`
+		Shadow struct {
+			Enabled bool     `default:"false" yaml:"enabled" env:"APP_SHADOW_ENABLED"`
+			Types   []string `yaml:"types" env:"APP_SHADOW_TYPES"`
+		} `yaml:"shadow"`
 	} `yaml:"app"`
 }
 
@@ -35,6 +35,14 @@ rmq:
 scheduler:
   period: 60s
   queue: "calendar_events"
+app:
+  threatFeeds:
+    - name: drop
+      url: "https://example.com/drop.txt"
+      enabled: true
+    - name: local
+      path: "/etc/limiter/blocklist.txt"
+      interval: 5m
 `
 
 func TestNewConfig(t *testing.T) {
@@ -60,6 +68,42 @@ func TestNewConfig(t *testing.T) {
 	require.Equal(t, true, cfg.App.GarbageCollector.Enabled)
 	require.Equal(t, 600*time.Second, cfg.App.GarbageCollector.TTL)
 	require.Equal(t, 60*time.Second, cfg.App.GarbageCollector.Interval)
+	require.Equal(t, "bucket", cfg.App.Limiter.Type)
+	require.Equal(t, "memory", cfg.App.BucketStore.Type)
+	require.Equal(t, "limiter:", cfg.App.BucketStore.Redis.KeyPrefix)
+	require.Equal(t, false, cfg.App.Snapshot.Enabled)
+	require.Equal(t, 10*time.Second, cfg.App.Snapshot.Interval)
+	require.Equal(t, false, cfg.App.Reload.Enabled)
+	require.Equal(t, 60*time.Second, cfg.App.Reload.Interval)
+	require.Equal(t, false, cfg.App.Reload.Listen)
+	require.Equal(t, 60*time.Second, cfg.App.RuleReaper.Interval)
+	require.Equal(t, false, cfg.App.IPv6.Aggregate)
+	require.Equal(t, 64, cfg.App.IPv6.PrefixLen)
+	require.Equal(t, false, cfg.App.RuleIndex.Listen)
+	require.Equal(t, 10*time.Second, cfg.App.RuleIndex.RetryInterval)
+	require.Equal(t, false, cfg.App.AutoBan.Enabled)
+	require.Equal(t, 10, cfg.App.AutoBan.Threshold)
+	require.Equal(t, 10*time.Minute, cfg.App.AutoBan.Window)
+	require.Equal(t, time.Hour, cfg.App.AutoBan.TTL)
+	require.Equal(t, 2.0, cfg.App.AutoBan.Backoff)
+	require.Equal(t, 24*time.Hour, cfg.App.AutoBan.MaxTTL)
+	require.Equal(t, 24*time.Hour, cfg.App.AutoBan.ForgetTime)
+	require.Equal(t, false, cfg.App.Shadow.Enabled)
+	require.Empty(t, cfg.App.Shadow.Types)
+	require.Equal(t, false, cfg.App.KeyHash.Disabled)
+	require.Equal(t, []string{"password"}, cfg.App.KeyHash.Types)
+	require.Empty(t, cfg.App.KeyHash.Secret)
+	require.Empty(t, cfg.App.KeyHash.RotatedAt)
+	require.Equal(t, time.Hour, cfg.App.KeyHash.GracePeriod)
+	require.Len(t, cfg.App.ThreatFeeds, 2)
+	require.Equal(t, "drop", cfg.App.ThreatFeeds[0].Name)
+	require.Equal(t, "https://example.com/drop.txt", cfg.App.ThreatFeeds[0].URL)
+	require.Equal(t, true, cfg.App.ThreatFeeds[0].Enabled)
+	require.Equal(t, time.Hour, cfg.App.ThreatFeeds[0].Interval)
+	require.Equal(t, 30*time.Second, cfg.App.ThreatFeeds[0].Timeout)
+	require.Equal(t, "/etc/limiter/blocklist.txt", cfg.App.ThreatFeeds[1].Path)
+	require.Equal(t, false, cfg.App.ThreatFeeds[1].Enabled)
+	require.Equal(t, 5*time.Minute, cfg.App.ThreatFeeds[1].Interval)
 }
 
 func TestConfigContext(t *testing.T) {
@@ -1,12 +1,35 @@
 package appinterfaces
 
+import (
+	"io"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
+)
+
 type Application interface {
-	LimitCheck(ip, login, password string) (bool, error)
-	LimitReset(ip, login string) error
+	LimitCheck(ip, login, password string) (limiter.Decision, error)
+	// Check проверяет запрос с произвольным набором измерений, заданных в таблице rate_limit.
+	Check(dimensions limiter.UserIdentityDto) (limiter.Decision, error)
+	LimitReset(actor audit.Actor, ip, login string) error
+	ReloadLimits() error
+	ShadowModeSet(actor audit.Actor, enabled bool, limitTypes []string)
+
+	WhiteListAdd(actor audit.Actor, ip string, options rule.AddOptions) error
+	WhiteListDelete(actor audit.Actor, ip string) error
+
+	BlackListAdd(actor audit.Actor, ip string, options rule.AddOptions) error
+	BlackListDelete(actor audit.Actor, ip string) error
+
+	// RuleFind возвращает правила обоих списков для подсети ip.
+	RuleFind(ip string) (*rule.Rules, error)
+	// RuleList возвращает страницу правил списка filter.RuleType.
+	RuleList(filter rule.ListFilter) (*rule.Page, error)
 
-	WhiteListAdd(ip string) error
-	WhiteListDelete(ip string) error
+	RuleImport(actor audit.Actor, r io.Reader, options rule.ImportOptions) (*rule.ImportSummary, error)
+	RuleExport(w io.Writer, ruleType rule.Type, format rule.Format) error
 
-	BlackListAdd(ip string) error
-	BlackListDelete(ip string) error
+	// AuditLog возвращает страницу журнала административных изменений от новых к старым.
+	AuditLog(filter audit.ListFilter) (*audit.Page, error)
 }
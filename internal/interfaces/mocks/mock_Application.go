@@ -5,6 +5,11 @@
 package appinterfaces
 
 import (
+	"io"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -35,17 +40,79 @@ func (_m *MockApplication) EXPECT() *MockApplication_Expecter {
 	return &MockApplication_Expecter{mock: &_m.Mock}
 }
 
+// AuditLog provides a mock function for the type MockApplication
+func (_mock *MockApplication) AuditLog(filter audit.ListFilter) (*audit.Page, error) {
+	ret := _mock.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuditLog")
+	}
+
+	var r0 *audit.Page
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(audit.ListFilter) (*audit.Page, error)); ok {
+		return returnFunc(filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(audit.ListFilter) *audit.Page); ok {
+		r0 = returnFunc(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*audit.Page)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(audit.ListFilter) error); ok {
+		r1 = returnFunc(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockApplication_AuditLog_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuditLog'
+type MockApplication_AuditLog_Call struct {
+	*mock.Call
+}
+
+// AuditLog is a helper method to define mock.On call
+//   - filter audit.ListFilter
+func (_e *MockApplication_Expecter) AuditLog(filter interface{}) *MockApplication_AuditLog_Call {
+	return &MockApplication_AuditLog_Call{Call: _e.mock.On("AuditLog", filter)}
+}
+
+func (_c *MockApplication_AuditLog_Call) Run(run func(filter audit.ListFilter)) *MockApplication_AuditLog_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 audit.ListFilter
+		if args[0] != nil {
+			arg0 = args[0].(audit.ListFilter)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockApplication_AuditLog_Call) Return(page *audit.Page, err error) *MockApplication_AuditLog_Call {
+	_c.Call.Return(page, err)
+	return _c
+}
+
+func (_c *MockApplication_AuditLog_Call) RunAndReturn(run func(filter audit.ListFilter) (*audit.Page, error)) *MockApplication_AuditLog_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // BlackListAdd provides a mock function for the type MockApplication
-func (_mock *MockApplication) BlackListAdd(ip string) error {
-	ret := _mock.Called(ip)
+func (_mock *MockApplication) BlackListAdd(actor audit.Actor, ip string, options rule.AddOptions) error {
+	ret := _mock.Called(actor, ip, options)
 
 	if len(ret) == 0 {
 		panic("no return value specified for BlackListAdd")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
-		r0 = returnFunc(ip)
+	if returnFunc, ok := ret.Get(0).(func(audit.Actor, string, rule.AddOptions) error); ok {
+		r0 = returnFunc(actor, ip, options)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -58,19 +125,31 @@ type MockApplication_BlackListAdd_Call struct {
 }
 
 // BlackListAdd is a helper method to define mock.On call
+//   - actor audit.Actor
 //   - ip string
-func (_e *MockApplication_Expecter) BlackListAdd(ip interface{}) *MockApplication_BlackListAdd_Call {
-	return &MockApplication_BlackListAdd_Call{Call: _e.mock.On("BlackListAdd", ip)}
+//   - options rule.AddOptions
+func (_e *MockApplication_Expecter) BlackListAdd(actor interface{}, ip interface{}, options interface{}) *MockApplication_BlackListAdd_Call {
+	return &MockApplication_BlackListAdd_Call{Call: _e.mock.On("BlackListAdd", actor, ip, options)}
 }
 
-func (_c *MockApplication_BlackListAdd_Call) Run(run func(ip string)) *MockApplication_BlackListAdd_Call {
+func (_c *MockApplication_BlackListAdd_Call) Run(run func(actor audit.Actor, ip string, options rule.AddOptions)) *MockApplication_BlackListAdd_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 audit.Actor
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(audit.Actor)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 rule.AddOptions
+		if args[2] != nil {
+			arg2 = args[2].(rule.AddOptions)
 		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -81,22 +160,22 @@ func (_c *MockApplication_BlackListAdd_Call) Return(err error) *MockApplication_
 	return _c
 }
 
-func (_c *MockApplication_BlackListAdd_Call) RunAndReturn(run func(ip string) error) *MockApplication_BlackListAdd_Call {
+func (_c *MockApplication_BlackListAdd_Call) RunAndReturn(run func(actor audit.Actor, ip string, options rule.AddOptions) error) *MockApplication_BlackListAdd_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // BlackListDelete provides a mock function for the type MockApplication
-func (_mock *MockApplication) BlackListDelete(ip string) error {
-	ret := _mock.Called(ip)
+func (_mock *MockApplication) BlackListDelete(actor audit.Actor, ip string) error {
+	ret := _mock.Called(actor, ip)
 
 	if len(ret) == 0 {
 		panic("no return value specified for BlackListDelete")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
-		r0 = returnFunc(ip)
+	if returnFunc, ok := ret.Get(0).(func(audit.Actor, string) error); ok {
+		r0 = returnFunc(actor, ip)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -109,19 +188,25 @@ type MockApplication_BlackListDelete_Call struct {
 }
 
 // BlackListDelete is a helper method to define mock.On call
+//   - actor audit.Actor
 //   - ip string
-func (_e *MockApplication_Expecter) BlackListDelete(ip interface{}) *MockApplication_BlackListDelete_Call {
-	return &MockApplication_BlackListDelete_Call{Call: _e.mock.On("BlackListDelete", ip)}
+func (_e *MockApplication_Expecter) BlackListDelete(actor interface{}, ip interface{}) *MockApplication_BlackListDelete_Call {
+	return &MockApplication_BlackListDelete_Call{Call: _e.mock.On("BlackListDelete", actor, ip)}
 }
 
-func (_c *MockApplication_BlackListDelete_Call) Run(run func(ip string)) *MockApplication_BlackListDelete_Call {
+func (_c *MockApplication_BlackListDelete_Call) Run(run func(actor audit.Actor, ip string)) *MockApplication_BlackListDelete_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 audit.Actor
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(audit.Actor)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -132,28 +217,88 @@ func (_c *MockApplication_BlackListDelete_Call) Return(err error) *MockApplicati
 	return _c
 }
 
-func (_c *MockApplication_BlackListDelete_Call) RunAndReturn(run func(ip string) error) *MockApplication_BlackListDelete_Call {
+func (_c *MockApplication_BlackListDelete_Call) RunAndReturn(run func(actor audit.Actor, ip string) error) *MockApplication_BlackListDelete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Check provides a mock function for the type MockApplication
+func (_mock *MockApplication) Check(dimensions limiter.UserIdentityDto) (limiter.Decision, error) {
+	ret := _mock.Called(dimensions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 limiter.Decision
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(limiter.UserIdentityDto) (limiter.Decision, error)); ok {
+		return returnFunc(dimensions)
+	}
+	if returnFunc, ok := ret.Get(0).(func(limiter.UserIdentityDto) limiter.Decision); ok {
+		r0 = returnFunc(dimensions)
+	} else {
+		r0 = ret.Get(0).(limiter.Decision)
+	}
+	if returnFunc, ok := ret.Get(1).(func(limiter.UserIdentityDto) error); ok {
+		r1 = returnFunc(dimensions)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockApplication_Check_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Check'
+type MockApplication_Check_Call struct {
+	*mock.Call
+}
+
+// Check is a helper method to define mock.On call
+//   - dimensions limiter.UserIdentityDto
+func (_e *MockApplication_Expecter) Check(dimensions interface{}) *MockApplication_Check_Call {
+	return &MockApplication_Check_Call{Call: _e.mock.On("Check", dimensions)}
+}
+
+func (_c *MockApplication_Check_Call) Run(run func(dimensions limiter.UserIdentityDto)) *MockApplication_Check_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 limiter.UserIdentityDto
+		if args[0] != nil {
+			arg0 = args[0].(limiter.UserIdentityDto)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockApplication_Check_Call) Return(decision limiter.Decision, err error) *MockApplication_Check_Call {
+	_c.Call.Return(decision, err)
+	return _c
+}
+
+func (_c *MockApplication_Check_Call) RunAndReturn(run func(dimensions limiter.UserIdentityDto) (limiter.Decision, error)) *MockApplication_Check_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // LimitCheck provides a mock function for the type MockApplication
-func (_mock *MockApplication) LimitCheck(ip string, login string, password string) (bool, error) {
+func (_mock *MockApplication) LimitCheck(ip string, login string, password string) (limiter.Decision, error) {
 	ret := _mock.Called(ip, login, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LimitCheck")
 	}
 
-	var r0 bool
+	var r0 limiter.Decision
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string, string, string) (bool, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(string, string, string) (limiter.Decision, error)); ok {
 		return returnFunc(ip, login, password)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string, string, string) bool); ok {
+	if returnFunc, ok := ret.Get(0).(func(string, string, string) limiter.Decision); ok {
 		r0 = returnFunc(ip, login, password)
 	} else {
-		r0 = ret.Get(0).(bool)
+		r0 = ret.Get(0).(limiter.Decision)
 	}
 	if returnFunc, ok := ret.Get(1).(func(string, string, string) error); ok {
 		r1 = returnFunc(ip, login, password)
@@ -199,27 +344,27 @@ func (_c *MockApplication_LimitCheck_Call) Run(run func(ip string, login string,
 	return _c
 }
 
-func (_c *MockApplication_LimitCheck_Call) Return(b bool, err error) *MockApplication_LimitCheck_Call {
-	_c.Call.Return(b, err)
+func (_c *MockApplication_LimitCheck_Call) Return(decision limiter.Decision, err error) *MockApplication_LimitCheck_Call {
+	_c.Call.Return(decision, err)
 	return _c
 }
 
-func (_c *MockApplication_LimitCheck_Call) RunAndReturn(run func(ip string, login string, password string) (bool, error)) *MockApplication_LimitCheck_Call {
+func (_c *MockApplication_LimitCheck_Call) RunAndReturn(run func(ip string, login string, password string) (limiter.Decision, error)) *MockApplication_LimitCheck_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // LimitReset provides a mock function for the type MockApplication
-func (_mock *MockApplication) LimitReset(ip string, login string) error {
-	ret := _mock.Called(ip, login)
+func (_mock *MockApplication) LimitReset(actor audit.Actor, ip string, login string) error {
+	ret := _mock.Called(actor, ip, login)
 
 	if len(ret) == 0 {
 		panic("no return value specified for LimitReset")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, string) error); ok {
-		r0 = returnFunc(ip, login)
+	if returnFunc, ok := ret.Get(0).(func(audit.Actor, string, string) error); ok {
+		r0 = returnFunc(actor, ip, login)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -232,25 +377,31 @@ type MockApplication_LimitReset_Call struct {
 }
 
 // LimitReset is a helper method to define mock.On call
+//   - actor audit.Actor
 //   - ip string
 //   - login string
-func (_e *MockApplication_Expecter) LimitReset(ip interface{}, login interface{}) *MockApplication_LimitReset_Call {
-	return &MockApplication_LimitReset_Call{Call: _e.mock.On("LimitReset", ip, login)}
+func (_e *MockApplication_Expecter) LimitReset(actor interface{}, ip interface{}, login interface{}) *MockApplication_LimitReset_Call {
+	return &MockApplication_LimitReset_Call{Call: _e.mock.On("LimitReset", actor, ip, login)}
 }
 
-func (_c *MockApplication_LimitReset_Call) Run(run func(ip string, login string)) *MockApplication_LimitReset_Call {
+func (_c *MockApplication_LimitReset_Call) Run(run func(actor audit.Actor, ip string, login string)) *MockApplication_LimitReset_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 audit.Actor
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(audit.Actor)
 		}
 		var arg1 string
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -261,22 +412,379 @@ func (_c *MockApplication_LimitReset_Call) Return(err error) *MockApplication_Li
 	return _c
 }
 
-func (_c *MockApplication_LimitReset_Call) RunAndReturn(run func(ip string, login string) error) *MockApplication_LimitReset_Call {
+func (_c *MockApplication_LimitReset_Call) RunAndReturn(run func(actor audit.Actor, ip string, login string) error) *MockApplication_LimitReset_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// WhiteListAdd provides a mock function for the type MockApplication
-func (_mock *MockApplication) WhiteListAdd(ip string) error {
+// ReloadLimits provides a mock function for the type MockApplication
+func (_mock *MockApplication) ReloadLimits() error {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReloadLimits")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func() error); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockApplication_ReloadLimits_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReloadLimits'
+type MockApplication_ReloadLimits_Call struct {
+	*mock.Call
+}
+
+// ReloadLimits is a helper method to define mock.On call
+func (_e *MockApplication_Expecter) ReloadLimits() *MockApplication_ReloadLimits_Call {
+	return &MockApplication_ReloadLimits_Call{Call: _e.mock.On("ReloadLimits")}
+}
+
+func (_c *MockApplication_ReloadLimits_Call) Run(run func()) *MockApplication_ReloadLimits_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockApplication_ReloadLimits_Call) Return(err error) *MockApplication_ReloadLimits_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockApplication_ReloadLimits_Call) RunAndReturn(run func() error) *MockApplication_ReloadLimits_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RuleExport provides a mock function for the type MockApplication
+func (_mock *MockApplication) RuleExport(w io.Writer, ruleType rule.Type, format rule.Format) error {
+	ret := _mock.Called(w, ruleType, format)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RuleExport")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(io.Writer, rule.Type, rule.Format) error); ok {
+		r0 = returnFunc(w, ruleType, format)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockApplication_RuleExport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RuleExport'
+type MockApplication_RuleExport_Call struct {
+	*mock.Call
+}
+
+// RuleExport is a helper method to define mock.On call
+//   - w io.Writer
+//   - ruleType rule.Type
+//   - format rule.Format
+func (_e *MockApplication_Expecter) RuleExport(w interface{}, ruleType interface{}, format interface{}) *MockApplication_RuleExport_Call {
+	return &MockApplication_RuleExport_Call{Call: _e.mock.On("RuleExport", w, ruleType, format)}
+}
+
+func (_c *MockApplication_RuleExport_Call) Run(run func(w io.Writer, ruleType rule.Type, format rule.Format)) *MockApplication_RuleExport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 io.Writer
+		if args[0] != nil {
+			arg0 = args[0].(io.Writer)
+		}
+		var arg1 rule.Type
+		if args[1] != nil {
+			arg1 = args[1].(rule.Type)
+		}
+		var arg2 rule.Format
+		if args[2] != nil {
+			arg2 = args[2].(rule.Format)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockApplication_RuleExport_Call) Return(err error) *MockApplication_RuleExport_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockApplication_RuleExport_Call) RunAndReturn(run func(w io.Writer, ruleType rule.Type, format rule.Format) error) *MockApplication_RuleExport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RuleFind provides a mock function for the type MockApplication
+func (_mock *MockApplication) RuleFind(ip string) (*rule.Rules, error) {
 	ret := _mock.Called(ip)
 
+	if len(ret) == 0 {
+		panic("no return value specified for RuleFind")
+	}
+
+	var r0 *rule.Rules
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (*rule.Rules, error)); ok {
+		return returnFunc(ip)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) *rule.Rules); ok {
+		r0 = returnFunc(ip)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rule.Rules)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockApplication_RuleFind_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RuleFind'
+type MockApplication_RuleFind_Call struct {
+	*mock.Call
+}
+
+// RuleFind is a helper method to define mock.On call
+//   - ip string
+func (_e *MockApplication_Expecter) RuleFind(ip interface{}) *MockApplication_RuleFind_Call {
+	return &MockApplication_RuleFind_Call{Call: _e.mock.On("RuleFind", ip)}
+}
+
+func (_c *MockApplication_RuleFind_Call) Run(run func(ip string)) *MockApplication_RuleFind_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockApplication_RuleFind_Call) Return(rules *rule.Rules, err error) *MockApplication_RuleFind_Call {
+	_c.Call.Return(rules, err)
+	return _c
+}
+
+func (_c *MockApplication_RuleFind_Call) RunAndReturn(run func(ip string) (*rule.Rules, error)) *MockApplication_RuleFind_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RuleImport provides a mock function for the type MockApplication
+func (_mock *MockApplication) RuleImport(actor audit.Actor, r io.Reader, options rule.ImportOptions) (*rule.ImportSummary, error) {
+	ret := _mock.Called(actor, r, options)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RuleImport")
+	}
+
+	var r0 *rule.ImportSummary
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(audit.Actor, io.Reader, rule.ImportOptions) (*rule.ImportSummary, error)); ok {
+		return returnFunc(actor, r, options)
+	}
+	if returnFunc, ok := ret.Get(0).(func(audit.Actor, io.Reader, rule.ImportOptions) *rule.ImportSummary); ok {
+		r0 = returnFunc(actor, r, options)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rule.ImportSummary)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(audit.Actor, io.Reader, rule.ImportOptions) error); ok {
+		r1 = returnFunc(actor, r, options)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockApplication_RuleImport_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RuleImport'
+type MockApplication_RuleImport_Call struct {
+	*mock.Call
+}
+
+// RuleImport is a helper method to define mock.On call
+//   - actor audit.Actor
+//   - r io.Reader
+//   - options rule.ImportOptions
+func (_e *MockApplication_Expecter) RuleImport(actor interface{}, r interface{}, options interface{}) *MockApplication_RuleImport_Call {
+	return &MockApplication_RuleImport_Call{Call: _e.mock.On("RuleImport", actor, r, options)}
+}
+
+func (_c *MockApplication_RuleImport_Call) Run(run func(actor audit.Actor, r io.Reader, options rule.ImportOptions)) *MockApplication_RuleImport_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 audit.Actor
+		if args[0] != nil {
+			arg0 = args[0].(audit.Actor)
+		}
+		var arg1 io.Reader
+		if args[1] != nil {
+			arg1 = args[1].(io.Reader)
+		}
+		var arg2 rule.ImportOptions
+		if args[2] != nil {
+			arg2 = args[2].(rule.ImportOptions)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockApplication_RuleImport_Call) Return(importSummary *rule.ImportSummary, err error) *MockApplication_RuleImport_Call {
+	_c.Call.Return(importSummary, err)
+	return _c
+}
+
+func (_c *MockApplication_RuleImport_Call) RunAndReturn(run func(actor audit.Actor, r io.Reader, options rule.ImportOptions) (*rule.ImportSummary, error)) *MockApplication_RuleImport_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RuleList provides a mock function for the type MockApplication
+func (_mock *MockApplication) RuleList(filter rule.ListFilter) (*rule.Page, error) {
+	ret := _mock.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RuleList")
+	}
+
+	var r0 *rule.Page
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(rule.ListFilter) (*rule.Page, error)); ok {
+		return returnFunc(filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(rule.ListFilter) *rule.Page); ok {
+		r0 = returnFunc(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rule.Page)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(rule.ListFilter) error); ok {
+		r1 = returnFunc(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockApplication_RuleList_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RuleList'
+type MockApplication_RuleList_Call struct {
+	*mock.Call
+}
+
+// RuleList is a helper method to define mock.On call
+//   - filter rule.ListFilter
+func (_e *MockApplication_Expecter) RuleList(filter interface{}) *MockApplication_RuleList_Call {
+	return &MockApplication_RuleList_Call{Call: _e.mock.On("RuleList", filter)}
+}
+
+func (_c *MockApplication_RuleList_Call) Run(run func(filter rule.ListFilter)) *MockApplication_RuleList_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 rule.ListFilter
+		if args[0] != nil {
+			arg0 = args[0].(rule.ListFilter)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockApplication_RuleList_Call) Return(page *rule.Page, err error) *MockApplication_RuleList_Call {
+	_c.Call.Return(page, err)
+	return _c
+}
+
+func (_c *MockApplication_RuleList_Call) RunAndReturn(run func(filter rule.ListFilter) (*rule.Page, error)) *MockApplication_RuleList_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ShadowModeSet provides a mock function for the type MockApplication
+func (_mock *MockApplication) ShadowModeSet(actor audit.Actor, enabled bool, limitTypes []string) {
+	_mock.Called(actor, enabled, limitTypes)
+	return
+}
+
+// MockApplication_ShadowModeSet_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ShadowModeSet'
+type MockApplication_ShadowModeSet_Call struct {
+	*mock.Call
+}
+
+// ShadowModeSet is a helper method to define mock.On call
+//   - actor audit.Actor
+//   - enabled bool
+//   - limitTypes []string
+func (_e *MockApplication_Expecter) ShadowModeSet(actor interface{}, enabled interface{}, limitTypes interface{}) *MockApplication_ShadowModeSet_Call {
+	return &MockApplication_ShadowModeSet_Call{Call: _e.mock.On("ShadowModeSet", actor, enabled, limitTypes)}
+}
+
+func (_c *MockApplication_ShadowModeSet_Call) Run(run func(actor audit.Actor, enabled bool, limitTypes []string)) *MockApplication_ShadowModeSet_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 audit.Actor
+		if args[0] != nil {
+			arg0 = args[0].(audit.Actor)
+		}
+		var arg1 bool
+		if args[1] != nil {
+			arg1 = args[1].(bool)
+		}
+		var arg2 []string
+		if args[2] != nil {
+			arg2 = args[2].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockApplication_ShadowModeSet_Call) Return() *MockApplication_ShadowModeSet_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockApplication_ShadowModeSet_Call) RunAndReturn(run func(actor audit.Actor, enabled bool, limitTypes []string)) *MockApplication_ShadowModeSet_Call {
+	_c.Run(run)
+	return _c
+}
+
+// WhiteListAdd provides a mock function for the type MockApplication
+func (_mock *MockApplication) WhiteListAdd(actor audit.Actor, ip string, options rule.AddOptions) error {
+	ret := _mock.Called(actor, ip, options)
+
 	if len(ret) == 0 {
 		panic("no return value specified for WhiteListAdd")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
-		r0 = returnFunc(ip)
+	if returnFunc, ok := ret.Get(0).(func(audit.Actor, string, rule.AddOptions) error); ok {
+		r0 = returnFunc(actor, ip, options)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -289,19 +797,31 @@ type MockApplication_WhiteListAdd_Call struct {
 }
 
 // WhiteListAdd is a helper method to define mock.On call
+//   - actor audit.Actor
 //   - ip string
-func (_e *MockApplication_Expecter) WhiteListAdd(ip interface{}) *MockApplication_WhiteListAdd_Call {
-	return &MockApplication_WhiteListAdd_Call{Call: _e.mock.On("WhiteListAdd", ip)}
+//   - options rule.AddOptions
+func (_e *MockApplication_Expecter) WhiteListAdd(actor interface{}, ip interface{}, options interface{}) *MockApplication_WhiteListAdd_Call {
+	return &MockApplication_WhiteListAdd_Call{Call: _e.mock.On("WhiteListAdd", actor, ip, options)}
 }
 
-func (_c *MockApplication_WhiteListAdd_Call) Run(run func(ip string)) *MockApplication_WhiteListAdd_Call {
+func (_c *MockApplication_WhiteListAdd_Call) Run(run func(actor audit.Actor, ip string, options rule.AddOptions)) *MockApplication_WhiteListAdd_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 audit.Actor
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(audit.Actor)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 rule.AddOptions
+		if args[2] != nil {
+			arg2 = args[2].(rule.AddOptions)
 		}
 		run(
 			arg0,
+			arg1,
+			arg2,
 		)
 	})
 	return _c
@@ -312,22 +832,22 @@ func (_c *MockApplication_WhiteListAdd_Call) Return(err error) *MockApplication_
 	return _c
 }
 
-func (_c *MockApplication_WhiteListAdd_Call) RunAndReturn(run func(ip string) error) *MockApplication_WhiteListAdd_Call {
+func (_c *MockApplication_WhiteListAdd_Call) RunAndReturn(run func(actor audit.Actor, ip string, options rule.AddOptions) error) *MockApplication_WhiteListAdd_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // WhiteListDelete provides a mock function for the type MockApplication
-func (_mock *MockApplication) WhiteListDelete(ip string) error {
-	ret := _mock.Called(ip)
+func (_mock *MockApplication) WhiteListDelete(actor audit.Actor, ip string) error {
+	ret := _mock.Called(actor, ip)
 
 	if len(ret) == 0 {
 		panic("no return value specified for WhiteListDelete")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string) error); ok {
-		r0 = returnFunc(ip)
+	if returnFunc, ok := ret.Get(0).(func(audit.Actor, string) error); ok {
+		r0 = returnFunc(actor, ip)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -340,19 +860,25 @@ type MockApplication_WhiteListDelete_Call struct {
 }
 
 // WhiteListDelete is a helper method to define mock.On call
+//   - actor audit.Actor
 //   - ip string
-func (_e *MockApplication_Expecter) WhiteListDelete(ip interface{}) *MockApplication_WhiteListDelete_Call {
-	return &MockApplication_WhiteListDelete_Call{Call: _e.mock.On("WhiteListDelete", ip)}
+func (_e *MockApplication_Expecter) WhiteListDelete(actor interface{}, ip interface{}) *MockApplication_WhiteListDelete_Call {
+	return &MockApplication_WhiteListDelete_Call{Call: _e.mock.On("WhiteListDelete", actor, ip)}
 }
 
-func (_c *MockApplication_WhiteListDelete_Call) Run(run func(ip string)) *MockApplication_WhiteListDelete_Call {
+func (_c *MockApplication_WhiteListDelete_Call) Run(run func(actor audit.Actor, ip string)) *MockApplication_WhiteListDelete_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 audit.Actor
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(audit.Actor)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
@@ -363,7 +889,7 @@ func (_c *MockApplication_WhiteListDelete_Call) Return(err error) *MockApplicati
 	return _c
 }
 
-func (_c *MockApplication_WhiteListDelete_Call) RunAndReturn(run func(ip string) error) *MockApplication_WhiteListDelete_Call {
+func (_c *MockApplication_WhiteListDelete_Call) RunAndReturn(run func(actor audit.Actor, ip string) error) *MockApplication_WhiteListDelete_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+var ErrAutoBan = errors.New("auto-ban failed")
+
+// AutoBanCreatedBy автор правил черного списка, добавленных автоматической блокировкой.
+const AutoBanCreatedBy = "auth-limiter"
+
+// AutoBan параметры автоматической блокировки IP, который часто получает отказ по лимиту.
+type AutoBan struct {
+	// Количество отказов за Window, превышение которого добавляет IP в черный список. 0 отключает блокировку.
+	Threshold int
+	Window    time.Duration
+	// Срок первой блокировки.
+	TTL time.Duration
+	// Множитель срока каждой следующей блокировки того же IP. 1 - срок не растет.
+	Backoff float64
+	// Максимальный срок блокировки. 0 - без ограничения.
+	MaxTTL time.Duration
+	// Время после последней блокировки, через которое IP перестает считаться повторным нарушителем.
+	ForgetTime time.Duration
+}
+
+// offender отказы и блокировки одного IP.
+type offender struct {
+	denials []time.Time
+	bans    int
+	lastBan time.Time
+}
+
+type autoBanner struct {
+	sync.Mutex
+
+	config    AutoBan
+	offenders map[string]*offender
+}
+
+func newAutoBanner(config AutoBan) *autoBanner {
+	return &autoBanner{
+		config:    config,
+		offenders: make(map[string]*offender),
+	}
+}
+
+// deny учитывает отказ для ip и возвращает срок блокировки, если отказов за окно больше порога.
+// Счетчик отказов после блокировки обнуляется, поэтому одновременные отказы блокируют IP один раз.
+func (b *autoBanner) deny(ip string, now time.Time) (time.Duration, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	o, found := b.offenders[ip]
+	if !found {
+		o = &offender{}
+		b.offenders[ip] = o
+	}
+
+	if o.bans > 0 && now.Sub(o.lastBan) > b.config.ForgetTime {
+		o.bans = 0
+	}
+
+	o.denials = append(b.recent(o.denials, now), now)
+	if len(o.denials) <= b.config.Threshold {
+		return 0, false
+	}
+
+	ttl := float64(b.config.TTL) * math.Pow(max(b.config.Backoff, 1), float64(o.bans))
+	if b.config.MaxTTL > 0 {
+		ttl = min(ttl, float64(b.config.MaxTTL))
+	}
+
+	o.denials = nil
+	o.bans++
+	o.lastBan = now
+
+	return time.Duration(min(ttl, math.MaxInt64)), true
+}
+
+// sweep удаляет IP без отказов за окно, которые не блокировались или уже не считаются повторными нарушителями.
+func (b *autoBanner) sweep(now time.Time) {
+	b.Lock()
+	defer b.Unlock()
+
+	for ip, o := range b.offenders {
+		o.denials = b.recent(o.denials, now)
+		if len(o.denials) == 0 && (o.bans == 0 || now.Sub(o.lastBan) > b.config.ForgetTime) {
+			delete(b.offenders, ip)
+		}
+	}
+}
+
+// recent возвращает отказы, попадающие в окно, заканчивающееся в now.
+func (b *autoBanner) recent(denials []time.Time, now time.Time) []time.Time {
+	for i, denial := range denials {
+		if now.Sub(denial) < b.config.Window {
+			return denials[i:]
+		}
+	}
+
+	return denials[:0]
+}
+
+func (b *autoBanner) reason() string {
+	return fmt.Sprintf("auto-ban: more than %d rate limit denials in %s", b.config.Threshold, b.config.Window)
+}
@@ -1,8 +1,16 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/netip"
+	"time"
+
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/composite"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/keyhash"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/metrics"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
 )
 
@@ -11,6 +19,12 @@ type Limiter struct {
 	limiter.IService
 	ruleService   rule.IService
 	bucketLimiter *composite.Limiter
+	// Длина префикса, до которой агрегируются IPv6 адреса в ключе bucket'а ip. 0 - без агрегации.
+	ipv6PrefixLen int
+	// Автоматическая блокировка IP, nil - отключена.
+	autoBan *autoBanner
+	// Хеширование чувствительных ключей identity перед bucket'ами, nil - отключено.
+	keyHasher *keyhash.Hasher
 }
 
 func New(
@@ -24,43 +38,268 @@ func New(
 }
 
 func (l *Limiter) SatisfyLimit(identity limiter.UserIdentityDto) (bool, error) {
-	validationErr := l.validateIdentity(identity)
+	identity, validationErr := l.validateIdentity(identity)
 	if validationErr != nil {
 		return false, validationErr
 	}
 
-	inBlackList, blErr := l.ruleService.InBlackList(identity[limiter.IPLimit.String()])
-	if inBlackList || blErr != nil {
-		return false, blErr
+	if ip := identity[limiter.IPLimit.String()]; ip != "" {
+		inBlackList, blErr := l.ruleService.InBlackList(ip)
+		if inBlackList || blErr != nil {
+			return false, blErr
+		}
+
+		inWhiteList, wlErr := l.ruleService.InWhiteList(ip)
+		if wlErr != nil {
+			return false, wlErr
+		}
+
+		if inWhiteList {
+			return true, nil
+		}
+	}
+
+	bucketIdentity := l.bucketIdentity(identity)
+	satisfies, err := l.satisfyBuckets(bucketIdentity)
+	if satisfies || err != nil {
+		return satisfies, err
+	}
+
+	return false, l.recordDenial(bucketIdentity[limiter.IPLimit.String()])
+}
+
+// Check проверяет запрос как SatisfyLimit и возвращает подробный результат проверки:
+// причину решения, сработавшее правило черного/белого списка или лимит.
+// Для IP из белого списка количество оставшихся попыток не ограничено.
+// Черный и белый списки проверяются, только если в запросе есть измерение ip.
+func (l *Limiter) Check(identity limiter.UserIdentityDto) (limiter.Decision, error) {
+	identity, validationErr := l.validateIdentity(identity)
+	if validationErr != nil {
+		return limiter.Decision{}, validationErr
+	}
+
+	if ip := identity[limiter.IPLimit.String()]; ip != "" {
+		decision, decided, err := l.checkRules(ip)
+		if decided || err != nil {
+			return decision, err
+		}
+	}
+
+	bucketIdentity := l.bucketIdentity(identity)
+	decision, err := l.checkBuckets(bucketIdentity)
+	if decision.Allowed || err != nil {
+		return decision, err
+	}
+
+	return decision, l.recordDenial(bucketIdentity[limiter.IPLimit.String()])
+}
+
+// checkRules возвращает решение по правилу черного или белого списка для ip, если оно есть.
+func (l *Limiter) checkRules(ip string) (limiter.Decision, bool, error) {
+	blackListRule, blErr := l.ruleService.Match(ip, rule.BlackList)
+	if blErr != nil {
+		return limiter.Decision{}, false, blErr
+	}
+
+	if blackListRule != nil {
+		return limiter.Decision{
+			Allowed:   false,
+			Reason:    limiter.ReasonBlackList,
+			RuleID:    blackListRule.ID,
+			RuleIPNet: blackListRule.IP,
+		}, true, nil
 	}
 
-	inWhiteList, wlErr := l.ruleService.InWhiteList(identity[limiter.IPLimit.String()])
+	whiteListRule, wlErr := l.ruleService.Match(ip, rule.WhiteList)
 	if wlErr != nil {
-		return false, wlErr
+		return limiter.Decision{}, false, wlErr
 	}
 
-	if inWhiteList {
-		return true, nil
+	if whiteListRule != nil {
+		return limiter.Decision{
+			Allowed:   true,
+			Reason:    limiter.ReasonWhiteList,
+			RuleID:    whiteListRule.ID,
+			RuleIPNet: whiteListRule.IP,
+			Remaining: limiter.Unlimited,
+		}, true, nil
 	}
 
-	return l.bucketLimiter.SatisfyLimit(identity)
+	return limiter.Decision{}, false, nil
 }
 
 func (l *Limiter) ResetLimit(identity limiter.UserIdentityDto) error {
-	return l.bucketLimiter.ResetLimit(identity)
+	bucketIdentity := l.bucketIdentity(identity)
+	if err := l.bucketLimiter.ResetLimit(l.hashIdentity(bucketIdentity)); err != nil {
+		return err
+	}
+
+	if previous, ok := l.previousIdentity(bucketIdentity); ok {
+		return l.bucketLimiter.ResetLimit(previous)
+	}
+
+	return nil
+}
+
+// Remaining возвращает количество возможных запросов identity по каждому из лимитов без списания токенов.
+func (l *Limiter) Remaining(identity limiter.UserIdentityDto) (map[string]int, error) {
+	return l.bucketLimiter.Remaining(l.hashIdentity(l.bucketIdentity(identity)))
+}
+
+// SetIPv6Prefix включает агрегацию IPv6 адресов: лимит ip считается для подсети длины prefixLen
+// (например, /64), так как один хост может менять адрес внутри нее. 0 отключает агрегацию.
+// Вызывается до начала проверок.
+func (l *Limiter) SetIPv6Prefix(prefixLen int) {
+	l.ipv6PrefixLen = prefixLen
 }
 
 func (l *Limiter) SetRequestCost(requestCost int) {
 	l.bucketLimiter.SetRequestCost(requestCost)
 }
 
-func (l *Limiter) validateIdentity(identity limiter.UserIdentityDto) error {
-	if identity[limiter.IPLimit.String()] == "" ||
-		identity[limiter.LoginLimit.String()] == "" ||
-		identity[limiter.PasswordLimit.String()] == "" {
-		// These identity keys are required
-		return limiter.ErrIncorrectIdentity
+// SetKeyHasher включает хеширование чувствительных ключей identity. Черный и белый списки
+// и автоматическая блокировка работают с исходными значениями. Вызывается до начала проверок.
+func (l *Limiter) SetKeyHasher(keyHasher *keyhash.Hasher) {
+	l.keyHasher = keyHasher
+}
+
+// SetShadow включает режим наблюдения для лимитов: черный список продолжает отклонять запросы.
+func (l *Limiter) SetShadow(enabled bool, limitTypes []string) {
+	l.bucketLimiter.SetShadow(enabled, limitTypes)
+}
+
+// Shadow возвращает текущий режим наблюдения.
+func (l *Limiter) Shadow() (bool, []string) {
+	return l.bucketLimiter.Shadow()
+}
+
+// SetAutoBan включает автоматическую блокировку: IP, получивший больше autoBan.Threshold отказов по лимиту
+// за autoBan.Window, добавляется в черный список на autoBan.TTL. Срок каждой следующей блокировки
+// того же IP увеличивается в autoBan.Backoff раз. Вызывается до начала проверок.
+func (l *Limiter) SetAutoBan(autoBan AutoBan) {
+	if autoBan.Threshold <= 0 {
+		l.autoBan = nil
+		return
+	}
+
+	l.autoBan = newAutoBanner(autoBan)
+}
+
+// SweepAutoBan забывает IP, которые больше не учитываются автоматической блокировкой.
+func (l *Limiter) SweepAutoBan() {
+	if l.autoBan != nil {
+		l.autoBan.sweep(time.Now())
+	}
+}
+
+// recordDenial учитывает отказ по лимиту и блокирует ip при превышении порога.
+// При агрегации IPv6 блокируется подсеть. Ошибка блокировки не меняет решение по запросу.
+// Блокировка, конфликтующая с правилами, намеренно пропускается: белый список имеет приоритет
+// над автоматической блокировкой, а IP, уже входящий в черный список, блокировать повторно не нужно.
+func (l *Limiter) recordDenial(ip string) error {
+	if l.autoBan == nil || ip == "" {
+		return nil
+	}
+
+	now := time.Now()
+	ttl, ban := l.autoBan.deny(ip, now)
+	if !ban {
+		return nil
 	}
 
+	expiresAt := now.Add(ttl)
+	err := l.ruleService.BlackListAdd(ip, rule.AddOptions{
+		ExpiresAt: &expiresAt,
+		Reason:    l.autoBan.reason(),
+		CreatedBy: AutoBanCreatedBy,
+		Source:    rule.SourceAutoBan,
+	})
+	if errors.Is(err, rule.ErrRuleConflict) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrAutoBan, ip, err)
+	}
+	metrics.AutoBans.Inc()
+
 	return nil
 }
+
+// satisfyBuckets проверяет лимиты для bucket'ов с хешированными ключами. В grace-период после смены
+// секрета запрос также должен пройти лимиты bucket'ов, ключи которых получены предыдущим секретом:
+// bucket'ы обоих секретов проверяются как один запрос, и токены списываются, только если пройдены все.
+func (l *Limiter) satisfyBuckets(bucketIdentity limiter.UserIdentityDto) (bool, error) {
+	return l.bucketLimiter.SatisfyLimitAll(l.bucketIdentities(bucketIdentity)...)
+}
+
+// checkBuckets проверяет лимиты как satisfyBuckets: отказ по ключам любого из секретов отклоняет запрос,
+// остаток попыток - минимальный по bucket'ам обоих секретов.
+func (l *Limiter) checkBuckets(bucketIdentity limiter.UserIdentityDto) (limiter.Decision, error) {
+	return l.bucketLimiter.CheckAll(l.bucketIdentities(bucketIdentity)...)
+}
+
+// bucketIdentities возвращает identity с ключами текущего секрета и, в grace-период, предыдущего.
+func (l *Limiter) bucketIdentities(bucketIdentity limiter.UserIdentityDto) []limiter.UserIdentityDto {
+	identities := []limiter.UserIdentityDto{l.hashIdentity(bucketIdentity)}
+	if previous, ok := l.previousIdentity(bucketIdentity); ok {
+		identities = append(identities, previous)
+	}
+
+	return identities
+}
+
+func (l *Limiter) hashIdentity(bucketIdentity limiter.UserIdentityDto) limiter.UserIdentityDto {
+	if l.keyHasher == nil {
+		return bucketIdentity
+	}
+
+	return l.keyHasher.Hash(bucketIdentity)
+}
+
+func (l *Limiter) previousIdentity(bucketIdentity limiter.UserIdentityDto) (limiter.UserIdentityDto, bool) {
+	if l.keyHasher == nil {
+		return nil, false
+	}
+
+	return l.keyHasher.Previous(bucketIdentity, time.Now())
+}
+
+// bucketIdentity возвращает identity для bucket'ов: при агрегации IPv6 адрес заменяется подсетью.
+// Черный и белый списки проверяются по исходному адресу.
+func (l *Limiter) bucketIdentity(identity limiter.UserIdentityDto) limiter.UserIdentityDto {
+	if l.ipv6PrefixLen == 0 {
+		return identity
+	}
+
+	ip, err := netip.ParseAddr(identity[limiter.IPLimit.String()])
+	if err != nil || ip.Unmap().Is4() {
+		return identity
+	}
+
+	prefix, err := ip.WithZone("").Prefix(l.ipv6PrefixLen)
+	if err != nil {
+		return identity
+	}
+
+	aggregated := maps.Clone(identity)
+	aggregated[limiter.IPLimit.String()] = prefix.String()
+
+	return aggregated
+}
+
+// validateIdentity отбрасывает измерения identity с пустыми значениями и проверяет оставшиеся
+// по лимитам: измерения без лимита и отсутствие обязательных измерений - ошибка.
+func (l *Limiter) validateIdentity(identity limiter.UserIdentityDto) (limiter.UserIdentityDto, error) {
+	dimensions := make(limiter.UserIdentityDto, len(identity))
+	for key, value := range identity {
+		if value != "" {
+			dimensions[key] = value
+		}
+	}
+
+	if err := l.bucketLimiter.Validate(dimensions); err != nil {
+		return nil, err
+	}
+
+	return dimensions, nil
+}
@@ -1,6 +1,7 @@
 package auth_test
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -8,6 +9,7 @@ import (
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/auth"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/composite"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/keyhash"
 	limitermocks "github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/mocks"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
 	rulemocks "github.com/rainb0w-clwn/go_auth_limiter/internal/rule/mocks"
@@ -39,7 +41,7 @@ func TestLoginFormLimiter_SatisfyLimit(t *testing.T) {
 
 	// Mock LimitStorage
 	limitStorage := limitermocks.NewMockIStorage(t)
-	limitStorage.EXPECT().GetLimitsByTypes(mock.AnythingOfType("[]string")).Return(&limiter.Limits{
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
 		limiter.Limit{LimitType: limiter.IPLimit, Value: limit},
 		limiter.Limit{LimitType: limiter.LoginLimit, Value: limit},
 		limiter.Limit{LimitType: limiter.PasswordLimit, Value: limit},
@@ -113,6 +115,11 @@ func TestLoginFormLimiter_SatisfyLimit(t *testing.T) {
 func TestLoginFormLimiter_SatisfyLimit_Error(t *testing.T) {
 	ruleService := rule.NewService(rulemocks.NewMockIStorage(t))
 	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 3, Required: true},
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 3, Required: true},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 3, Required: true},
+	}, nil).Maybe()
 	refillRate := refillrate.New(3, time.Second*1)
 
 	t.Run("incorrect identity", func(t *testing.T) {
@@ -139,5 +146,488 @@ func TestLoginFormLimiter_SatisfyLimit_Error(t *testing.T) {
 		// empty identity
 		_, err = loginFormLimiter.SatisfyLimit(emptyIdentity)
 		require.ErrorIs(t, err, expectedErr)
+
+		// dimension without limit
+		_, err = loginFormLimiter.SatisfyLimit(limiter.UserIdentityDto{
+			limiter.IPLimit.String():       "1.1.1.1",
+			limiter.LoginLimit.String():    "lucky",
+			limiter.PasswordLimit.String(): "root",
+			"tenant":                       "acme",
+		})
+		require.ErrorIs(t, err, expectedErr)
+	})
+}
+
+func TestLoginFormLimiter_Check(t *testing.T) {
+	whiteListIP, blackListIP, unknownIP := "192.168.1.1", "192.150.10.3", "5.5.5.5"
+
+	ruleStorage := rulemocks.NewMockIStorage(t)
+	ruleStorage.EXPECT().GetForType(rule.WhiteList).Return(&rule.Rules{
+		rule.Rule{ID: 1, IP: whiteListIP, RuleType: rule.WhiteList},
+	}, nil).Maybe()
+	ruleStorage.EXPECT().GetForType(rule.BlackList).Return(&rule.Rules{
+		rule.Rule{ID: 2, IP: blackListIP, RuleType: rule.BlackList},
+	}, nil).Maybe()
+	ruleService := rule.NewService(ruleStorage)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 10},
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 2},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 10},
+	}, nil).Maybe()
+
+	newIdentity := func(ip string) limiter.UserIdentityDto {
+		return limiter.UserIdentityDto{
+			limiter.IPLimit.String():       ip,
+			limiter.LoginLimit.String():    "lucky",
+			limiter.PasswordLimit.String(): "root",
+		}
+	}
+
+	t.Run("ip in white list", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Minute)))
+
+		decision, err := loginFormLimiter.Check(newIdentity(whiteListIP))
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+		require.Equal(t, limiter.ReasonWhiteList, decision.Reason)
+		require.Equal(t, 1, decision.RuleID)
+		require.Equal(t, whiteListIP, decision.RuleIPNet)
+		require.Equal(t, limiter.Unlimited, decision.Remaining)
+	})
+
+	t.Run("ip in black list", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Minute)))
+
+		decision, err := loginFormLimiter.Check(newIdentity(blackListIP))
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.ReasonBlackList, decision.Reason)
+		require.Equal(t, 2, decision.RuleID)
+		require.Equal(t, blackListIP, decision.RuleIPNet)
+		require.Zero(t, decision.RetryAfter)
+		require.Empty(t, decision.LimitType)
+	})
+
+	t.Run("denied by limit", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Minute)))
+
+		decision, err := loginFormLimiter.Check(newIdentity(unknownIP))
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+		require.Equal(t, limiter.ReasonPassed, decision.Reason)
+		require.Equal(t, 1, decision.Remaining)
+		require.Equal(t, 2, decision.Limit)
+
+		_, err = loginFormLimiter.Check(newIdentity(unknownIP))
+		require.NoError(t, err)
+
+		decision, err = loginFormLimiter.Check(newIdentity(unknownIP))
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.ReasonRateLimit, decision.Reason)
+		require.Equal(t, limiter.LoginLimit, decision.LimitType)
+		require.Equal(t, time.Minute, decision.RetryAfter)
+	})
+
+	t.Run("shadow mode", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Minute)))
+		loginFormLimiter.SetShadow(true, nil)
+
+		for range 2 {
+			_, err := loginFormLimiter.Check(newIdentity(unknownIP))
+			require.NoError(t, err)
+		}
+
+		decision, err := loginFormLimiter.Check(newIdentity(unknownIP))
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+		require.True(t, decision.Shadow)
+		require.Equal(t, limiter.LoginLimit, decision.LimitType)
+
+		// черный список работает и в режиме наблюдения
+		decision, err = loginFormLimiter.Check(newIdentity(blackListIP))
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.ReasonBlackList, decision.Reason)
+	})
+}
+
+func TestLoginFormLimiter_IPv6Aggregation(t *testing.T) {
+	ruleStorage := rulemocks.NewMockIStorage(t)
+	ruleStorage.EXPECT().GetForType(rule.BlackList).Return(&rule.Rules{}, nil).Maybe()
+	ruleStorage.EXPECT().GetForType(rule.WhiteList).Return(&rule.Rules{}, nil).Maybe()
+	ruleService := rule.NewService(ruleStorage)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 2},
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 10},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 10},
+	}, nil).Maybe()
+
+	newIdentity := func(ip, login string) limiter.UserIdentityDto {
+		return limiter.UserIdentityDto{
+			limiter.IPLimit.String():       ip,
+			limiter.LoginLimit.String():    login,
+			limiter.PasswordLimit.String(): "root",
+		}
+	}
+
+	t.Run("addresses of one network share bucket", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Minute)))
+		loginFormLimiter.SetIPv6Prefix(64)
+
+		for _, ip := range []string{"2001:db8::1", "2001:db8::2"} {
+			satisfies, err := loginFormLimiter.SatisfyLimit(newIdentity(ip, ip))
+			require.NoError(t, err)
+			require.True(t, satisfies)
+		}
+
+		decision, err := loginFormLimiter.Check(newIdentity("2001:db8::3", "other"))
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.IPLimit, decision.LimitType)
+
+		// другая сеть /64 и IPv4 не агрегируются
+		satisfies, err := loginFormLimiter.SatisfyLimit(newIdentity("2001:db8:0:1::1", "other"))
+		require.NoError(t, err)
+		require.True(t, satisfies)
+
+		require.NoError(t, loginFormLimiter.ResetLimit(limiter.UserIdentityDto{
+			limiter.IPLimit.String():    "2001:db8::ffff",
+			limiter.LoginLimit.String(): "other",
+		}))
+		satisfies, err = loginFormLimiter.SatisfyLimit(newIdentity("2001:db8::3", "other"))
+		require.NoError(t, err)
+		require.True(t, satisfies)
+	})
+
+	t.Run("without aggregation", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Minute)))
+
+		for _, ip := range []string{"2001:db8::1", "2001:db8::2", "2001:db8::3"} {
+			satisfies, err := loginFormLimiter.SatisfyLimit(newIdentity(ip, ip))
+			require.NoError(t, err)
+			require.True(t, satisfies)
+		}
+	})
+}
+
+func TestLoginFormLimiter_AutoBan(t *testing.T) {
+	const attackerIP = "6.6.6.6"
+
+	ruleStorage := rulemocks.NewMockIStorage(t)
+	ruleStorage.EXPECT().GetForType(rule.BlackList).Return(&rule.Rules{}, nil).Maybe()
+	ruleStorage.EXPECT().GetForType(rule.WhiteList).Return(&rule.Rules{}, nil).Maybe()
+	ruleService := rule.NewService(ruleStorage)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 1},
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 100},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 100},
+	}, nil).Maybe()
+
+	identity := limiter.UserIdentityDto{
+		limiter.IPLimit.String():       attackerIP,
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): "root",
+	}
+
+	// бан со сроком ttl, добавленный автоматической блокировкой
+	banned := func(ttl time.Duration) any {
+		return mock.MatchedBy(func(r rule.Rule) bool {
+			return r.IP == attackerIP &&
+				r.RuleType == rule.BlackList &&
+				r.Source == rule.SourceAutoBan &&
+				r.CreatedBy == auth.AutoBanCreatedBy &&
+				r.ExpiresAt != nil &&
+				time.Until(*r.ExpiresAt).Round(time.Minute) == ttl
+		})
+	}
+
+	t.Run("ban after threshold with backoff", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Hour)))
+		loginFormLimiter.SetAutoBan(auth.AutoBan{
+			Threshold:  3,
+			Window:     time.Minute,
+			TTL:        time.Hour,
+			Backoff:    2,
+			MaxTTL:     3 * time.Hour,
+			ForgetTime: 24 * time.Hour,
+		})
+
+		satisfies, err := loginFormLimiter.SatisfyLimit(identity)
+		require.NoError(t, err)
+		require.True(t, satisfies)
+
+		for _, ttl := range []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour} {
+			ruleStorage.EXPECT().Create(banned(ttl)).Return(1, nil).Once()
+
+			for range 4 {
+				decision, err := loginFormLimiter.Check(identity)
+				require.NoError(t, err)
+				require.False(t, decision.Allowed)
+			}
+		}
+
+		// после сброса учета IP все равно считается повторным нарушителем
+		loginFormLimiter.SweepAutoBan()
+		ruleStorage.EXPECT().Create(banned(3*time.Hour)).Return(1, nil).Once()
+		for range 4 {
+			_, err := loginFormLimiter.SatisfyLimit(identity)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("ban only after more than threshold denials", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Hour)))
+		loginFormLimiter.SetAutoBan(auth.AutoBan{Threshold: 2, Window: time.Minute, TTL: time.Hour})
+
+		// первый запрос проходит, следующие два отказа не превышают порог
+		for range 3 {
+			_, err := loginFormLimiter.Check(identity)
+			require.NoError(t, err)
+		}
+
+		ruleStorage.EXPECT().Create(banned(time.Hour)).Return(1, nil).Once()
+		decision, err := loginFormLimiter.Check(identity)
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+	})
+
+	t.Run("denials outside window are not counted", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Hour)))
+		loginFormLimiter.SetAutoBan(auth.AutoBan{Threshold: 1, Window: 50 * time.Millisecond, TTL: time.Hour})
+
+		for range 3 {
+			_, err := loginFormLimiter.Check(identity)
+			require.NoError(t, err)
+			time.Sleep(60 * time.Millisecond)
+		}
+	})
+
+	t.Run("ban error does not change decision", func(t *testing.T) {
+		loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Hour)))
+		loginFormLimiter.SetAutoBan(auth.AutoBan{Threshold: 1, Window: time.Minute, TTL: time.Hour})
+
+		for range 2 {
+			_, err := loginFormLimiter.Check(identity)
+			require.NoError(t, err)
+		}
+
+		ruleStorage.EXPECT().Create(mock.Anything).Return(0, errors.New("db is down")).Once()
+		decision, err := loginFormLimiter.Check(identity)
+		require.ErrorIs(t, err, auth.ErrAutoBan)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.ReasonRateLimit, decision.Reason)
+	})
+
+	t.Run("conflicting ban is skipped", func(t *testing.T) {
+		// правило для IP появилось в списке после проверки запроса, например, добавлено другой репликой
+		conflicting := &conflictingRules{
+			IService: ruleService,
+			err: &rule.ConflictError{Conflicts: []rule.Conflict{{
+				Kind: rule.ConflictOverlap,
+				Rule: rule.Rule{ID: 1, IP: attackerIP, RuleType: rule.WhiteList},
+			}}},
+		}
+		loginFormLimiter := auth.New(conflicting, composite.New(limitStorage, refillrate.New(1, time.Hour)))
+		loginFormLimiter.SetAutoBan(auth.AutoBan{Threshold: 1, Window: time.Minute, TTL: time.Hour})
+
+		for range 2 {
+			_, err := loginFormLimiter.Check(identity)
+			require.NoError(t, err)
+		}
+
+		decision, err := loginFormLimiter.Check(identity)
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.ReasonRateLimit, decision.Reason)
+		require.Equal(t, 1, conflicting.added)
+	})
+}
+
+// conflictingRules сервис правил, в котором добавление в черный список конфликтует с существующими правилами.
+type conflictingRules struct {
+	rule.IService
+
+	err   error
+	added int
+}
+
+func (r *conflictingRules) BlackListAdd(string, rule.AddOptions) error {
+	r.added++
+
+	return r.err
+}
+
+func TestLoginFormLimiter_KeyHash(t *testing.T) {
+	ruleStorage := rulemocks.NewMockIStorage(t)
+	ruleStorage.EXPECT().GetForType(rule.BlackList).Return(&rule.Rules{}, nil).Maybe()
+	ruleStorage.EXPECT().GetForType(rule.WhiteList).Return(&rule.Rules{}, nil).Maybe()
+	ruleService := rule.NewService(ruleStorage)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 100},
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 100},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 2},
+	}, nil).Maybe()
+
+	identity := limiter.UserIdentityDto{
+		limiter.IPLimit.String():       "7.7.7.7",
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): "hunter2",
+	}
+
+	hasher, err := keyhash.New([]byte("old"), []string{limiter.PasswordLimit.String()})
+	require.NoError(t, err)
+
+	bucketLimiter := composite.New(limitStorage, refillrate.New(2, time.Hour))
+	loginFormLimiter := auth.New(ruleService, bucketLimiter)
+	loginFormLimiter.SetKeyHasher(hasher)
+
+	for range 2 {
+		decision, err := loginFormLimiter.Check(identity)
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+	}
+
+	for key := range bucketLimiter.GetBuckets() {
+		require.NotContains(t, key, "hunter2")
+	}
+
+	t.Run("counters survive rotation during grace period", func(t *testing.T) {
+		require.NoError(t, hasher.Rotate([]byte("new"), time.Hour, time.Now()))
+
+		decision, err := loginFormLimiter.Check(identity)
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.PasswordLimit, decision.LimitType)
+
+		// отказ по bucket'у предыдущего секрета не расходует токены bucket'ов текущего
+		remaining, err := loginFormLimiter.Remaining(identity)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{
+			limiter.IPLimit.String():       98,
+			limiter.LoginLimit.String():    98,
+			limiter.PasswordLimit.String(): 2,
+		}, remaining)
+
+		satisfies, err := loginFormLimiter.SatisfyLimit(identity)
+		require.NoError(t, err)
+		require.False(t, satisfies)
+
+		remaining, err = loginFormLimiter.Remaining(identity)
+		require.NoError(t, err)
+		require.Equal(t, 98, remaining[limiter.LoginLimit.String()])
+	})
+
+	t.Run("reset clears buckets of both secrets", func(t *testing.T) {
+		require.NoError(t, loginFormLimiter.ResetLimit(identity))
+
+		decision, err := loginFormLimiter.Check(identity)
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+	})
+}
+
+func TestLoginFormLimiter_KeyHashCompositeLimit(t *testing.T) {
+	ruleStorage := rulemocks.NewMockIStorage(t)
+	ruleStorage.EXPECT().GetForType(rule.BlackList).Return(&rule.Rules{}, nil).Maybe()
+	ruleStorage.EXPECT().GetForType(rule.WhiteList).Return(&rule.Rules{}, nil).Maybe()
+	ruleService := rule.NewService(ruleStorage)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 100},
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 100},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 100},
+		limiter.Limit{
+			LimitType: "password+ip",
+			Value:     2,
+			KeyFields: []string{limiter.PasswordLimit.String(), limiter.IPLimit.String()},
+		},
+	}, nil).Maybe()
+
+	identity := limiter.UserIdentityDto{
+		limiter.IPLimit.String():       "7.7.7.7",
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): "hunter2",
+	}
+
+	hasher, err := keyhash.New([]byte("old"), []string{limiter.PasswordLimit.String()})
+	require.NoError(t, err)
+
+	bucketLimiter := composite.New(limitStorage, refillrate.New(2, time.Hour))
+	loginFormLimiter := auth.New(ruleService, bucketLimiter)
+	loginFormLimiter.SetKeyHasher(hasher)
+
+	for range 2 {
+		decision, err := loginFormLimiter.Check(identity)
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+	}
+
+	require.NoError(t, hasher.Rotate([]byte("new"), time.Hour, time.Now()))
+
+	// составной лимит по паролю и ip находит bucket предыдущего секрета
+	decision, err := loginFormLimiter.Check(identity)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, limiter.Type("password+ip"), decision.LimitType)
+
+	// bucket'ы без чувствительных полей общие для обоих секретов и списываются один раз
+	identity[limiter.PasswordLimit.String()] = "qwerty"
+	decision, err = loginFormLimiter.Check(identity)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+
+	remaining, err := loginFormLimiter.Remaining(identity)
+	require.NoError(t, err)
+	require.Equal(t, 97, remaining[limiter.IPLimit.String()])
+	require.Equal(t, 97, remaining[limiter.LoginLimit.String()])
+}
+
+func TestLoginFormLimiter_Dimensions(t *testing.T) {
+	// без ip черный и белый списки не проверяются: у хранилища правил нет ожиданий
+	ruleService := rule.NewService(rulemocks.NewMockIStorage(t))
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 100},
+		limiter.Limit{LimitType: "tenant", Value: 100, Required: true},
+		limiter.Limit{LimitType: "device", Value: 1},
+	}, nil).Maybe()
+
+	loginFormLimiter := auth.New(ruleService, composite.New(limitStorage, refillrate.New(1, time.Hour)))
+
+	t.Run("required dimension", func(t *testing.T) {
+		_, err := loginFormLimiter.Check(limiter.UserIdentityDto{"device": "abc"})
+		require.ErrorIs(t, err, limiter.ErrIncorrectIdentity)
+
+		_, err = loginFormLimiter.Check(limiter.UserIdentityDto{"tenant": "", "device": "abc"})
+		require.ErrorIs(t, err, limiter.ErrIncorrectIdentity)
+	})
+
+	t.Run("optional dimension", func(t *testing.T) {
+		identity := limiter.UserIdentityDto{"tenant": "acme", "device": "abc"}
+
+		decision, err := loginFormLimiter.Check(identity)
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+
+		decision, err = loginFormLimiter.Check(identity)
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.Type("device"), decision.LimitType)
+
+		// пустое значение необязательного измерения не учитывается
+		decision, err = loginFormLimiter.Check(limiter.UserIdentityDto{"tenant": "acme", "device": ""})
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
 	})
 }
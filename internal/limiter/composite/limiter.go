@@ -2,27 +2,67 @@ package composite
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"math"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/snapshot"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/store/memory"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/tokenbucket"
 )
 
 var ErrNoLimitsFound = errors.New("not found any limits for given identity")
 
+// BucketStoreFactory создает хранилище bucket'ов для лимитера заданного типа, работающее по алгоритму algorithm.
+type BucketStoreFactory func(limitType string, algorithm bucket.Algorithm) bucket.IBucketStore
+
+// LimiterFactory создает лимитер для лимита заданного типа с алгоритмом bucket.TokenBucket
+// вместо лимитера на основе хранилища bucket'ов.
+type LimiterFactory func(
+	limitType string,
+	bucketSize int,
+	refillRate refillrate.RefillRate,
+) limiter.ITokenBucketLimitService
+
 // Limiter лимитер с использованием нескольких bucket'ов
-// Набор bucket'ов определяется на основе входных данных в UserIdentityDto (ключей).
+// Набор bucket'ов определяется на основе входных данных в UserIdentityDto (ключей): лимит учитывается,
+// если в identity есть все поля его ключа (для составного ключа, например login и ip, - все поля).
 // Объединение по логике И: для удовлетворения лимиту необходимо "пройти" все bucket'ы.
+//
+// Набор лимитеров не изменяется после создания: Reload заменяет его целиком.
 type Limiter struct {
+	sync.RWMutex
+
 	limitStorage limiter.IStorage
 
 	limiters map[string]limiter.ITokenBucketLimitService
-
-	refillRate  refillrate.RefillRate
-	requestCost int
+	// Лимиты, по которым созданы лимитеры: при смене алгоритма или полей ключа лимитер создается заново.
+	limits map[string]limiter.Limit
+
+	// Скорость пополнения для лимитов, у которых она не задана.
+	refillRate   refillrate.RefillRate
+	requestCost  int
+	storeFactory BucketStoreFactory
+	// Если задана, используется для лимитов с алгоритмом bucket.TokenBucket.
+	limiterFactory LimiterFactory
+
+	// Снимки bucket'ов, восстановленные до инициализации лимитеров.
+	pendingSnapshots snapshot.Snapshots
+
+	// Режим наблюдения для всех лимитов и для отдельных типов лимитов:
+	// токены забираются, но превышение лимита не отклоняет запрос.
+	shadow      bool
+	shadowTypes map[string]bool
+
+	// Блокировки bucket'ов на время проверки и списания токенов.
+	bucketLocks stripedLocks
 }
 
 func New(limitStorage limiter.IStorage, refillRate refillrate.RefillRate) *Limiter {
@@ -30,39 +70,116 @@ func New(limitStorage limiter.IStorage, refillRate refillrate.RefillRate) *Limit
 		limitStorage: limitStorage,
 		refillRate:   refillRate,
 		requestCost:  tokenbucket.DefaultRequestCost,
+		storeFactory: func(_ string, algorithm bucket.Algorithm) bucket.IBucketStore {
+			return memory.NewWithAlgorithm(algorithm)
+		},
 	}
 }
 
+// SetBucketStoreFactory задает хранилище bucket'ов для лимитеров, которые будут созданы после вызова.
+func (o *Limiter) SetBucketStoreFactory(storeFactory BucketStoreFactory) {
+	o.storeFactory = storeFactory
+}
+
+// SetLimiterFactory задает реализацию token bucket'а для лимитеров, которые будут созданы после вызова.
+func (o *Limiter) SetLimiterFactory(limiterFactory LimiterFactory) {
+	o.limiterFactory = limiterFactory
+}
+
+// SetShadow включает режим наблюдения для всех лимитов (enabled) или только для типов limitTypes.
+func (o *Limiter) SetShadow(enabled bool, limitTypes []string) {
+	shadowTypes := make(map[string]bool, len(limitTypes))
+	for _, limitType := range limitTypes {
+		shadowTypes[limitType] = true
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	o.shadow = enabled
+	o.shadowTypes = shadowTypes
+}
+
+// Shadow возвращает текущий режим наблюдения: для всех лимитов и отсортированные типы лимитов.
+func (o *Limiter) Shadow() (bool, []string) {
+	o.RLock()
+	defer o.RUnlock()
+
+	limitTypes := slices.Sorted(maps.Keys(o.shadowTypes))
+
+	return o.shadow, limitTypes
+}
+
 func (o *Limiter) SatisfyLimit(identity limiter.UserIdentityDto) (bool, error) {
-	identityKeys := o.getIdentityKeys(identity)
-	if len(identity) == 0 {
-		return false, limiter.ErrIncorrectIdentity
+	return o.SatisfyLimitAll(identity)
+}
+
+// SatisfyLimitAll проверяет запрос, учитываемый по нескольким identity (например, по ключам текущего
+// и предыдущего секрета хеширования), как один запрос: токены списываются из bucket'ов всех identity,
+// только если запрос проходит лимиты каждого из них.
+func (o *Limiter) SatisfyLimitAll(identities ...limiter.UserIdentityDto) (bool, error) {
+	current, charges, err := o.charges(identities)
+	if err != nil {
+		return false, err
 	}
 
-	if len(o.limiters) == 0 {
-		limitersInitErr := o.init(identityKeys)
-		if limitersInitErr != nil {
-			return false, limitersInitErr
-		}
+	deniedBy, _, err := o.satisfy(current, charges)
+
+	return deniedBy == "" && err == nil, err
+}
+
+// Check проверяет запрос как SatisfyLimit и дополнительно возвращает оставшееся количество попыток
+// по самому строгому лимиту, а для отклоненного запроса - отклонивший лимит и время до повторной попытки.
+// Если запрос превысил только лимиты в режиме наблюдения, он разрешается с признаком Shadow
+// и данными превышенного лимита.
+func (o *Limiter) Check(identity limiter.UserIdentityDto) (limiter.Decision, error) {
+	return o.CheckAll(identity)
+}
+
+// CheckAll проверяет запрос по нескольким identity как SatisfyLimitAll и возвращает решение как Check:
+// остаток попыток - минимальный по bucket'ам всех identity.
+func (o *Limiter) CheckAll(identities ...limiter.UserIdentityDto) (limiter.Decision, error) {
+	current, charges, err := o.charges(identities)
+	if err != nil {
+		return limiter.Decision{}, err
 	}
 
-	for key := range identity {
-		l, found := o.limiters[key]
-		if !found {
-			return false, limiter.ErrIncorrectIdentity
-		}
+	deniedBy, shadowDeniedBy, err := o.satisfy(current, charges)
+	if err != nil {
+		return limiter.Decision{}, err
+	}
 
-		satisfies, checkErr := l.SatisfyLimit(identity)
+	limiters, limits := current.limiters, current.limits
+	decision := limiter.Decision{Allowed: deniedBy == "", Reason: limiter.ReasonPassed, Remaining: math.MaxInt}
+	for _, c := range charges {
+		allowed, checkErr := limiters[c.key].GetRequestsAllowed(c.identity)
 		if checkErr != nil {
-			return false, checkErr
+			return limiter.Decision{}, checkErr
 		}
 
-		if !satisfies {
-			return false, nil // not satisfies if fails at least one limiter
+		if allowed < decision.Remaining {
+			decision.Remaining = allowed
+			decision.Limit = limits[c.key].Value
+			decision.ResetAfter = o.resetAfter(limits[c.key], allowed)
 		}
 	}
 
-	return true, nil // satisfies if pass all limiter
+	if decision.Allowed && shadowDeniedBy != "" {
+		decision.Shadow = true
+		deniedBy = shadowDeniedBy
+	}
+
+	if deniedBy != "" {
+		decision.Reason = limiter.ReasonRateLimit
+		decision.LimitType = limits[deniedBy].LimitType
+		decision.Tier = limits[deniedBy].Tier
+		decision.Remaining = 0
+		decision.Limit = limits[deniedBy].Value
+		decision.RetryAfter = o.retryAfter(limits[deniedBy])
+		decision.ResetAfter = o.resetAfter(limits[deniedBy], 0)
+	}
+
+	return decision, nil
 }
 
 func (o *Limiter) ResetLimit(identity limiter.UserIdentityDto) error {
@@ -70,12 +187,18 @@ func (o *Limiter) ResetLimit(identity limiter.UserIdentityDto) error {
 		return limiter.ErrIncorrectIdentity
 	}
 
-	if len(o.limiters) == 0 {
+	current := o.state()
+	if len(current.limiters) == 0 {
 		return ErrNoLimitsFound
 	}
 
+	identity, err := keyed(current.limits, identity)
+	if err != nil {
+		return err
+	}
+
 	for key := range identity {
-		l, found := o.limiters[key]
+		l, found := current.limiters[key]
 		if !found {
 			return limiter.ErrIncorrectIdentity
 		}
@@ -89,13 +212,134 @@ func (o *Limiter) ResetLimit(identity limiter.UserIdentityDto) error {
 	return nil
 }
 
+// Validate проверяет измерения identity: для каждого измерения должен быть задан лимит,
+// а обязательные измерения (Limit.Required) должны иметь непустые значения.
+func (o *Limiter) Validate(identity limiter.UserIdentityDto) error {
+	if len(identity) == 0 {
+		return limiter.ErrIncorrectIdentity
+	}
+
+	current, err := o.acquire()
+	if err != nil {
+		return err
+	}
+
+	limits := current.limits
+	fields := dimensions(limits)
+	for key := range identity {
+		if !fields[key] {
+			return fmt.Errorf("%w: unknown dimension %q", limiter.ErrIncorrectIdentity, key)
+		}
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(limits)) {
+		if !limits[key].Required {
+			continue
+		}
+
+		for _, field := range limits[key].Fields() {
+			if identity[field] == "" {
+				return fmt.Errorf("%w: dimension %q is required", limiter.ErrIncorrectIdentity, field)
+			}
+		}
+	}
+
+	return nil
+}
+
+// charge bucket лимитера key, из которого списывается токен для keyed identity запроса.
+type charge struct {
+	key      string
+	identity limiter.UserIdentityDto
+}
+
+// charges возвращает текущее состояние лимитеров и bucket'ы запроса: для каждого identity по порядку -
+// bucket'ы его лимитов в порядке ключей лимитеров. Bucket, общий для нескольких identity
+// (например, лимит по логину при смене секрета хеширования пароля), списывается один раз.
+func (o *Limiter) charges(identities []limiter.UserIdentityDto) (state, []charge, error) {
+	if len(identities) == 0 {
+		return state{}, nil, limiter.ErrIncorrectIdentity
+	}
+	for _, identity := range identities {
+		if len(identity) == 0 {
+			return state{}, nil, limiter.ErrIncorrectIdentity
+		}
+	}
+
+	current, limitersInitErr := o.acquire()
+	if limitersInitErr != nil {
+		return state{}, nil, limitersInitErr
+	}
+
+	var charges []charge
+	charged := make(map[string]bool)
+	for _, identity := range identities {
+		keyedIdentity, err := keyed(current.limits, identity)
+		if err != nil {
+			return state{}, nil, err
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(keyedIdentity)) {
+			if _, found := current.limiters[key]; !found {
+				return state{}, nil, limiter.ErrIncorrectIdentity
+			}
+
+			bucketKey := key + limiter.BucketKeySeparator + keyedIdentity[key]
+			if charged[bucketKey] {
+				continue
+			}
+			charged[bucketKey] = true
+
+			charges = append(charges, charge{key: key, identity: keyedIdentity})
+		}
+	}
+
+	return current, charges, nil
+}
+
+// keyed возвращает identity лимитеров: ключи bucket'ов всех лимитов, для которых в identity есть все поля.
+// Поле identity, которое не входит ни в один лимит, или identity, к которому не применим ни один лимит, - ошибка.
+func keyed(limits map[string]limiter.Limit, identity limiter.UserIdentityDto) (limiter.UserIdentityDto, error) {
+	fields := dimensions(limits)
+	for field := range identity {
+		if !fields[field] {
+			return nil, limiter.ErrIncorrectIdentity
+		}
+	}
+
+	keyed := make(limiter.UserIdentityDto, len(limits))
+	for limitType, limit := range limits {
+		if key, found := limit.Key(identity); found {
+			keyed[limitType] = key
+		}
+	}
+
+	if len(keyed) == 0 {
+		return nil, limiter.ErrIncorrectIdentity
+	}
+
+	return keyed, nil
+}
+
+// dimensions возвращает поля identity, по которым считается хотя бы один из лимитов.
+func dimensions(limits map[string]limiter.Limit) map[string]bool {
+	fields := make(map[string]bool, len(limits))
+	for _, limit := range limits {
+		for _, field := range limit.Fields() {
+			fields[field] = true
+		}
+	}
+
+	return fields
+}
+
 func (o *Limiter) SweepBucket(compositeKey string) error {
-	limiterKey, bucketKey, foundSep := strings.Cut(compositeKey, "_")
+	limiterKey, bucketKey, foundSep := strings.Cut(compositeKey, limiter.BucketKeySeparator)
 	if !foundSep {
 		return limiter.ErrIncorrectBucketKey
 	}
 
-	l, foundLimiter := o.limiters[limiterKey]
+	l, foundLimiter := o.current()[limiterKey]
 	if !foundLimiter {
 		return limiter.ErrIncorrectBucketKey
 	}
@@ -104,6 +348,9 @@ func (o *Limiter) SweepBucket(compositeKey string) error {
 }
 
 func (o *Limiter) SetRequestCost(requestCost int) {
+	o.Lock()
+	defer o.Unlock()
+
 	o.requestCost = requestCost
 
 	if len(o.limiters) == 0 {
@@ -117,21 +364,23 @@ func (o *Limiter) SetRequestCost(requestCost int) {
 
 // GetRequestsAllowed возращает минимум из остатков всех лимитеров.
 func (o *Limiter) GetRequestsAllowed(identity limiter.UserIdentityDto) (int, error) {
-	identityKeys := o.getIdentityKeys(identity)
 	if len(identity) == 0 {
 		return 0, limiter.ErrIncorrectIdentity
 	}
 
-	if len(o.limiters) == 0 {
-		limitersInitErr := o.init(identityKeys)
-		if limitersInitErr != nil {
-			return 0, limitersInitErr
-		}
+	current, limitersInitErr := o.acquire()
+	if limitersInitErr != nil {
+		return 0, limitersInitErr
+	}
+
+	identity, err := keyed(current.limits, identity)
+	if err != nil {
+		return 0, err
 	}
 
 	minAllowed := math.MaxInt
 	for key := range identity {
-		l, found := o.limiters[key]
+		l, found := current.limiters[key]
 		if !found {
 			return 0, limiter.ErrIncorrectIdentity
 		}
@@ -147,43 +396,385 @@ func (o *Limiter) GetRequestsAllowed(identity limiter.UserIdentityDto) (int, err
 	return minAllowed, nil
 }
 
+// Remaining возвращает количество возможных запросов identity по каждому из лимитеров
+// (тип лимита или тип и уровень) без списания токенов.
+func (o *Limiter) Remaining(identity limiter.UserIdentityDto) (map[string]int, error) {
+	if len(identity) == 0 {
+		return nil, limiter.ErrIncorrectIdentity
+	}
+
+	current, limitersInitErr := o.acquire()
+	if limitersInitErr != nil {
+		return nil, limitersInitErr
+	}
+
+	identity, err := keyed(current.limits, identity)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := make(map[string]int, len(identity))
+	for key := range identity {
+		l, found := current.limiters[key]
+		if !found {
+			return nil, limiter.ErrIncorrectIdentity
+		}
+
+		allowed, checkErr := l.GetRequestsAllowed(identity)
+		if checkErr != nil {
+			return nil, checkErr
+		}
+
+		remaining[key] = allowed
+	}
+
+	return remaining, nil
+}
+
 func (o *Limiter) GetBuckets() map[string]*bucket.IBucket {
 	buckets := make(map[string]*bucket.IBucket)
 
-	for limiterKey, l := range o.limiters {
+	for limiterKey, l := range o.current() {
 		limiterBuckets := l.GetBuckets()
 		for bucketKey, b := range limiterBuckets {
-			buckets[limiterKey+"_"+bucketKey] = b
+			buckets[limiterKey+limiter.BucketKeySeparator+bucketKey] = b
 		}
 	}
 
 	return buckets
 }
 
-func (o *Limiter) init(identityKeys []string) error {
-	limits, getLimitsErr := o.limitStorage.GetLimitsByTypes(identityKeys)
+// TakeSnapshot собирает изменения bucket'ов всех лимитеров с прошлого вызова.
+func (o *Limiter) TakeSnapshot() (snapshot.Snapshots, snapshot.Snapshots) {
+	changed := make(snapshot.Snapshots, 0)
+	deleted := make(snapshot.Snapshots, 0)
+
+	for limiterKey, l := range o.current() {
+		snapshotLimiter, ok := l.(limiter.ISnapshotLimitService)
+		if !ok {
+			continue
+		}
+
+		states, deletedKeys := snapshotLimiter.TakeSnapshot()
+		for bucketKey, state := range states {
+			changed = append(changed, snapshot.Snapshot{
+				LimitType:  limiterKey,
+				BucketKey:  bucketKey,
+				Tokens:     state.Tokens,
+				LastRefill: state.LastRefill,
+			})
+		}
+		for _, bucketKey := range deletedKeys {
+			deleted = append(deleted, snapshot.Snapshot{
+				LimitType: limiterKey,
+				BucketKey: bucketKey,
+			})
+		}
+	}
+
+	return changed, deleted
+}
+
+// RestoreSnapshot восстанавливает bucket'ы из снимков.
+// Если лимитеры еще не созданы, снимки применяются при их инициализации.
+func (o *Limiter) RestoreSnapshot(snapshots snapshot.Snapshots) {
+	o.Lock()
+	defer o.Unlock()
+
+	o.restoreSnapshot(snapshots)
+}
+
+func (o *Limiter) restoreSnapshot(snapshots snapshot.Snapshots) {
+	for _, s := range snapshots {
+		l, found := o.limiters[s.LimitType]
+		if !found {
+			o.pendingSnapshots = append(o.pendingSnapshots, s)
+
+			continue
+		}
+
+		o.restoreBucket(l, s)
+	}
+}
+
+func (o *Limiter) restoreBucket(l limiter.ITokenBucketLimitService, s snapshot.Snapshot) {
+	snapshotLimiter, ok := l.(limiter.ISnapshotLimitService)
+	if !ok {
+		return
+	}
+
+	snapshotLimiter.RestoreSnapshot(s.BucketKey, bucket.State{
+		Tokens:     s.Tokens,
+		LastRefill: s.LastRefill,
+	})
+}
+
+// Reload перечитывает лимиты из хранилища и атомарно заменяет набор лимитеров.
+// Bucket'ы сохраняются для типов, которые остались в хранилище; при изменении размера
+// израсходованная часть bucket'а переносится на новый размер. Лимитеры удаленных типов отбрасываются.
+// Если лимитеры еще не инициализированы, они будут загружены при первом запросе.
+func (o *Limiter) Reload() error {
+	limits, err := o.limitStorage.GetLimits()
+	if err != nil {
+		return err
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	if len(o.limiters) == 0 {
+		return nil
+	}
+
+	limitsByKey := expand(limits)
+	limiters := make(map[string]limiter.ITokenBucketLimitService, len(limitsByKey))
+	for key, limit := range limitsByKey {
+		current, found := o.limiters[key]
+		resizable, ok := current.(limiter.IResizableLimitService)
+		if found && ok && o.limits[key].Algorithm == limit.Algorithm &&
+			slices.Equal(o.limits[key].KeyFields, limit.KeyFields) {
+			limiters[key] = resizable.Resize(limit.Value, o.limitRefillRate(limit))
+
+			continue
+		}
+
+		limiters[key] = o.newLimiter(key, limit)
+	}
+
+	o.limiters = limiters
+	o.limits = limitsByKey
+
+	return nil
+}
+
+// satisfy проверяет bucket'ы запроса по порядку и возвращает ключ первого отклонившего запрос
+// лимитера (тип лимита или тип и уровень).
+// Токены забираются в две фазы: сначала проверяется остаток всех bucket'ов, и только если запрос проходит
+// все лимиты, токены списываются из каждого. Отклоненный запрос не расходует токены ни одного bucket'а.
+// Лимиты в режиме наблюдения не прерывают проверку: первый из превышенных возвращается вторым значением.
+// Bucket'ы запроса блокируются на время обеих фаз, поэтому параллельные запросы к тем же bucket'ам
+// не списывают токены между проверкой и списанием. Общее хранилище может изменить остаток в обход
+// блокировок: тогда запрос отклоняется, а уже списанные токены возвращаются, если лимитер это поддерживает.
+func (o *Limiter) satisfy(current state, charges []charge) (string, string, error) {
+	limiters := current.limiters
+
+	identities := make([]limiter.UserIdentityDto, 0, len(charges))
+	for _, c := range charges {
+		identities = append(identities, limiter.UserIdentityDto{c.key: c.identity[c.key]})
+	}
+	unlock := o.bucketLocks.lock(identities...)
+	defer unlock()
+
+	deniedBy, shadowDeniedBy := "", ""
+	for _, c := range charges {
+		allowed, checkErr := limiters[c.key].GetRequestsAllowed(c.identity)
+		if checkErr != nil {
+			return "", "", checkErr
+		}
+
+		if allowed > 0 {
+			continue
+		}
+
+		if current.enforced(c.key) {
+			deniedBy = c.key
+
+			break
+		}
+
+		if shadowDeniedBy == "" {
+			shadowDeniedBy = c.key
+		}
+	}
+
+	if deniedBy != "" {
+		return deniedBy, shadowDeniedBy, nil // not satisfies if fails at least one limiter
+	}
+
+	taken := make([]charge, 0, len(charges))
+	for _, c := range charges {
+		satisfies, takeErr := limiters[c.key].SatisfyLimit(c.identity)
+		if takeErr != nil {
+			refund(limiters, taken)
+
+			return "", "", takeErr
+		}
+
+		if satisfies {
+			taken = append(taken, c)
+
+			continue
+		}
+
+		if current.enforced(c.key) {
+			refund(limiters, taken)
+
+			return c.key, shadowDeniedBy, nil
+		}
+
+		if shadowDeniedBy == "" {
+			shadowDeniedBy = c.key
+		}
+	}
+
+	return "", shadowDeniedBy, nil // satisfies if pass all limiter
+}
+
+// refund возвращает токены, списанные для charges, лимитерам, которые поддерживают возврат.
+// Возврат выполняется по возможности: запрос уже отклонен, и ошибка возврата не меняет решение.
+func refund(limiters map[string]limiter.ITokenBucketLimitService, charges []charge) {
+	for _, c := range charges {
+		if refundable, ok := limiters[c.key].(limiter.IRefundableLimitService); ok {
+			_ = refundable.RefundLimit(c.identity)
+		}
+	}
+}
+
+// retryAfter оценивает сверху время, за которое в bucket'е лимита накопятся токены на один запрос.
+// Для скользящих окон это длина окна.
+func (o *Limiter) retryAfter(limit limiter.Limit) time.Duration {
+	refillRate := o.limitRefillRate(limit)
+
+	o.RLock()
+	requestCost := o.requestCost
+	o.RUnlock()
+
+	switch limit.Algorithm {
+	case bucket.SlidingLog, bucket.SlidingCounter:
+		return refillRate.GetTime()
+	case bucket.TokenBucket:
+	}
+
+	count := max(refillRate.GetCount(), 1)
+	periods := (requestCost + count - 1) / count
+
+	return refillRate.GetTime() * time.Duration(periods)
+}
+
+// resetAfter оценивает сверху время, за которое bucket лимита с остатком remaining запросов
+// пополнится полностью. Для скользящих окон это длина окна.
+func (o *Limiter) resetAfter(limit limiter.Limit, remaining int) time.Duration {
+	o.RLock()
+	requestCost := o.requestCost
+	o.RUnlock()
+
+	missing := limit.Value - remaining*requestCost
+	if missing <= 0 {
+		return 0
+	}
+
+	refillRate := o.limitRefillRate(limit)
+	switch limit.Algorithm {
+	case bucket.SlidingLog, bucket.SlidingCounter:
+		return refillRate.GetTime()
+	case bucket.TokenBucket:
+	}
+
+	count := max(refillRate.GetCount(), 1)
+	periods := (missing + count - 1) / count
+
+	return refillRate.GetTime() * time.Duration(periods)
+}
+
+// state набор лимитеров, их лимиты и режим наблюдения, прочитанные вместе: Reload и SetShadow
+// не меняют состояние, уже полученное запросом.
+type state struct {
+	limiters    map[string]limiter.ITokenBucketLimitService
+	limits      map[string]limiter.Limit
+	shadow      bool
+	shadowTypes map[string]bool
+}
+
+// enforced сообщает, отклоняет ли запрос превышение лимитера key, то есть лимит не в режиме наблюдения.
+func (s state) enforced(key string) bool {
+	return !s.shadow && !s.shadowTypes[s.limits[key].LimitType.String()]
+}
+
+// state возвращает текущее состояние лимитеров.
+func (o *Limiter) state() state {
+	o.RLock()
+	defer o.RUnlock()
+
+	return o.stateLocked()
+}
+
+func (o *Limiter) stateLocked() state {
+	return state{limiters: o.limiters, limits: o.limits, shadow: o.shadow, shadowTypes: o.shadowTypes}
+}
+
+// current возвращает текущий набор лимитеров.
+func (o *Limiter) current() map[string]limiter.ITokenBucketLimitService {
+	o.RLock()
+	defer o.RUnlock()
+
+	return o.limiters
+}
+
+// acquire возвращает текущее состояние лимитеров, инициализируя их при первом обращении.
+func (o *Limiter) acquire() (state, error) {
+	if current := o.state(); len(current.limiters) > 0 {
+		return current, nil
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	if len(o.limiters) == 0 {
+		if err := o.init(); err != nil {
+			return state{}, err
+		}
+	}
+
+	return o.stateLocked(), nil
+}
+
+// init создает лимитеры для всех лимитов хранилища: запросы могут содержать любые из измерений.
+func (o *Limiter) init() error {
+	limits, getLimitsErr := o.limitStorage.GetLimits()
 	if getLimitsErr != nil || len(*limits) == 0 {
 		return ErrNoLimitsFound
 	}
 
-	o.limiters = make(map[string]limiter.ITokenBucketLimitService, len(*limits))
-	for _, limit := range *limits {
-		key := limit.LimitType.String()
-		l := tokenbucket.New(key, limit.Value, o.refillRate)
-		l.SetRequestCost(o.requestCost)
-
-		o.limiters[key] = l
+	o.limits = expand(limits)
+	o.limiters = make(map[string]limiter.ITokenBucketLimitService, len(o.limits))
+	for key, limit := range o.limits {
+		o.limiters[key] = o.newLimiter(key, limit)
 	}
 
+	o.restoreSnapshot(o.pendingSnapshots)
+	o.pendingSnapshots = nil
+
 	return nil
 }
 
-func (o *Limiter) getIdentityKeys(identity limiter.UserIdentityDto) []string {
-	keys := make([]string, 0, len(identity))
+// expand возвращает лимиты всех уровней по ключам лимитеров.
+func expand(limits *limiter.Limits) map[string]limiter.Limit {
+	expanded := make(map[string]limiter.Limit, len(*limits))
+	for _, limit := range *limits {
+		maps.Copy(expanded, limit.Expand())
+	}
 
-	for key := range identity {
-		keys = append(keys, key)
+	return expanded
+}
+
+func (o *Limiter) newLimiter(key string, limit limiter.Limit) limiter.ITokenBucketLimitService {
+	refillRate := o.limitRefillRate(limit)
+
+	var l limiter.ITokenBucketLimitService
+	if o.limiterFactory != nil && (limit.Algorithm == "" || limit.Algorithm == bucket.TokenBucket) {
+		l = o.limiterFactory(key, limit.Value, refillRate)
+	} else {
+		l = tokenbucket.NewWithStore(key, limit.Value, refillRate, o.storeFactory(key, limit.Algorithm))
+	}
+	l.SetRequestCost(o.requestCost)
+
+	return l
+}
+
+func (o *Limiter) limitRefillRate(limit limiter.Limit) refillrate.RefillRate {
+	if limit.RefillRate != nil {
+		return *limit.RefillRate
 	}
 
-	return keys
+	return o.refillRate
 }
@@ -1,14 +1,19 @@
 package composite_test
 
 import (
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/composite"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/gcra"
 	limitermocks "github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/mocks"
-	"github.com/stretchr/testify/mock"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/tokenbucket"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -249,6 +254,33 @@ func TestCompositeBucketLimiter_GetRequestsAllowed(t *testing.T) {
 	require.Equal(t, bucketSize-1, allowed)
 }
 
+func TestCompositeBucketLimiter_Remaining(t *testing.T) {
+	types := []limiter.Type{limiter.LoginLimit, limiter.IPLimit}
+	limitStorage := getMockLimitStorage(t, types, []int{3, 2})
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+
+	identity := limiter.UserIdentityDto{
+		limiter.LoginLimit.String(): "lucky",
+		limiter.IPLimit.String():    "192.168.1.1",
+	}
+
+	satisfies, err := compositeLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	remaining, err := compositeLimiter.Remaining(identity)
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{limiter.LoginLimit.String(): 2, limiter.IPLimit.String(): 1}, remaining)
+
+	// чтение остатка не расходует токены
+	remaining, err = compositeLimiter.Remaining(limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"})
+	require.NoError(t, err)
+	require.Equal(t, map[string]int{limiter.LoginLimit.String(): 2}, remaining)
+
+	_, err = compositeLimiter.Remaining(limiter.UserIdentityDto{})
+	require.ErrorIs(t, err, limiter.ErrIncorrectIdentity)
+}
+
 func getMockLimitStorage(t *testing.T, types []limiter.Type, values []int) *limitermocks.MockIStorage {
 	t.Helper()
 
@@ -261,7 +293,617 @@ func getMockLimitStorage(t *testing.T, types []limiter.Type, values []int) *limi
 	}
 
 	limitStorage := limitermocks.NewMockIStorage(t)
-	limitStorage.EXPECT().GetLimitsByTypes(mock.AnythingOfType("[]string")).Return(&mockLimits, nil)
+	limitStorage.EXPECT().GetLimits().Return(&mockLimits, nil)
 
 	return limitStorage
 }
+
+func TestCompositeBucketLimiter_Snapshot(t *testing.T) {
+	refillRate := refillrate.New(1, time.Hour)
+	types := []limiter.Type{limiter.LoginLimit, limiter.IPLimit}
+	identity := limiter.UserIdentityDto{
+		types[0].String(): "lucky",
+		types[1].String(): "192.168.1.1",
+	}
+
+	compositeLimiter := composite.New(getMockLimitStorage(t, types, []int{3, 3}), refillRate)
+	satisfies, err := compositeLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	changed, deleted := compositeLimiter.TakeSnapshot()
+	require.Len(t, changed, 2)
+	require.Empty(t, deleted)
+	for _, s := range changed {
+		require.Equal(t, 2, s.Tokens)
+	}
+
+	// восстановление до инициализации лимитеров применяется при первом запросе
+	restoredLimiter := composite.New(getMockLimitStorage(t, types, []int{3, 3}), refillRate)
+	restoredLimiter.RestoreSnapshot(changed)
+
+	allowed, err := restoredLimiter.GetRequestsAllowed(identity)
+	require.NoError(t, err)
+	require.Equal(t, 2, allowed)
+}
+
+func TestCompositeBucketLimiter_PerLimitRefillRate(t *testing.T) {
+	globalRefillRate := refillrate.New(1, time.Hour)
+	loginRefillRate := refillrate.New(100, time.Second)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 1, RefillRate: &loginRefillRate},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 1},
+	}, nil)
+	compositeLimiter := composite.New(limitStorage, globalRefillRate)
+
+	login := limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"}
+	ip := limiter.UserIdentityDto{limiter.IPLimit.String(): "192.168.1.1"}
+
+	// инициализация лимитеров всеми типами
+	satisfies, err := compositeLimiter.SatisfyLimit(limiter.UserIdentityDto{
+		limiter.LoginLimit.String(): "lucky",
+		limiter.IPLimit.String():    "192.168.1.1",
+	})
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	time.Sleep(time.Millisecond * 50)
+
+	// login пополняется по собственной скорости
+	satisfies, err = compositeLimiter.SatisfyLimit(login)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	// ip - по глобальной
+	satisfies, err = compositeLimiter.SatisfyLimit(ip)
+	require.NoError(t, err)
+	require.False(t, satisfies)
+}
+
+func TestCompositeBucketLimiter_Reload(t *testing.T) {
+	refillRate := refillrate.New(1, time.Hour)
+	identity := limiter.UserIdentityDto{
+		limiter.LoginLimit.String(): "lucky",
+		limiter.IPLimit.String():    "192.168.1.1",
+	}
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	compositeLimiter := composite.New(limitStorage, refillRate)
+
+	// до инициализации перезагружать нечего
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{}, nil).Once()
+	require.NoError(t, compositeLimiter.Reload())
+
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 3},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 5},
+	}, nil).Once()
+
+	satisfies, err := compositeLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 10},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 5},
+	}, nil).Once()
+	require.NoError(t, compositeLimiter.Reload())
+
+	// login увеличен с сохранением израсходованного токена, ip не изменился
+	allowed, err := compositeLimiter.GetRequestsAllowed(limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"})
+	require.NoError(t, err)
+	require.Equal(t, 9, allowed)
+	allowed, err = compositeLimiter.GetRequestsAllowed(limiter.UserIdentityDto{limiter.IPLimit.String(): "192.168.1.1"})
+	require.NoError(t, err)
+	require.Equal(t, 4, allowed)
+
+	// удаленный тип лимита больше не обслуживается, новый - появляется
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 10},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 1},
+	}, nil).Once()
+	require.NoError(t, compositeLimiter.Reload())
+
+	_, err = compositeLimiter.SatisfyLimit(identity)
+	require.ErrorIs(t, err, limiter.ErrIncorrectIdentity)
+	satisfies, err = compositeLimiter.SatisfyLimit(limiter.UserIdentityDto{limiter.PasswordLimit.String(): "123456"})
+	require.NoError(t, err)
+	require.True(t, satisfies)
+}
+
+func TestCompositeBucketLimiter_MixedAlgorithms(t *testing.T) {
+	refillRate := refillrate.New(100, time.Hour)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 2, Algorithm: bucket.SlidingLog},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 3, Algorithm: bucket.SlidingCounter},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 4, Algorithm: bucket.TokenBucket},
+	}, nil)
+	compositeLimiter := composite.New(limitStorage, refillRate)
+
+	identity := limiter.UserIdentityDto{
+		limiter.LoginLimit.String():    "lucky",
+		limiter.IPLimit.String():       "192.168.1.1",
+		limiter.PasswordLimit.String(): "123456",
+	}
+	for range 2 {
+		satisfies, err := compositeLimiter.SatisfyLimit(identity)
+		require.NoError(t, err)
+		require.True(t, satisfies)
+	}
+
+	allowed, err := compositeLimiter.GetRequestsAllowed(limiter.UserIdentityDto{limiter.IPLimit.String(): "192.168.1.1"})
+	require.NoError(t, err)
+	require.Equal(t, 1, allowed)
+
+	// sliding log по login исчерпан
+	satisfies, err := compositeLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.False(t, satisfies)
+}
+
+func TestCompositeBucketLimiter_LimiterFactory(t *testing.T) {
+	types := []limiter.Type{limiter.LoginLimit, limiter.IPLimit}
+	compositeLimiter := composite.New(getMockLimitStorage(t, types, []int{1, 3}), refillrate.New(1, time.Hour))
+	compositeLimiter.SetLimiterFactory(gcra.New)
+
+	identity := limiter.UserIdentityDto{
+		types[0].String(): "lucky",
+		types[1].String(): "192.168.1.1",
+	}
+	satisfies, err := compositeLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	satisfies, err = compositeLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.False(t, satisfies)
+
+	require.NoError(t, compositeLimiter.ResetLimit(identity))
+	allowed, err := compositeLimiter.GetRequestsAllowed(identity)
+	require.NoError(t, err)
+	require.Equal(t, 1, allowed)
+}
+
+func TestCompositeBucketLimiter_Check(t *testing.T) {
+	loginRefillRate := refillrate.New(2, time.Minute)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 2, RefillRate: &loginRefillRate},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 5, Algorithm: bucket.SlidingLog},
+	}, nil)
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+	compositeLimiter.SetRequestCost(1)
+
+	identity := limiter.UserIdentityDto{
+		limiter.LoginLimit.String(): "lucky",
+		limiter.IPLimit.String():    "192.168.1.1",
+	}
+
+	decision, err := compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, 1, decision.Remaining)
+	require.Equal(t, 2, decision.Limit)
+	require.Empty(t, decision.LimitType)
+	// израсходованный токен login пополнится за один период
+	require.Zero(t, decision.RetryAfter)
+	require.Equal(t, time.Minute, decision.ResetAfter)
+
+	_, err = compositeLimiter.Check(identity)
+	require.NoError(t, err)
+
+	// login исчерпан: токен на запрос пополнится за один период
+	decision, err = compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Zero(t, decision.Remaining)
+	require.Equal(t, limiter.LoginLimit, decision.LimitType)
+	require.Equal(t, time.Minute, decision.RetryAfter)
+	require.Equal(t, time.Minute, decision.ResetAfter)
+
+	// для скользящего окна - длина окна
+	ip := limiter.UserIdentityDto{limiter.IPLimit.String(): "10.0.0.1"}
+	compositeLimiter.SetRequestCost(6)
+	decision, err = compositeLimiter.Check(ip)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, limiter.IPLimit, decision.LimitType)
+	require.Equal(t, time.Hour, decision.RetryAfter)
+	require.Equal(t, time.Hour, decision.ResetAfter)
+}
+
+func TestCompositeBucketLimiter_Shadow(t *testing.T) {
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 1},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 2},
+	}, nil)
+
+	newIdentity := func(login string) limiter.UserIdentityDto {
+		return limiter.UserIdentityDto{
+			limiter.LoginLimit.String(): login,
+			limiter.IPLimit.String():    "192.168.1.1",
+		}
+	}
+
+	t.Run("shadow for limit type", func(t *testing.T) {
+		compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+		compositeLimiter.SetShadow(false, []string{limiter.LoginLimit.String()})
+
+		decision, err := compositeLimiter.Check(newIdentity("lucky"))
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+		require.False(t, decision.Shadow)
+
+		// login превышен, но работает в режиме наблюдения: токены ip все равно забираются
+		decision, err = compositeLimiter.Check(newIdentity("lucky"))
+		require.NoError(t, err)
+		require.True(t, decision.Allowed)
+		require.True(t, decision.Shadow)
+		require.Equal(t, limiter.ReasonRateLimit, decision.Reason)
+		require.Equal(t, limiter.LoginLimit, decision.LimitType)
+		require.Equal(t, time.Hour, decision.RetryAfter)
+
+		// ip не в режиме наблюдения и отклоняет запрос
+		decision, err = compositeLimiter.Check(newIdentity("other"))
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.False(t, decision.Shadow)
+		require.Equal(t, limiter.IPLimit, decision.LimitType)
+
+		satisfies, err := compositeLimiter.SatisfyLimit(newIdentity("lucky"))
+		require.NoError(t, err)
+		require.False(t, satisfies)
+	})
+
+	t.Run("global shadow", func(t *testing.T) {
+		compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+		compositeLimiter.SetShadow(true, nil)
+
+		for range 3 {
+			satisfies, err := compositeLimiter.SatisfyLimit(newIdentity("lucky"))
+			require.NoError(t, err)
+			require.True(t, satisfies)
+		}
+
+		remaining, err := compositeLimiter.GetRequestsAllowed(newIdentity("lucky"))
+		require.NoError(t, err)
+		require.Zero(t, remaining)
+
+		// выключение режима наблюдения снова отклоняет запросы
+		compositeLimiter.SetShadow(false, nil)
+		decision, err := compositeLimiter.Check(newIdentity("lucky"))
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.False(t, decision.Shadow)
+	})
+}
+
+func TestCompositeBucketLimiter_Validate(t *testing.T) {
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 1, Required: true},
+		limiter.Limit{LimitType: "device", Value: 1},
+	}, nil).Once()
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+
+	require.ErrorIs(t, compositeLimiter.Validate(limiter.UserIdentityDto{}), limiter.ErrIncorrectIdentity)
+	require.ErrorIs(t, compositeLimiter.Validate(limiter.UserIdentityDto{"device": "abc"}), limiter.ErrIncorrectIdentity)
+	require.ErrorIs(t, compositeLimiter.Validate(limiter.UserIdentityDto{
+		limiter.LoginLimit.String(): "lucky",
+		"asn":                       "AS13335",
+	}), limiter.ErrIncorrectIdentity)
+
+	require.NoError(t, compositeLimiter.Validate(limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"}))
+	require.NoError(t, compositeLimiter.Validate(limiter.UserIdentityDto{
+		limiter.LoginLimit.String(): "lucky",
+		"device":                    "abc",
+	}))
+}
+
+func TestCompositeBucketLimiter_KeyFields(t *testing.T) {
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 100},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 100},
+		limiter.Limit{LimitType: "login+ip", Value: 2, KeyFields: []string{"login", "ip"}},
+	}, nil).Once()
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+
+	identity := limiter.UserIdentityDto{
+		limiter.LoginLimit.String(): "lucky",
+		limiter.IPLimit.String():    "1.1.1.1",
+	}
+
+	for range 2 {
+		satisfies, err := compositeLimiter.SatisfyLimit(identity)
+		require.NoError(t, err)
+		require.True(t, satisfies)
+	}
+
+	decision, err := compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, limiter.Type("login+ip"), decision.LimitType)
+	require.Contains(t, compositeLimiter.GetBuckets(), "login+ip_lucky|1.1.1.1")
+
+	// тот же логин с другого IP - другая пара
+	decision, err = compositeLimiter.Check(limiter.UserIdentityDto{
+		limiter.LoginLimit.String(): "lucky",
+		limiter.IPLimit.String():    "2.2.2.2",
+	})
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+
+	// без ip составной лимит не учитывается
+	allowed, err := compositeLimiter.GetRequestsAllowed(limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"})
+	require.NoError(t, err)
+	require.Greater(t, allowed, 2)
+
+	// сброс по логину и IP сбрасывает и пару
+	require.NoError(t, compositeLimiter.ResetLimit(identity))
+	satisfies, err := compositeLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+}
+
+func TestCompositeBucketLimiter_AllOrNothing(t *testing.T) {
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 5},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 1},
+	}, nil).Once()
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+
+	identity := limiter.UserIdentityDto{
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): "root",
+	}
+	login := limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"}
+
+	satisfies, err := compositeLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	// отказ по паролю не расходует токены логина
+	for range 3 {
+		satisfies, err = compositeLimiter.SatisfyLimit(identity)
+		require.NoError(t, err)
+		require.False(t, satisfies)
+	}
+
+	allowed, err := compositeLimiter.GetRequestsAllowed(login)
+	require.NoError(t, err)
+	require.Equal(t, 4, allowed)
+
+	// при нескольких превышенных лимитах отклоняет всегда первый по типу
+	for range 4 {
+		_, err = compositeLimiter.SatisfyLimit(login)
+		require.NoError(t, err)
+	}
+	for range 10 {
+		decision, err := compositeLimiter.Check(identity)
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.LoginLimit, decision.LimitType)
+	}
+}
+
+func TestCompositeBucketLimiter_AllOrNothing_CommitFailure(t *testing.T) {
+	identity := limiter.UserIdentityDto{
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): "root",
+	}
+	login := limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"}
+
+	newLimiter := func(t *testing.T) *composite.Limiter {
+		t.Helper()
+
+		limitStorage := limitermocks.NewMockIStorage(t)
+		limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+			limiter.Limit{LimitType: limiter.LoginLimit, Value: 5},
+			limiter.Limit{LimitType: limiter.PasswordLimit, Value: 5},
+		}, nil).Once()
+
+		compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+		compositeLimiter.SetLimiterFactory(func(
+			limitType string,
+			bucketSize int,
+			refillRate refillrate.RefillRate,
+		) limiter.ITokenBucketLimitService {
+			l := tokenbucket.New(limitType, bucketSize, refillRate)
+			if limitType == limiter.PasswordLimit.String() {
+				return &drainedLimiter{ITokenBucketLimitService: l}
+			}
+
+			return l
+		})
+
+		return compositeLimiter
+	}
+
+	t.Run("denied and refunded", func(t *testing.T) {
+		compositeLimiter := newLimiter(t)
+
+		decision, err := compositeLimiter.Check(identity)
+		require.NoError(t, err)
+		require.False(t, decision.Allowed)
+		require.Equal(t, limiter.PasswordLimit, decision.LimitType)
+
+		// токен логина, списанный до отказа по паролю, возвращен
+		allowed, err := compositeLimiter.GetRequestsAllowed(login)
+		require.NoError(t, err)
+		require.Equal(t, 5, allowed)
+	})
+
+	t.Run("shadow limit does not deny", func(t *testing.T) {
+		compositeLimiter := newLimiter(t)
+		compositeLimiter.SetShadow(false, []string{limiter.PasswordLimit.String()})
+
+		satisfies, err := compositeLimiter.SatisfyLimit(identity)
+		require.NoError(t, err)
+		require.True(t, satisfies)
+
+		allowed, err := compositeLimiter.GetRequestsAllowed(login)
+		require.NoError(t, err)
+		require.Equal(t, 4, allowed)
+	})
+}
+
+// drainedLimiter лимитер, остаток которого успевают израсходовать между проверкой и списанием,
+// например, другие реплики с общим хранилищем bucket'ов.
+type drainedLimiter struct {
+	limiter.ITokenBucketLimitService
+}
+
+func (l *drainedLimiter) SatisfyLimit(limiter.UserIdentityDto) (bool, error) {
+	return false, nil
+}
+
+func TestCompositeBucketLimiter_AllOrNothing_Identities(t *testing.T) {
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 5},
+		limiter.Limit{LimitType: limiter.PasswordLimit, Value: 1},
+	}, nil).Once()
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+
+	current := limiter.UserIdentityDto{
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): "new",
+	}
+	previous := limiter.UserIdentityDto{limiter.PasswordLimit.String(): "old"}
+
+	satisfies, err := compositeLimiter.SatisfyLimit(previous)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	// отказ по второму identity не расходует токены первого
+	satisfies, err = compositeLimiter.SatisfyLimitAll(current, previous)
+	require.NoError(t, err)
+	require.False(t, satisfies)
+
+	decision, err := compositeLimiter.CheckAll(current, previous)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, limiter.PasswordLimit, decision.LimitType)
+
+	allowed, err := compositeLimiter.GetRequestsAllowed(current)
+	require.NoError(t, err)
+	require.Equal(t, 1, allowed)
+
+	// запрос, прошедший лимиты обоих identity, расходует токены каждого
+	require.NoError(t, compositeLimiter.ResetLimit(previous))
+	decision, err = compositeLimiter.CheckAll(current, previous)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, 0, decision.Remaining)
+
+	_, err = compositeLimiter.SatisfyLimitAll()
+	require.ErrorIs(t, err, limiter.ErrIncorrectIdentity)
+}
+
+func TestCompositeBucketLimiter_AllOrNothing_Concurrent(t *testing.T) {
+	const (
+		requests   = 200
+		loginLimit = 50
+		ipLimit    = 10
+	)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: loginLimit},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: ipLimit},
+	}, nil).Once()
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+
+	var (
+		wg      sync.WaitGroup
+		allowed atomic.Int32
+	)
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// все запросы одного логина с двух адресов
+			satisfies, err := compositeLimiter.SatisfyLimit(limiter.UserIdentityDto{
+				limiter.LoginLimit.String(): "lucky",
+				limiter.IPLimit.String():    []string{"1.1.1.1", "2.2.2.2"}[i%2],
+			})
+			assert.NoError(t, err)
+			if satisfies {
+				allowed.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// проходят только запросы в пределах лимитов адресов, и только они расходуют токены логина
+	require.Equal(t, int32(2*ipLimit), allowed.Load())
+
+	remaining, err := compositeLimiter.GetRequestsAllowed(limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"})
+	require.NoError(t, err)
+	require.Equal(t, loginLimit-2*ipLimit, remaining)
+}
+
+func TestCompositeBucketLimiter_Tiers(t *testing.T) {
+	day := refillrate.New(1, 24*time.Hour)
+
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{
+			LimitType: limiter.LoginLimit,
+			Value:     2,
+			Tiers:     []limiter.Tier{{Name: "day", Value: 3, RefillRate: &day}},
+		},
+	}, nil).Once()
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+
+	identity := limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"}
+
+	decision, err := compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.Equal(t, 1, decision.Remaining)
+	require.Equal(t, 2, decision.Limit)
+
+	decision, err = compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+
+	// основной уровень исчерпан раньше суточного
+	decision, err = compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, limiter.LoginLimit, decision.LimitType)
+	require.Empty(t, decision.Tier)
+	require.Equal(t, time.Hour, decision.RetryAfter)
+
+	// после сброса основного уровня срабатывает суточный
+	require.NoError(t, compositeLimiter.SweepBucket("login_lucky"))
+
+	decision, err = compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+
+	decision, err = compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.False(t, decision.Allowed)
+	require.Equal(t, limiter.LoginLimit, decision.LimitType)
+	require.Equal(t, "day", decision.Tier)
+	require.Equal(t, 3, decision.Limit)
+	require.Equal(t, 24*time.Hour, decision.RetryAfter)
+
+	// уровни в режиме наблюдения вместе с типом лимита
+	compositeLimiter.SetShadow(false, []string{limiter.LoginLimit.String()})
+	decision, err = compositeLimiter.Check(identity)
+	require.NoError(t, err)
+	require.True(t, decision.Allowed)
+	require.True(t, decision.Shadow)
+}
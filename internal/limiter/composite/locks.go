@@ -0,0 +1,46 @@
+package composite
+
+import (
+	"hash/fnv"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+)
+
+// lockStripes количество блокировок, между которыми распределяются bucket'ы.
+const lockStripes = 256
+
+// stripedLocks блокировки bucket'ов по хешу ключа. Запросы к разным bucket'ам, как правило,
+// не ждут друг друга, а запросы к общему bucket'у выполняются по очереди.
+type stripedLocks struct {
+	stripes [lockStripes]sync.Mutex
+}
+
+// lock блокирует bucket'ы identities и возвращает функцию снятия блокировок. Блокировки берутся в порядке
+// возрастания номеров, поэтому запросы с пересекающимися наборами bucket'ов не ждут друг друга взаимно.
+func (s *stripedLocks) lock(identities ...limiter.UserIdentityDto) func() {
+	indexes := make(map[uint32]struct{})
+	for _, identity := range identities {
+		for key, value := range identity {
+			hash := fnv.New32a()
+			hash.Write([]byte(key))
+			hash.Write([]byte{0})
+			hash.Write([]byte(value))
+
+			indexes[hash.Sum32()%lockStripes] = struct{}{}
+		}
+	}
+
+	sorted := slices.Sorted(maps.Keys(indexes))
+	for _, index := range sorted {
+		s.stripes[index].Lock()
+	}
+
+	return func() {
+		for _, index := range slices.Backward(sorted) {
+			s.stripes[index].Unlock()
+		}
+	}
+}
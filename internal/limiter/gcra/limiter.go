@@ -0,0 +1,211 @@
+package gcra
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+)
+
+const DefaultRequestCost = 1
+
+// Limiter позволяет задать rate limit для запросов с использованием алгоритма GCRA (generic cell rate algorithm).
+//
+// Эквивалентен token bucket'у того же размера и скорости пополнения, но для каждого клиента хранит
+// лишь теоретическое время прибытия следующего запроса (TAT): bucket полон, когда TAT в прошлом,
+// а каждый токен сдвигает TAT на интервал пополнения одного токена.
+type Limiter struct {
+	state *state
+
+	// Интервал пополнения одного токена.
+	emissionInterval time.Duration
+	// Допустимое опережение TAT относительно текущего времени: размер bucket'а в единицах времени.
+	burstTolerance time.Duration
+
+	bucketSize       int
+	bucketRefillRate refillrate.RefillRate
+
+	// Количество токенов, которое тратится на один запрос при вызове SatisfyLimit.
+	// По умолчанию - 1.
+	requestCost int
+
+	// Ключ, по которому происходит поиск идентификационных данных в UserIdentityDto.
+	bucketKey string
+}
+
+// state TAT клиентов в наносекундах Unix-времени. Общий для лимитеров, созданных через Resize.
+type state struct {
+	sync.Mutex
+
+	tat map[string]int64
+}
+
+func New(bucketKey string, bucketSize int, refillRate refillrate.RefillRate) limiter.ITokenBucketLimitService {
+	return newLimiter(&state{tat: make(map[string]int64)}, bucketKey, bucketSize, refillRate, DefaultRequestCost)
+}
+
+func newLimiter(
+	s *state,
+	bucketKey string,
+	bucketSize int,
+	refillRate refillrate.RefillRate,
+	requestCost int,
+) *Limiter {
+	emissionInterval := refillRate.GetTime() / time.Duration(max(refillRate.GetCount(), 1))
+
+	return &Limiter{
+		state: s,
+
+		emissionInterval: emissionInterval,
+		burstTolerance:   emissionInterval * time.Duration(bucketSize),
+
+		bucketSize:       bucketSize,
+		bucketRefillRate: refillRate,
+		requestCost:      requestCost,
+		bucketKey:        bucketKey,
+	}
+}
+
+// SatisfyLimit проверяет возможность выполнения запроса для identity c учетом текущей стоимости запроса
+// и при успехе сдвигает TAT клиента на стоимость запроса.
+func (l *Limiter) SatisfyLimit(identity limiter.UserIdentityDto) (bool, error) {
+	identityValue, found := identity[l.bucketKey]
+	if !found {
+		return false, limiter.ErrIncorrectIdentity
+	}
+
+	return l.take(identityValue, l.requestCost), nil
+}
+
+func (l *Limiter) ResetLimit(identity limiter.UserIdentityDto) error {
+	identityValue, found := identity[l.bucketKey]
+	if !found {
+		return limiter.ErrIncorrectIdentity
+	}
+
+	return l.SweepBucket(identityValue)
+}
+
+// SweepBucket удаляет TAT клиента: отсутствующий TAT соответствует полному bucket'у.
+func (l *Limiter) SweepBucket(bucketKey string) error {
+	l.state.Lock()
+	delete(l.state.tat, bucketKey)
+	l.state.Unlock()
+
+	return nil
+}
+
+func (l *Limiter) SetRequestCost(requestCost int) {
+	l.requestCost = requestCost
+}
+
+// GetRequestsAllowed возвращает количество возможных запросов для identity с учетом текущей стоимости запроса.
+func (l *Limiter) GetRequestsAllowed(identity limiter.UserIdentityDto) (int, error) {
+	identityValue, found := identity[l.bucketKey]
+	if !found {
+		return 0, limiter.ErrIncorrectIdentity
+	}
+
+	return l.tokens(identityValue) / l.requestCost, nil
+}
+
+// GetBuckets возвращает представления TAT клиентов в виде bucket'ов для сборщика мусора.
+// Представления создаются при каждом вызове и не хранятся в лимитере.
+func (l *Limiter) GetBuckets() map[string]*bucket.IBucket {
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	buckets := make(map[string]*bucket.IBucket, len(l.state.tat))
+	for key := range l.state.tat {
+		var b bucket.IBucket = &view{limiter: l, key: key}
+		buckets[key] = &b
+	}
+
+	return buckets
+}
+
+// Resize создает лимитер с новыми размером и скоростью пополнения, использующий те же TAT клиентов.
+// Израсходованная часть bucket'а хранится в единицах времени, поэтому сохраняется без пересчета.
+func (l *Limiter) Resize(bucketSize int, refillRate refillrate.RefillRate) limiter.ITokenBucketLimitService {
+	if bucketSize == l.bucketSize && refillRate == l.bucketRefillRate {
+		return l
+	}
+
+	return newLimiter(l.state, l.bucketKey, bucketSize, refillRate, l.requestCost)
+}
+
+func (l *Limiter) take(key string, cost int) bool {
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	now := time.Now().UnixNano()
+	newTat := l.arrival(key, now) + int64(l.emissionInterval)*int64(cost)
+	if newTat-now > int64(l.burstTolerance) {
+		return false
+	}
+
+	l.state.tat[key] = newTat
+
+	return true
+}
+
+func (l *Limiter) tokens(key string) int {
+	l.state.Lock()
+	defer l.state.Unlock()
+
+	if l.emissionInterval <= 0 {
+		return l.bucketSize
+	}
+
+	now := time.Now().UnixNano()
+	debt := l.arrival(key, now) - now
+
+	return max(0, int((int64(l.burstTolerance)-debt)/int64(l.emissionInterval)))
+}
+
+// arrival возвращает TAT клиента, но не раньше now.
+func (l *Limiter) arrival(key string, now int64) int64 {
+	return max(l.state.tat[key], now)
+}
+
+// view представление TAT клиента в виде bucket'а.
+type view struct {
+	limiter *Limiter
+	key     string
+}
+
+func (v *view) GetSize() int {
+	return v.limiter.bucketSize
+}
+
+func (v *view) GetTokenCount() int {
+	return v.limiter.tokens(v.key)
+}
+
+// GetLastRefill возвращает момент, когда bucket полностью пополнится (или пополнился).
+func (v *view) GetLastRefill() time.Time {
+	v.limiter.state.Lock()
+	defer v.limiter.state.Unlock()
+
+	return time.Unix(0, v.limiter.state.tat[v.key])
+}
+
+func (v *view) GetToken(tokenCount int) {
+	v.limiter.take(v.key, tokenCount)
+}
+
+// Refill ничего не делает: пополнение вычисляется из TAT.
+func (v *view) Refill() {}
+
+func (v *view) Reset() {
+	_ = v.limiter.SweepBucket(v.key)
+}
+
+func (v *view) Full() bool {
+	v.limiter.state.Lock()
+	defer v.limiter.state.Unlock()
+
+	return v.limiter.state.tat[v.key] <= time.Now().UnixNano()
+}
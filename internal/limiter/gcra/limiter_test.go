@@ -0,0 +1,137 @@
+package gcra_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/gcra"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/tokenbucket/gb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGCRALimiter_SatisfyLimit(t *testing.T) {
+	bucketKey := "ip"
+	identity := limiter.UserIdentityDto{bucketKey: "192.168.1.1"}
+
+	t.Run("limit reached", func(t *testing.T) {
+		bucketSize := 3
+		gcraLimiter := gcra.New(bucketKey, bucketSize, refillrate.New(1, time.Hour))
+
+		for i := 0; i < bucketSize; i++ {
+			satisfies, err := gcraLimiter.SatisfyLimit(identity)
+			require.NoError(t, err)
+			require.True(t, satisfies)
+		}
+
+		satisfies, err := gcraLimiter.SatisfyLimit(identity)
+		require.NoError(t, err)
+		require.False(t, satisfies)
+
+		// другой клиент не затронут
+		satisfies, err = gcraLimiter.SatisfyLimit(limiter.UserIdentityDto{bucketKey: "10.0.0.1"})
+		require.NoError(t, err)
+		require.True(t, satisfies)
+	})
+
+	t.Run("too expensive request", func(t *testing.T) {
+		bucketSize := 3
+		gcraLimiter := gcra.New(bucketKey, bucketSize, refillrate.New(1, time.Hour))
+
+		gcraLimiter.SetRequestCost(bucketSize + 1)
+		satisfies, err := gcraLimiter.SatisfyLimit(identity)
+		require.NoError(t, err)
+		require.False(t, satisfies)
+	})
+
+	t.Run("refill", func(t *testing.T) {
+		bucketSize := 3
+		refillRate := refillrate.New(3, 300*time.Millisecond)
+		gcraLimiter := gcra.New(bucketKey, bucketSize, refillRate)
+
+		for i := 0; i < bucketSize; i++ {
+			satisfies, err := gcraLimiter.SatisfyLimit(identity)
+			require.NoError(t, err)
+			require.True(t, satisfies)
+		}
+
+		// один токен пополняется за 100мс
+		time.Sleep(150 * time.Millisecond)
+		allowed, err := gcraLimiter.GetRequestsAllowed(identity)
+		require.NoError(t, err)
+		require.Equal(t, 1, allowed)
+
+		time.Sleep(refillRate.GetTime())
+		allowed, err = gcraLimiter.GetRequestsAllowed(identity)
+		require.NoError(t, err)
+		require.Equal(t, bucketSize, allowed)
+	})
+}
+
+func TestGCRALimiter_Errors(t *testing.T) {
+	gcraLimiter := gcra.New("ip", 3, refillrate.New(1, time.Hour))
+	identity := limiter.UserIdentityDto{"login": "lucky"}
+
+	_, err := gcraLimiter.SatisfyLimit(identity)
+	require.ErrorIs(t, err, limiter.ErrIncorrectIdentity)
+
+	_, err = gcraLimiter.GetRequestsAllowed(identity)
+	require.ErrorIs(t, err, limiter.ErrIncorrectIdentity)
+
+	err = gcraLimiter.ResetLimit(identity)
+	require.ErrorIs(t, err, limiter.ErrIncorrectIdentity)
+}
+
+func TestGCRALimiter_ResetLimit(t *testing.T) {
+	identity := limiter.UserIdentityDto{"ip": "192.168.1.1"}
+	gcraLimiter := gcra.New("ip", 2, refillrate.New(1, time.Hour))
+	gcraLimiter.SetRequestCost(2)
+
+	satisfies, err := gcraLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	require.NoError(t, gcraLimiter.ResetLimit(identity))
+
+	satisfies, err = gcraLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+}
+
+func TestGCRALimiter_Sweep(t *testing.T) {
+	refillRate := refillrate.New(1, 50*time.Millisecond)
+	gcraLimiter := gcra.New("ip", 3, refillRate)
+
+	_, err := gcraLimiter.SatisfyLimit(limiter.UserIdentityDto{"ip": "192.168.1.1"})
+	require.NoError(t, err)
+
+	buckets := gcraLimiter.GetBuckets()
+	require.Len(t, buckets, 1)
+	require.False(t, (*buckets["192.168.1.1"]).Full())
+	require.Equal(t, 2, (*buckets["192.168.1.1"]).GetTokenCount())
+
+	// bucket пополнился и устарел
+	time.Sleep(refillRate.GetTime() * 2)
+	require.NoError(t, gb.New(gcraLimiter, refillRate.GetTime()/2).Sweep())
+	require.Empty(t, gcraLimiter.GetBuckets())
+}
+
+func TestGCRALimiter_Resize(t *testing.T) {
+	identity := limiter.UserIdentityDto{"ip": "192.168.1.1"}
+	refillRate := refillrate.New(1, time.Hour)
+	gcraLimiter := gcra.New("ip", 3, refillRate)
+
+	satisfies, err := gcraLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	resizable, ok := gcraLimiter.(limiter.IResizableLimitService)
+	require.True(t, ok)
+
+	// израсходованный токен сохраняется
+	resized := resizable.Resize(10, refillRate)
+	allowed, err := resized.GetRequestsAllowed(identity)
+	require.NoError(t, err)
+	require.Equal(t, 9, allowed)
+}
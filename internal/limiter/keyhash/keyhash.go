@@ -0,0 +1,122 @@
+package keyhash
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+)
+
+var ErrEmptySecret = errors.New("key hash secret is empty")
+
+// secretSize размер случайного секрета, если секрет не задан в конфигурации.
+const secretSize = 32
+
+// Hasher заменяет значения чувствительных ключей identity (например, пароль) на HMAC-SHA256 с секретом,
+// чтобы открытые значения не попадали в ключи bucket'ов, снимки и внешние хранилища.
+// После смены секрета предыдущий действует grace-период: bucket'ы, созданные до смены,
+// продолжают учитываться, и смена секрета не сбрасывает счетчики.
+type Hasher struct {
+	sync.RWMutex
+
+	types    map[string]bool
+	current  []byte
+	previous []byte
+	// Время, до которого действует предыдущий секрет.
+	previousUntil time.Time
+}
+
+// New создает Hasher для типов лимитов limitTypes.
+func New(secret []byte, limitTypes []string) (*Hasher, error) {
+	if len(secret) == 0 {
+		return nil, ErrEmptySecret
+	}
+
+	types := make(map[string]bool, len(limitTypes))
+	for _, limitType := range limitTypes {
+		types[limitType] = true
+	}
+
+	return &Hasher{types: types, current: secret}, nil
+}
+
+// RandomSecret возвращает случайный секрет. Ключи bucket'ов с таким секретом не совпадают между запусками.
+func RandomSecret() ([]byte, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// Rotate делает secret текущим секретом, смененным в момент now. Предыдущий секрет действует еще grace после now.
+func (h *Hasher) Rotate(secret []byte, grace time.Duration, now time.Time) error {
+	if len(secret) == 0 {
+		return ErrEmptySecret
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	h.previous = h.current
+	h.previousUntil = now.Add(grace)
+	h.current = secret
+
+	return nil
+}
+
+// Hash возвращает копию identity, в которой значения чувствительных ключей заменены HMAC с текущим секретом.
+func (h *Hasher) Hash(identity limiter.UserIdentityDto) limiter.UserIdentityDto {
+	h.RLock()
+	defer h.RUnlock()
+
+	hashed := maps.Clone(identity)
+	for key, value := range identity {
+		if h.types[key] {
+			hashed[key] = sum(h.current, value)
+		}
+	}
+
+	return hashed
+}
+
+// Previous возвращает копию identity, в которой значения чувствительных ключей заменены HMAC
+// с предыдущим секретом, если к моменту now grace-период еще не истек и в identity есть чувствительные ключи.
+// Остальные поля сохраняются, чтобы составные лимиты (например, password и ip) находили свои bucket'ы.
+func (h *Hasher) Previous(identity limiter.UserIdentityDto, now time.Time) (limiter.UserIdentityDto, bool) {
+	h.RLock()
+	defer h.RUnlock()
+
+	if h.previous == nil || !now.Before(h.previousUntil) {
+		return nil, false
+	}
+
+	previous := maps.Clone(identity)
+	sensitive := false
+	for key, value := range identity {
+		if h.types[key] {
+			previous[key] = sum(h.previous, value)
+			sensitive = true
+		}
+	}
+
+	if !sensitive {
+		return nil, false
+	}
+
+	return previous, true
+}
+
+func sum(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(value))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,82 @@
+package keyhash_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/keyhash"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasher_Hash(t *testing.T) {
+	hasher, err := keyhash.New([]byte("secret"), []string{limiter.PasswordLimit.String()})
+	require.NoError(t, err)
+
+	identity := limiter.UserIdentityDto{
+		limiter.IPLimit.String():       "1.1.1.1",
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): "root",
+	}
+
+	hashed := hasher.Hash(identity)
+	require.Equal(t, "1.1.1.1", hashed[limiter.IPLimit.String()])
+	require.Equal(t, "lucky", hashed[limiter.LoginLimit.String()])
+	require.NotEqual(t, "root", hashed[limiter.PasswordLimit.String()])
+	require.Len(t, hashed[limiter.PasswordLimit.String()], 64)
+
+	// исходный identity не изменяется, результат детерминирован
+	require.Equal(t, "root", identity[limiter.PasswordLimit.String()])
+	require.Equal(t, hashed, hasher.Hash(identity))
+
+	other, err := keyhash.New([]byte("other"), []string{limiter.PasswordLimit.String()})
+	require.NoError(t, err)
+	require.NotEqual(t, hashed[limiter.PasswordLimit.String()], other.Hash(identity)[limiter.PasswordLimit.String()])
+}
+
+func TestHasher_Rotate(t *testing.T) {
+	now := time.Now()
+	identity := limiter.UserIdentityDto{
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): "root",
+	}
+
+	hasher, err := keyhash.New([]byte("old"), []string{limiter.PasswordLimit.String()})
+	require.NoError(t, err)
+
+	_, ok := hasher.Previous(identity, now)
+	require.False(t, ok)
+
+	before := hasher.Hash(identity)
+	require.NoError(t, hasher.Rotate([]byte("new"), time.Hour, now))
+
+	after := hasher.Hash(identity)
+	require.NotEqual(t, before[limiter.PasswordLimit.String()], after[limiter.PasswordLimit.String()])
+
+	previous, ok := hasher.Previous(identity, now.Add(30*time.Minute))
+	require.True(t, ok)
+	require.Equal(t, limiter.UserIdentityDto{
+		limiter.LoginLimit.String():    "lucky",
+		limiter.PasswordLimit.String(): before[limiter.PasswordLimit.String()],
+	}, previous)
+
+	_, ok = hasher.Previous(identity, now.Add(time.Hour))
+	require.False(t, ok)
+
+	// identity без чувствительных ключей не учитывается по предыдущему секрету
+	_, ok = hasher.Previous(limiter.UserIdentityDto{limiter.LoginLimit.String(): "lucky"}, now)
+	require.False(t, ok)
+}
+
+func TestHasher_EmptySecret(t *testing.T) {
+	_, err := keyhash.New(nil, []string{limiter.PasswordLimit.String()})
+	require.ErrorIs(t, err, keyhash.ErrEmptySecret)
+
+	hasher, err := keyhash.New([]byte("secret"), nil)
+	require.NoError(t, err)
+	require.ErrorIs(t, hasher.Rotate(nil, time.Hour, time.Now()), keyhash.ErrEmptySecret)
+
+	secret, err := keyhash.RandomSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, 32)
+}
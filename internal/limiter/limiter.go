@@ -2,8 +2,12 @@ package limiter
 
 import (
 	"errors"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
 )
 
 const (
@@ -12,11 +16,19 @@ const (
 	IPLimit       Type = "ip"
 )
 
+// LimitsChangedChannel канал уведомлений PostgreSQL об изменении таблицы rate_limit.
+const LimitsChangedChannel = "rate_limit_changed"
+
 var (
 	// ErrIncorrectIdentity Ошибка на случай некорректного входного аргумента identity.
 	ErrIncorrectIdentity  = errors.New("not found appropriate key in user identity")
 	ErrNotSupported       = errors.New("operation not supported")
 	ErrIncorrectBucketKey = errors.New("incorrect bucket key")
+	ErrIncorrectRefill    = errors.New("incorrect limit refill rate")
+	ErrIncorrectAlgorithm = errors.New("incorrect limit algorithm")
+	ErrIncorrectKeyFields = errors.New("incorrect limit key fields")
+	ErrIncorrectTiers     = errors.New("incorrect limit tiers")
+	ErrIncorrectLimitType = errors.New("incorrect limit type")
 )
 
 type Type string
@@ -31,12 +43,144 @@ type Limit struct {
 	LimitType   Type
 	Value       int
 	Description string
+	// Скорость пополнения bucket'ов лимита. Если не задана, используется глобальная из конфигурации.
+	RefillRate *refillrate.RefillRate
+	// Алгоритм bucket'ов лимита. Для скользящих окон длина окна равна периоду скорости пополнения.
+	Algorithm bucket.Algorithm
+	// Измерение лимита обязательно в каждом запросе на проверку.
+	Required bool
+	// Поля identity, из значений которых составляется ключ bucket'а, например login и ip.
+	// Если не заданы, ключом служит значение поля с именем типа лимита.
+	KeyFields []string
+	// Дополнительные уровни лимита, например 50 в сутки к основным 5 в минуту. Запрос должен пройти все уровни.
+	Tiers []Tier
+	// Уровень, к которому относится лимит, развернутый из Tiers; пусто для основного уровня.
+	Tier string
+}
+
+// Tier дополнительный уровень лимита со своими размером bucket'а и скоростью пополнения.
+type Tier struct {
+	Name  string
+	Value int
+	// Если не задана, используется скорость пополнения лимита.
+	RefillRate *refillrate.RefillRate
+}
+
+// TierSeparator разделитель типа лимита и имени уровня в ключе лимитера уровня.
+const TierSeparator = ":"
+
+// BucketKeySeparator разделитель ключа лимитера и ключа bucket'а в ключах bucket'ов составного лимитера.
+// Ключ лимитера отделяется по первому вхождению, поэтому тип лимита и имя уровня не могут его содержать.
+const BucketKeySeparator = "_"
+
+// Expand возвращает лимиты всех уровней по ключам лимитеров: основной уровень - по типу лимита,
+// дополнительные - по типу и имени уровня через TierSeparator.
+func (l Limit) Expand() map[string]Limit {
+	expanded := make(map[string]Limit, len(l.Tiers)+1)
+
+	base := l
+	base.Tiers = nil
+	expanded[l.LimitType.String()] = base
+
+	for _, tier := range l.Tiers {
+		tierLimit := base
+		tierLimit.Value = tier.Value
+		tierLimit.Tier = tier.Name
+		if tier.RefillRate != nil {
+			tierLimit.RefillRate = tier.RefillRate
+		}
+
+		expanded[l.LimitType.String()+TierSeparator+tier.Name] = tierLimit
+	}
+
+	return expanded
+}
+
+// KeySeparator разделитель значений полей в составном ключе bucket'а.
+const KeySeparator = "|"
+
+// Fields возвращает поля identity, по которым считается лимит.
+func (l Limit) Fields() []string {
+	if len(l.KeyFields) == 0 {
+		return []string{l.LimitType.String()}
+	}
+
+	return l.KeyFields
+}
+
+// Key возвращает ключ bucket'а лимита для identity, если в identity есть все поля лимита.
+// Значения полей составного ключа экранируются и объединяются через KeySeparator,
+// поэтому разные наборы значений не дают одинаковых ключей.
+func (l Limit) Key(identity UserIdentityDto) (string, bool) {
+	fields := l.Fields()
+	values := make([]string, 0, len(fields))
+	for _, field := range fields {
+		value, found := identity[field]
+		if !found {
+			return "", false
+		}
+
+		values = append(values, value)
+	}
+
+	if len(values) == 1 {
+		return values[0], true
+	}
+
+	for i, value := range values {
+		values[i] = url.QueryEscape(value)
+	}
+
+	return strings.Join(values, KeySeparator), true
+}
+
+// Unlimited количество оставшихся попыток для запросов, которые не ограничиваются (IP в белом списке).
+const Unlimited = -1
+
+// Reason причина решения по запросу.
+type Reason string
+
+const (
+	// ReasonPassed запрос прошел все лимиты.
+	ReasonPassed Reason = "passed"
+	// ReasonRateLimit запрос отклонен лимитом.
+	ReasonRateLimit Reason = "ratelimit"
+	// ReasonBlackList запрос отклонен: IP в черном списке.
+	ReasonBlackList Reason = "blacklist"
+	// ReasonWhiteList запрос разрешен без проверки лимитов: IP в белом списке.
+	ReasonWhiteList Reason = "whitelist"
+)
+
+func (r Reason) String() string {
+	return string(r)
+}
+
+// Decision результат проверки запроса на rate limit.
+type Decision struct {
+	Allowed bool
+	Reason  Reason
+	// Правило черного или белого списка, определившее решение.
+	RuleID    int
+	RuleIPNet string
+	// Количество оставшихся попыток по самому строгому лимиту или Unlimited.
+	Remaining int
+	// Размер самого строгого лимита.
+	Limit int
+	// Время, через которое запрос может быть разрешен, если он отклонен лимитом.
+	RetryAfter time.Duration
+	// Время, за которое самый строгий лимит полностью восстановится (оценка сверху); 0, если он не израсходован.
+	ResetAfter time.Duration
+	// Тип лимита, отклонившего запрос.
+	LimitType Type
+	// Уровень лимита, отклонивший запрос; пусто для основного уровня.
+	Tier string
+	// Запрос разрешен только потому, что превышенный лимит работает в режиме наблюдения.
+	Shadow bool
 }
 
 // IStorage хранилище лимитов (правил) rate limit'инга запросов.
 type IStorage interface {
 	GetLimits() (*Limits, error)
-	GetLimitsByTypes([]string) (*Limits, error)
 }
 
 // IService основной сервис проверки запроса на rate limit.
@@ -45,8 +189,16 @@ type IService interface {
 	ResetLimit(UserIdentityDto) error
 }
 
+// IDecisionService сервис проверки запроса на rate limit с подробным результатом проверки.
+type IDecisionService interface {
+	IService
+
+	Check(UserIdentityDto) (Decision, error)
+}
+
 // UserIdentityDto тип для идентификации клиента, запрос которого подвергается rate limit'ингу.
-// Может содержать один или несколько пар ключ-значение. Лимитеры сами решают, с какими ключами работать.
+// Может содержать один или несколько пар ключ-значение (измерений: логин, IP, отпечаток устройства и т.д.).
+// Лимитеры сами решают, с какими ключами работать.
 type UserIdentityDto map[string]string
 
 // ITokenBucketLimitService интерфейс лимитеров на основе Bucket.
@@ -58,3 +210,20 @@ type ITokenBucketLimitService interface {
 	GetBuckets() map[string]*bucket.IBucket
 	SweepBucket(string) error
 }
+
+// ISnapshotLimitService лимитер, состояние bucket'ов которого можно сохранять и восстанавливать.
+type ISnapshotLimitService interface {
+	// TakeSnapshot возвращает состояния bucket'ов, изменившихся с прошлого вызова, и ключи удаленных bucket'ов.
+	TakeSnapshot() (map[string]bucket.State, []string)
+	RestoreSnapshot(bucketKey string, state bucket.State)
+}
+
+// IRefundableLimitService лимитер, который может вернуть токены, списанные SatisfyLimit для identity.
+type IRefundableLimitService interface {
+	RefundLimit(UserIdentityDto) error
+}
+
+// IResizableLimitService лимитер, который можно пересоздать с новыми параметрами без потери состояния bucket'ов.
+type IResizableLimitService interface {
+	Resize(bucketSize int, refillRate refillrate.RefillRate) ITokenBucketLimitService
+}
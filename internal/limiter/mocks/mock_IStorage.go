@@ -90,65 +90,3 @@ func (_c *MockIStorage_GetLimits_Call) RunAndReturn(run func() (*limiter.Limits,
 	_c.Call.Return(run)
 	return _c
 }
-
-// GetLimitsByTypes provides a mock function for the type MockIStorage
-func (_mock *MockIStorage) GetLimitsByTypes(strings []string) (*limiter.Limits, error) {
-	ret := _mock.Called(strings)
-
-	if len(ret) == 0 {
-		panic("no return value specified for GetLimitsByTypes")
-	}
-
-	var r0 *limiter.Limits
-	var r1 error
-	if returnFunc, ok := ret.Get(0).(func([]string) (*limiter.Limits, error)); ok {
-		return returnFunc(strings)
-	}
-	if returnFunc, ok := ret.Get(0).(func([]string) *limiter.Limits); ok {
-		r0 = returnFunc(strings)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*limiter.Limits)
-		}
-	}
-	if returnFunc, ok := ret.Get(1).(func([]string) error); ok {
-		r1 = returnFunc(strings)
-	} else {
-		r1 = ret.Error(1)
-	}
-	return r0, r1
-}
-
-// MockIStorage_GetLimitsByTypes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLimitsByTypes'
-type MockIStorage_GetLimitsByTypes_Call struct {
-	*mock.Call
-}
-
-// GetLimitsByTypes is a helper method to define mock.On call
-//   - strings []string
-func (_e *MockIStorage_Expecter) GetLimitsByTypes(strings interface{}) *MockIStorage_GetLimitsByTypes_Call {
-	return &MockIStorage_GetLimitsByTypes_Call{Call: _e.mock.On("GetLimitsByTypes", strings)}
-}
-
-func (_c *MockIStorage_GetLimitsByTypes_Call) Run(run func(strings []string)) *MockIStorage_GetLimitsByTypes_Call {
-	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 []string
-		if args[0] != nil {
-			arg0 = args[0].([]string)
-		}
-		run(
-			arg0,
-		)
-	})
-	return _c
-}
-
-func (_c *MockIStorage_GetLimitsByTypes_Call) Return(limits *limiter.Limits, err error) *MockIStorage_GetLimitsByTypes_Call {
-	_c.Call.Return(limits, err)
-	return _c
-}
-
-func (_c *MockIStorage_GetLimitsByTypes_Call) RunAndReturn(run func(strings []string) (*limiter.Limits, error)) *MockIStorage_GetLimitsByTypes_Call {
-	_c.Call.Return(run)
-	return _c
-}
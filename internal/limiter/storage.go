@@ -2,8 +2,15 @@ package limiter
 
 import (
 	"database/sql"
-
-	"github.com/jmoiron/sqlx"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/metrics"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/storage/postgres"
 )
 
@@ -11,6 +18,20 @@ type sqlEntity struct {
 	LimitType   string         `db:"type"`
 	Value       int            `db:"value"`
 	Description sql.NullString `db:"description"`
+	RefillCount sql.NullInt64  `db:"refill_count"`
+	RefillTime  sql.NullString `db:"refill_time"`
+	Algorithm   sql.NullString `db:"algorithm"`
+	Required    bool           `db:"required"`
+	KeyFields   sql.NullString `db:"key_fields"`
+	Tiers       sql.NullString `db:"tiers"`
+}
+
+// sqlTier элемент JSON-массива tiers таблицы rate_limit.
+type sqlTier struct {
+	Name        string `json:"name"`
+	Value       int    `json:"value"`
+	RefillCount int    `json:"refill_count"`
+	RefillTime  string `json:"refill_time"`
 }
 
 type Storage struct {
@@ -22,6 +43,8 @@ func NewStorage(storage *postgres.Storage) IStorage {
 }
 
 func (s *Storage) GetLimits() (*Limits, error) {
+	defer metrics.ObserveQuery("limit", "GetLimits", time.Now())
+
 	query := `
 		SELECT *
 		FROM rate_limit
@@ -43,62 +66,117 @@ func (s *Storage) GetLimits() (*Limits, error) {
 		return nil, err
 	}
 
+	return s.sqlEntitiesToEntities(rows)
+}
+
+func (s *Storage) sqlEntitiesToEntities(rows []sqlEntity) (*Limits, error) {
 	result := make(Limits, 0, len(rows))
 	for _, r := range rows {
-		result = append(result, *s.sqlEntityToEntity(&r))
+		e, err := s.sqlEntityToEntity(&r)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, *e)
 	}
 
 	return &result, nil
 }
 
-func (s *Storage) GetLimitsByTypes(types []string) (*Limits, error) {
-	arg := map[string]any{
-		"types": types,
+func (s *Storage) sqlEntityToEntity(se *sqlEntity) (*Limit, error) {
+	// Тип лимита входит в ключи лимитеров и bucket'ов и не должен содержать их разделителей.
+	if se.LimitType == "" || strings.ContainsAny(se.LimitType, TierSeparator+BucketKeySeparator) {
+		return nil, fmt.Errorf("%w: %q", ErrIncorrectLimitType, se.LimitType)
 	}
-	query := `SELECT * FROM rate_limit WHERE type IN(:types)`
-	query, args, err := sqlx.Named(query, arg)
-	if err != nil {
-		return nil, err
+
+	e := &Limit{
+		LimitType: Type(se.LimitType),
+		Value:     se.Value,
+		Required:  se.Required,
 	}
-	query, args, err = sqlx.In(query, args...)
-	if err != nil {
-		return nil, err
+
+	if se.Description.Valid {
+		e.Description = se.Description.String
 	}
-	query = sqlx.Rebind(sqlx.DOLLAR, query) // query теперь с $1,$2
-	stmt, err := s.DB.PreparexContext(s.Ctx, query)
-	if err != nil {
-		return nil, err
+
+	if se.RefillCount.Valid && se.RefillTime.Valid {
+		refillTime, err := time.ParseDuration(se.RefillTime.String)
+		if err != nil || se.RefillCount.Int64 <= 0 || refillTime <= 0 {
+			return nil, fmt.Errorf(
+				"%w for %s: %d per %q",
+				ErrIncorrectRefill, se.LimitType, se.RefillCount.Int64, se.RefillTime.String,
+			)
+		}
+
+		refillRate := refillrate.New(int(se.RefillCount.Int64), refillTime)
+		e.RefillRate = &refillRate
 	}
-	defer stmt.Close()
 
-	var rows []sqlEntity
+	if se.Algorithm.Valid {
+		e.Algorithm = bucket.Algorithm(se.Algorithm.String)
+		if !e.Algorithm.Valid() {
+			return nil, fmt.Errorf("%w for %s: %q", ErrIncorrectAlgorithm, se.LimitType, se.Algorithm.String)
+		}
+	}
 
-	err = stmt.SelectContext(
-		s.Ctx,
-		&rows,
-		args...,
-	)
-	if err != nil {
-		return nil, err
+	if se.KeyFields.Valid && se.KeyFields.String != "" {
+		for field := range strings.SplitSeq(se.KeyFields.String, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" || slices.Contains(e.KeyFields, field) {
+				return nil, fmt.Errorf("%w for %s: %q", ErrIncorrectKeyFields, se.LimitType, se.KeyFields.String)
+			}
+
+			e.KeyFields = append(e.KeyFields, field)
+		}
 	}
 
-	result := make(Limits, 0, len(rows))
-	for _, r := range rows {
-		result = append(result, *s.sqlEntityToEntity(&r))
+	if se.Tiers.Valid {
+		tiers, err := sqlTiersToTiers(se.Tiers.String)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s: %w", ErrIncorrectTiers, se.LimitType, err)
+		}
+
+		e.Tiers = tiers
 	}
 
-	return &result, nil
+	return e, nil
 }
 
-func (s *Storage) sqlEntityToEntity(se *sqlEntity) *Limit {
-	e := &Limit{
-		LimitType: Type(se.LimitType),
-		Value:     se.Value,
+// sqlTiersToTiers разбирает уровни лимита. Имя уровня входит в ключ лимитера, поэтому должно быть
+// уникальным и не содержать разделителей ключей; скорость пополнения задается целиком или не задается.
+func sqlTiersToTiers(raw string) ([]Tier, error) {
+	var rows []sqlTier
+	if err := json.Unmarshal([]byte(raw), &rows); err != nil {
+		return nil, err
 	}
 
-	if se.Description.Valid {
-		e.Description = se.Description.String
+	tiers := make([]Tier, 0, len(rows))
+	names := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if row.Name == "" || strings.ContainsAny(row.Name, TierSeparator+BucketKeySeparator) || names[row.Name] {
+			return nil, fmt.Errorf("invalid tier name %q", row.Name)
+		}
+		names[row.Name] = true
+
+		if row.Value <= 0 {
+			return nil, fmt.Errorf("invalid value %d of tier %q", row.Value, row.Name)
+		}
+
+		tier := Tier{Name: row.Name, Value: row.Value}
+		if row.RefillCount != 0 || row.RefillTime != "" {
+			refillTime, err := time.ParseDuration(row.RefillTime)
+			if err != nil || row.RefillCount <= 0 || refillTime <= 0 {
+				return nil, fmt.Errorf(
+					"invalid refill rate of tier %q: %d per %q", row.Name, row.RefillCount, row.RefillTime,
+				)
+			}
+
+			refillRate := refillrate.New(row.RefillCount, refillTime)
+			tier.RefillRate = &refillRate
+		}
+
+		tiers = append(tiers, tier)
 	}
 
-	return e
+	return tiers, nil
 }
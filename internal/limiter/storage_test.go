@@ -3,11 +3,13 @@ package limiter_test
 import (
 	"context"
 	"database/sql"
-	"database/sql/driver"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/storage/postgres"
 	"github.com/stretchr/testify/require"
@@ -33,9 +35,9 @@ func newTestStorage(t *testing.T) (*limiter.Storage, sqlmock.Sqlmock) {
 func TestStorage_GetLimits(t *testing.T) {
 	storage, mock := newTestStorage(t)
 
-	rows := sqlmock.NewRows([]string{"type", "value", "description"}).
-		AddRow("login", 100, "login limit").
-		AddRow("api", 200, nil)
+	rows := sqlmock.NewRows([]string{"type", "value", "description", "required"}).
+		AddRow("login", 100, "login limit", true).
+		AddRow("api", 200, nil, false)
 
 	mock.ExpectPrepare("SELECT \\* FROM rate_limit").
 		ExpectQuery().
@@ -48,10 +50,12 @@ func TestStorage_GetLimits(t *testing.T) {
 	require.Equal(t, limiter.Type("login"), (*result)[0].LimitType)
 	require.Equal(t, 100, (*result)[0].Value)
 	require.Equal(t, "login limit", (*result)[0].Description)
+	require.True(t, (*result)[0].Required)
 
 	require.Equal(t, limiter.Type("api"), (*result)[1].LimitType)
 	require.Equal(t, 200, (*result)[1].Value)
 	require.Empty(t, (*result)[1].Description)
+	require.False(t, (*result)[1].Required)
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
@@ -79,38 +83,214 @@ func TestStorage_GetLimits_SelectError(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestStorage_GetLimitsByTypes(t *testing.T) {
+func TestStorage_GetLimits_IncorrectLimitType(t *testing.T) {
+	for _, limitType := range []string{"", "user_agent", "login:strict"} {
+		t.Run(limitType, func(t *testing.T) {
+			storage, mock := newTestStorage(t)
+
+			rows := sqlmock.NewRows([]string{"type", "value", "description"}).
+				AddRow(limitType, 10, nil)
+
+			mock.ExpectPrepare("SELECT \\* FROM rate_limit").
+				ExpectQuery().
+				WillReturnRows(rows)
+
+			_, err := storage.GetLimits()
+			require.ErrorIs(t, err, limiter.ErrIncorrectLimitType)
+		})
+	}
+}
+
+func TestStorage_GetLimits_RefillRate(t *testing.T) {
 	storage, mock := newTestStorage(t)
 
-	rows := sqlmock.NewRows([]string{"type", "value", "description"}).
-		AddRow("login", 100, "login limit").
-		AddRow("api", 200, nil)
+	rows := sqlmock.NewRows([]string{"type", "value", "description", "refill_count", "refill_time"}).
+		AddRow("login", 10, nil, 10, "1m").
+		AddRow("ip", 1000, nil, nil, nil)
 
-	types := []string{"login", "api"}
-	query := "SELECT \\* FROM rate_limit WHERE type IN\\(\\$1, \\$2\\)"
+	mock.ExpectPrepare("SELECT \\* FROM rate_limit").
+		ExpectQuery().
+		WillReturnRows(rows)
 
-	driverArgs := make([]driver.Value, len(types))
-	for i, v := range types {
-		driverArgs[i] = v
+	result, err := storage.GetLimits()
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+
+	require.NotNil(t, (*result)[0].RefillRate)
+	require.Equal(t, 10, (*result)[0].RefillRate.GetCount())
+	require.Equal(t, time.Minute, (*result)[0].RefillRate.GetTime())
+
+	require.Nil(t, (*result)[1].RefillRate)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_GetLimits_IncorrectRefillRate(t *testing.T) {
+	for _, refillTime := range []string{"minute", "0s", "-1m"} {
+		t.Run(refillTime, func(t *testing.T) {
+			storage, mock := newTestStorage(t)
+
+			rows := sqlmock.NewRows([]string{"type", "value", "description", "refill_count", "refill_time"}).
+				AddRow("login", 10, nil, 10, refillTime)
+
+			mock.ExpectPrepare("SELECT \\* FROM rate_limit").
+				ExpectQuery().
+				WillReturnRows(rows)
+
+			_, err := storage.GetLimits()
+			require.ErrorIs(t, err, limiter.ErrIncorrectRefill)
+		})
 	}
+}
+
+func TestStorage_GetLimits_Algorithm(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	rows := sqlmock.NewRows([]string{"type", "value", "description", "algorithm"}).
+		AddRow("login", 10, nil, "sliding_log").
+		AddRow("ip", 1000, nil, "token_bucket")
 
-	// Подготавливаем mock под итоговый SQL после Rebind
-	mock.ExpectPrepare(query).
+	mock.ExpectPrepare("SELECT \\* FROM rate_limit").
 		ExpectQuery().
-		WithArgs(driverArgs...).
 		WillReturnRows(rows)
 
-	result, err := storage.GetLimitsByTypes(types)
+	result, err := storage.GetLimits()
 	require.NoError(t, err)
 	require.Len(t, *result, 2)
+	require.Equal(t, bucket.SlidingLog, (*result)[0].Algorithm)
+	require.Equal(t, bucket.TokenBucket, (*result)[1].Algorithm)
 
-	require.Equal(t, limiter.Type("login"), (*result)[0].LimitType)
-	require.Equal(t, 100, (*result)[0].Value)
-	require.Equal(t, "login limit", (*result)[0].Description)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
 
-	require.Equal(t, limiter.Type("api"), (*result)[1].LimitType)
-	require.Equal(t, 200, (*result)[1].Value)
-	require.Empty(t, (*result)[1].Description)
+func TestStorage_GetLimits_IncorrectAlgorithm(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	rows := sqlmock.NewRows([]string{"type", "value", "description", "algorithm"}).
+		AddRow("login", 10, nil, "leaky_bucket")
+
+	mock.ExpectPrepare("SELECT \\* FROM rate_limit").
+		ExpectQuery().
+		WillReturnRows(rows)
+
+	_, err := storage.GetLimits()
+	require.ErrorIs(t, err, limiter.ErrIncorrectAlgorithm)
+}
+
+func TestStorage_GetLimits_KeyFields(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	rows := sqlmock.NewRows([]string{"type", "value", "description", "key_fields"}).
+		AddRow("login+ip", 5, nil, "login, ip").
+		AddRow("ip", 1000, nil, nil)
+
+	mock.ExpectPrepare("SELECT \\* FROM rate_limit").
+		ExpectQuery().
+		WillReturnRows(rows)
+
+	result, err := storage.GetLimits()
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+	require.Equal(t, []string{"login", "ip"}, (*result)[0].KeyFields)
+	require.Equal(t, []string{"login", "ip"}, (*result)[0].Fields())
+	require.Nil(t, (*result)[1].KeyFields)
+	require.Equal(t, []string{"ip"}, (*result)[1].Fields())
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestStorage_GetLimits_IncorrectKeyFields(t *testing.T) {
+	for _, keyFields := range []string{"login,,ip", "login,login"} {
+		t.Run(keyFields, func(t *testing.T) {
+			storage, mock := newTestStorage(t)
+
+			rows := sqlmock.NewRows([]string{"type", "value", "description", "key_fields"}).
+				AddRow("login+ip", 5, nil, keyFields)
+
+			mock.ExpectPrepare("SELECT \\* FROM rate_limit").
+				ExpectQuery().
+				WillReturnRows(rows)
+
+			_, err := storage.GetLimits()
+			require.ErrorIs(t, err, limiter.ErrIncorrectKeyFields)
+		})
+	}
+}
+
+func TestLimit_Key(t *testing.T) {
+	pair := limiter.Limit{LimitType: "login+ip", KeyFields: []string{"login", "ip"}}
+
+	key, found := pair.Key(limiter.UserIdentityDto{"login": "lucky", "ip": "1.1.1.1", "password": "root"})
+	require.True(t, found)
+	require.Equal(t, "lucky|1.1.1.1", key)
+
+	_, found = pair.Key(limiter.UserIdentityDto{"login": "lucky"})
+	require.False(t, found)
+
+	// разделитель в значениях не приводит к совпадению ключей
+	first, _ := pair.Key(limiter.UserIdentityDto{"login": "a|b", "ip": "c"})
+	second, _ := pair.Key(limiter.UserIdentityDto{"login": "a", "ip": "b|c"})
+	require.NotEqual(t, first, second)
+
+	key, found = limiter.Limit{LimitType: limiter.IPLimit}.Key(limiter.UserIdentityDto{"ip": "1.1.1.1"})
+	require.True(t, found)
+	require.Equal(t, "1.1.1.1", key)
+}
+
+func TestStorage_GetLimits_Tiers(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	rows := sqlmock.NewRows([]string{"type", "value", "description", "refill_count", "refill_time", "tiers"}).
+		AddRow("login", 5, nil, 5, "1m", `[{"name": "day", "value": 50, "refill_count": 50, "refill_time": "24h"}]`).
+		AddRow("ip", 1000, nil, nil, nil, nil)
+
+	mock.ExpectPrepare("SELECT \\* FROM rate_limit").
+		ExpectQuery().
+		WillReturnRows(rows)
+
+	result, err := storage.GetLimits()
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+
+	day := refillrate.New(50, 24*time.Hour)
+	require.Equal(t, []limiter.Tier{{Name: "day", Value: 50, RefillRate: &day}}, (*result)[0].Tiers)
+	require.Nil(t, (*result)[1].Tiers)
+
+	expanded := (*result)[0].Expand()
+	require.Len(t, expanded, 2)
+	require.Equal(t, 5, expanded["login"].Value)
+	require.Empty(t, expanded["login"].Tier)
+	require.Equal(t, 50, expanded["login:day"].Value)
+	require.Equal(t, "day", expanded["login:day"].Tier)
+	require.Equal(t, limiter.LoginLimit, expanded["login:day"].LimitType)
+	require.Equal(t, &day, expanded["login:day"].RefillRate)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_GetLimits_IncorrectTiers(t *testing.T) {
+	tests := []string{
+		`{"name": "day"}`,
+		`[{"name": "", "value": 50}]`,
+		`[{"name": "per_day", "value": 50}]`,
+		`[{"name": "day", "value": 50}, {"name": "day", "value": 60}]`,
+		`[{"name": "day", "value": 0}]`,
+		`[{"name": "day", "value": 50, "refill_count": 50}]`,
+	}
+
+	for _, tiers := range tests {
+		t.Run(tiers, func(t *testing.T) {
+			storage, mock := newTestStorage(t)
+
+			rows := sqlmock.NewRows([]string{"type", "value", "description", "tiers"}).
+				AddRow("login", 5, nil, tiers)
+
+			mock.ExpectPrepare("SELECT \\* FROM rate_limit").
+				ExpectQuery().
+				WillReturnRows(rows)
+
+			_, err := storage.GetLimits()
+			require.ErrorIs(t, err, limiter.ErrIncorrectTiers)
+		})
+	}
+}
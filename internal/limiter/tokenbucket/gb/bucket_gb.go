@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/metrics"
 )
 
 type TokenBucketGB struct {
@@ -20,6 +21,11 @@ func New(tokenBucketLimiter limiter.ITokenBucketLimitService, tokenBucketTTL tim
 }
 
 func (gb *TokenBucketGB) Sweep() error {
+	start := time.Now()
+	defer func() {
+		metrics.GCSweepDuration.Observe(time.Since(start).Seconds())
+	}()
+
 	buckets := gb.tokenBucketLimiter.GetBuckets()
 	if len(buckets) == 0 {
 		return nil
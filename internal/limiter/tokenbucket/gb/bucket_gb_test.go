@@ -9,7 +9,6 @@ import (
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/composite"
 	limitermocks "github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/mocks"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/tokenbucket/gb"
-	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -58,7 +57,7 @@ func getMockLimitStorage(t *testing.T, types []limiter.Type, values []int) *limi
 	}
 
 	limitStorage := limitermocks.NewMockIStorage(t)
-	limitStorage.EXPECT().GetLimitsByTypes(mock.AnythingOfType("[]string")).Return(&mockLimits, nil)
+	limitStorage.EXPECT().GetLimits().Return(&mockLimits, nil)
 
 	return limitStorage
 }
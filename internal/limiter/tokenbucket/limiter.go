@@ -1,11 +1,9 @@
 package tokenbucket
 
 import (
-	"sync"
-
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
-	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/token"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/store/memory"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
 )
 
@@ -18,9 +16,8 @@ const DefaultRequestCost = 1
 //
 // Позволяет проверять возможность выполнения очередного запроса и получать количество доступных.
 type Limiter struct {
-	sync.Mutex
-
-	buckets    map[string]*bucket.IBucket
+	// Хранилище состояний bucket'ов. По умолчанию - в памяти процесса.
+	store      bucket.IBucketStore
 	bucketSize int
 
 	// Скорость пополнения токенов корзины.
@@ -35,8 +32,18 @@ type Limiter struct {
 }
 
 func New(bucketKey string, bucketSize int, refillRate refillrate.RefillRate) limiter.ITokenBucketLimitService {
+	return NewWithStore(bucketKey, bucketSize, refillRate, memory.New())
+}
+
+// NewWithStore создает лимитер, который хранит bucket'ы в переданном хранилище.
+func NewWithStore(
+	bucketKey string,
+	bucketSize int,
+	refillRate refillrate.RefillRate,
+	store bucket.IBucketStore,
+) limiter.ITokenBucketLimitService {
 	return &Limiter{
-		buckets:     make(map[string]*bucket.IBucket),
+		store:       store,
 		requestCost: DefaultRequestCost,
 
 		bucketKey:        bucketKey,
@@ -54,19 +61,7 @@ func (l *Limiter) SatisfyLimit(identity limiter.UserIdentityDto) (bool, error) {
 		return false, limiter.ErrIncorrectIdentity
 	}
 
-	l.Lock()
-	defer l.Unlock()
-	b := l.initBucket(identityValue)
-
-	(*b).Refill()
-
-	if (*b).GetTokenCount() > 0 && l.requestCost <= (*b).GetTokenCount() {
-		(*b).GetToken(l.requestCost)
-
-		return true, nil
-	}
-
-	return false, nil
+	return l.store.Take(identityValue, l.bucketSize, l.bucketRefillRate, l.requestCost)
 }
 
 func (l *Limiter) ResetLimit(identity limiter.UserIdentityDto) error {
@@ -75,26 +70,26 @@ func (l *Limiter) ResetLimit(identity limiter.UserIdentityDto) error {
 		return limiter.ErrIncorrectIdentity
 	}
 
-	l.Lock()
-	defer l.Unlock()
-	b := l.findBucket(identityValue)
-	if b == nil {
-		return nil
+	return l.store.Reset(identityValue)
+}
+
+// RefundLimit возвращает в bucket identity токены одного запроса, если хранилище поддерживает возврат.
+func (l *Limiter) RefundLimit(identity limiter.UserIdentityDto) error {
+	identityValue, found := identity[l.bucketKey]
+	if !found {
+		return limiter.ErrIncorrectIdentity
 	}
 
-	(*b).Reset()
+	store, ok := l.store.(bucket.IRefundableBucketStore)
+	if !ok {
+		return limiter.ErrNotSupported
+	}
 
-	return nil
+	return store.Refund(identityValue, l.bucketSize, l.bucketRefillRate, l.requestCost)
 }
 
 func (l *Limiter) SweepBucket(bucketKey string) error {
-	l.Lock()
-
-	delete(l.GetBuckets(), bucketKey)
-
-	l.Unlock()
-
-	return nil
+	return l.store.Delete(bucketKey)
 }
 
 func (l *Limiter) SetRequestCost(requestCost int) {
@@ -108,41 +103,55 @@ func (l *Limiter) GetRequestsAllowed(identity limiter.UserIdentityDto) (int, err
 		return 0, limiter.ErrIncorrectIdentity
 	}
 
-	l.Lock()
-	defer l.Unlock()
+	tokens, err := l.store.Tokens(identityValue, l.bucketSize, l.bucketRefillRate)
+	if err != nil {
+		return 0, err
+	}
 
-	b := l.initBucket(identityValue)
+	return tokens / l.requestCost, nil
+}
 
-	(*b).Refill()
+// Resize создает лимитер с новыми размером и скоростью пополнения, использующий то же хранилище bucket'ов.
+// Текущий лимитер не изменяется, поэтому замена безопасна для запросов, которые он обрабатывает.
+func (l *Limiter) Resize(bucketSize int, refillRate refillrate.RefillRate) limiter.ITokenBucketLimitService {
+	if bucketSize == l.bucketSize && refillRate == l.bucketRefillRate {
+		return l
+	}
 
-	return (*b).GetTokenCount() / l.requestCost, nil
-}
+	if store, ok := l.store.(bucket.IResizableBucketStore); ok {
+		store.Resize(bucketSize, refillRate)
+	}
 
-func (l *Limiter) GetBuckets() map[string]*bucket.IBucket {
-	return l.buckets
-}
+	return &Limiter{
+		store:       l.store,
+		requestCost: l.requestCost,
 
-func (l *Limiter) initBucket(identityValue string) *bucket.IBucket {
-	b := l.findBucket(identityValue)
-	if b == nil {
-		b = l.createBucket(identityValue)
+		bucketKey:        l.bucketKey,
+		bucketSize:       bucketSize,
+		bucketRefillRate: refillRate,
 	}
+}
 
-	return b
+func (l *Limiter) GetBuckets() map[string]*bucket.IBucket {
+	return l.store.Buckets()
 }
 
-func (l *Limiter) findBucket(identityValue string) *bucket.IBucket {
-	b, found := l.buckets[identityValue]
-	if !found {
-		return nil
+// TakeSnapshot возвращает изменения bucket'ов, если хранилище поддерживает их выгрузку.
+func (l *Limiter) TakeSnapshot() (map[string]bucket.State, []string) {
+	store, ok := l.store.(bucket.ISnapshotBucketStore)
+	if !ok {
+		return nil, nil
 	}
 
-	return b
+	return store.Dirty()
 }
 
-func (l *Limiter) createBucket(identityValue string) *bucket.IBucket {
-	newBucket := token.New(l.bucketSize, l.bucketRefillRate)
-	l.buckets[identityValue] = &newBucket
+// RestoreSnapshot восстанавливает bucket, если хранилище поддерживает восстановление.
+func (l *Limiter) RestoreSnapshot(bucketKey string, state bucket.State) {
+	store, ok := l.store.(bucket.ISnapshotBucketStore)
+	if !ok {
+		return
+	}
 
-	return l.buckets[identityValue]
+	store.Restore(bucketKey, l.bucketSize, l.bucketRefillRate, state)
 }
@@ -363,3 +363,25 @@ func TestTokenBucketLimiter_ResetLimit_Errors(t *testing.T) {
 		require.ErrorIs(t, resetErr, limiter.ErrIncorrectIdentity)
 	})
 }
+
+func TestTokenBucketLimiter_RefundLimit(t *testing.T) {
+	bucketKey := "ip"
+	identity := limiter.UserIdentityDto{bucketKey: "192.168.1.1"}
+
+	tokenBucketLimiter := tokenbucket.New(bucketKey, 3, refillrate.New(1, time.Hour))
+	refundable, ok := tokenBucketLimiter.(limiter.IRefundableLimitService)
+	require.True(t, ok)
+
+	tokenBucketLimiter.SetRequestCost(2)
+	satisfies, err := tokenBucketLimiter.SatisfyLimit(identity)
+	require.NoError(t, err)
+	require.True(t, satisfies)
+
+	require.NoError(t, refundable.RefundLimit(identity))
+	tokenBucketLimiter.SetRequestCost(1)
+	allowed, err := tokenBucketLimiter.GetRequestsAllowed(identity)
+	require.NoError(t, err)
+	require.Equal(t, 3, allowed)
+
+	require.ErrorIs(t, refundable.RefundLimit(limiter.UserIdentityDto{}), limiter.ErrIncorrectIdentity)
+}
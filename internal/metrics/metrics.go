@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket"
+)
+
+const namespace = "auth_limiter"
+
+var (
+	// Decisions количество решений по запросам LimitCheck.
+	Decisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "decisions_total",
+		Help:      "Rate limit decisions by result, reason and limit type.",
+	}, []string{"allowed", "reason", "limit_type", "shadow"})
+
+	// AutoBans количество автоматических блокировок IP.
+	AutoBans = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auto_bans_total",
+		Help:      "IPs added to the blacklist for repeated rate limit denials.",
+	})
+
+	// GCSweepDuration длительность подчистки устаревших bucket'ов.
+	GCSweepDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "gc_sweep_duration_seconds",
+		Help:      "Duration of stale buckets garbage collection.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// QueryDuration длительность запросов к PostgreSQL.
+	QueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "postgres_query_duration_seconds",
+		Help:      "Duration of PostgreSQL queries by storage and operation.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"storage", "operation"})
+
+	// GRPCDuration длительность обработки gRPC запросов.
+	GRPCDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_request_duration_seconds",
+		Help:      "Duration of gRPC requests by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+)
+
+// ObserveDecision учитывает решение по запросу.
+func ObserveDecision(allowed bool, reason, limitType string, shadow bool) {
+	Decisions.WithLabelValues(
+		strconv.FormatBool(allowed),
+		reason,
+		limitType,
+		strconv.FormatBool(shadow),
+	).Inc()
+}
+
+// ObserveQuery учитывает длительность запроса к PostgreSQL, начатого в start.
+// Предназначена для вызова через defer в начале метода хранилища.
+func ObserveQuery(storage, operation string, start time.Time) {
+	QueryDuration.WithLabelValues(storage, operation).Observe(time.Since(start).Seconds())
+}
+
+// IBucketSource лимитер, bucket'ы которого учитываются в метриках.
+type IBucketSource interface {
+	GetBuckets() map[string]*bucket.IBucket
+}
+
+// BucketCollector отдает количество bucket'ов каждого лимитера на момент сбора метрик.
+// Ключи bucket'ов составного лимитера имеют вид "<тип лимита>_<ключ>".
+type BucketCollector struct {
+	bucketLimiter IBucketSource
+	desc          *prometheus.Desc
+}
+
+func NewBucketCollector(bucketLimiter IBucketSource) *BucketCollector {
+	return &BucketCollector{
+		bucketLimiter: bucketLimiter,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "buckets"),
+			"Live buckets by limit type.",
+			[]string{"limit_type"},
+			nil,
+		),
+	}
+}
+
+func (c *BucketCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *BucketCollector) Collect(ch chan<- prometheus.Metric) {
+	counts := make(map[string]int)
+	for key := range c.bucketLimiter.GetBuckets() {
+		// ключ лимитера отделяется limiter.BucketKeySeparator, который запрещен в типах лимитов
+		limitType, _, _ := strings.Cut(key, "_")
+		counts[limitType]++
+	}
+
+	for limitType, count := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), limitType)
+	}
+}
@@ -0,0 +1,51 @@
+package metrics_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/bucket/refillrate"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/composite"
+	limitermocks "github.com/rainb0w-clwn/go_auth_limiter/internal/limiter/mocks"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/metrics"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveDecision(t *testing.T) {
+	denied := metrics.Decisions.WithLabelValues("false", "ratelimit", "login", "false")
+	before := testutil.ToFloat64(denied)
+
+	metrics.ObserveDecision(false, "ratelimit", "login", false)
+	metrics.ObserveDecision(true, "passed", "", false)
+
+	require.InDelta(t, before+1, testutil.ToFloat64(denied), 0)
+}
+
+func TestBucketCollector(t *testing.T) {
+	limitStorage := limitermocks.NewMockIStorage(t)
+	limitStorage.EXPECT().GetLimits().Return(&limiter.Limits{
+		limiter.Limit{LimitType: limiter.LoginLimit, Value: 3},
+		limiter.Limit{LimitType: limiter.IPLimit, Value: 3},
+	}, nil)
+	compositeLimiter := composite.New(limitStorage, refillrate.New(1, time.Hour))
+
+	for _, login := range []string{"lucky", "root"} {
+		_, err := compositeLimiter.SatisfyLimit(limiter.UserIdentityDto{
+			limiter.LoginLimit.String(): login,
+			limiter.IPLimit.String():    "192.168.1.1",
+		})
+		require.NoError(t, err)
+	}
+
+	expected := `
+# HELP auth_limiter_buckets Live buckets by limit type.
+# TYPE auth_limiter_buckets gauge
+auth_limiter_buckets{limit_type="ip"} 1
+auth_limiter_buckets{limit_type="login"} 2
+`
+	err := testutil.CollectAndCompare(metrics.NewBucketCollector(compositeLimiter), strings.NewReader(expected))
+	require.NoError(t, err)
+}
@@ -0,0 +1,103 @@
+package rule
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+var ErrRuleConflict = errors.New("rule conflicts with existing rules")
+
+// ConflictKind вид конфликта добавляемого правила с существующим.
+type ConflictKind string
+
+const (
+	// ConflictDuplicate в списке уже есть правило с той же подсетью.
+	ConflictDuplicate ConflictKind = "duplicate"
+	// ConflictOverlap подсеть пересекается с правилом противоположного списка.
+	ConflictOverlap ConflictKind = "overlap"
+	// ConflictSubsumed подсеть входит в более широкое правило того же списка.
+	ConflictSubsumed ConflictKind = "subsumed"
+)
+
+type Conflict struct {
+	Kind ConflictKind
+	Rule Rule
+}
+
+// ConflictError ошибка добавления правила, конфликтующего с действующими правилами.
+// Правило можно добавить несмотря на конфликты с AddOptions.Force.
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	conflicts := make([]string, 0, len(e.Conflicts))
+	for _, conflict := range e.Conflicts {
+		conflicts = append(conflicts, fmt.Sprintf(
+			"%s with %s list rule %s", conflict.Kind, conflict.Rule.RuleType, conflict.Rule.IP,
+		))
+	}
+
+	return fmt.Sprintf("%s: %s", ErrRuleConflict, strings.Join(conflicts, ", "))
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrRuleConflict
+}
+
+// Duplicate проверяет, есть ли среди конфликтов правило с той же подсетью в том же списке.
+func (e *ConflictError) Duplicate() bool {
+	for _, conflict := range e.Conflicts {
+		if conflict.Kind == ConflictDuplicate {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findConflicts возвращает конфликты подсети ipNet списка listType с действующими правилами обоих списков.
+// Пересекающиеся правила ищутся в индексах списков, а не полным перебором правил из хранилища.
+func (s Service) findConflicts(ipNet string, listType Type) ([]Conflict, error) {
+	prefix, ok := parsePrefix(ipNet)
+	if !ok {
+		return nil, ErrInvalidInputIP
+	}
+	prefix = prefix.Masked()
+
+	var conflicts []Conflict
+	for _, ruleType := range []Type{WhiteList, BlackList} {
+		index, err := s.index(ruleType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range index.Overlapping(prefix) {
+			existing, _ := parsePrefix(r.IP)
+
+			if kind, conflict := conflictKind(prefix, existing.Masked(), ruleType == listType); conflict {
+				conflicts = append(conflicts, Conflict{Kind: kind, Rule: *r})
+			}
+		}
+	}
+
+	return conflicts, nil
+}
+
+func conflictKind(added, existing netip.Prefix, sameList bool) (ConflictKind, bool) {
+	switch {
+	case !added.Overlaps(existing):
+		return "", false
+	case !sameList:
+		return ConflictOverlap, true
+	case added == existing:
+		return ConflictDuplicate, true
+	case existing.Bits() < added.Bits():
+		return ConflictSubsumed, true
+	default:
+		// более широкое правило того же списка не конфликтует с уже существующими узкими
+		return "", false
+	}
+}
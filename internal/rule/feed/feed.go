@@ -0,0 +1,83 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
+)
+
+var (
+	ErrNoSource       = errors.New("threat feed has neither url nor path")
+	ErrUnexpectedHTTP = errors.New("unexpected threat feed response status")
+)
+
+// Options источник блоклиста: URL или путь к локальному файлу в формате "CIDR ; комментарий".
+type Options struct {
+	Name string
+	URL  string
+	Path string
+}
+
+// Feed синхронизирует правила черного списка, принадлежащие блоклисту, с его текущим содержимым.
+type Feed struct {
+	options     Options
+	ruleService rule.IService
+	client      *http.Client
+}
+
+func New(options Options, ruleService rule.IService, client *http.Client) *Feed {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &Feed{
+		options:     options,
+		ruleService: ruleService,
+		client:      client,
+	}
+}
+
+func (f *Feed) Name() string {
+	return f.options.Name
+}
+
+// Sync загружает блоклист и применяет его к черному списку.
+func (f *Feed) Sync(ctx context.Context) (*rule.ImportSummary, error) {
+	body, err := f.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return f.ruleService.SyncFeed(f.options.Name, body)
+}
+
+func (f *Feed) fetch(ctx context.Context) (io.ReadCloser, error) {
+	switch {
+	case f.options.URL != "":
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, f.options.URL, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := f.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w: %s", ErrUnexpectedHTTP, resp.Status)
+		}
+
+		return resp.Body, nil
+	case f.options.Path != "":
+		return os.Open(f.options.Path)
+	default:
+		return nil, ErrNoSource
+	}
+}
@@ -0,0 +1,88 @@
+package feed_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule/feed"
+	rulemocks "github.com/rainb0w-clwn/go_auth_limiter/internal/rule/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const dropList = "; Spamhaus DROP List\n1.10.16.0/20 ; SBL256894\n2.56.192.0/22 ; SBL459831\n"
+
+func expectSync(storage *rulemocks.MockIStorage) {
+	storage.EXPECT().GetForFeed("drop").Return(&rule.Rules{{ID: 1, IP: "1.10.16.0/20", Feed: "drop"}}, nil).Once()
+	storage.EXPECT().
+		ApplyFeed("drop", mock.MatchedBy(func(add rule.Rules) bool {
+			return len(add) == 1 && add[0].IP == "2.56.192.0/22" && add[0].Feed == "drop"
+		}), []int(nil)).
+		Return(1, 0, nil).
+		Once()
+}
+
+func TestFeed_Sync_URL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(dropList))
+	}))
+	defer server.Close()
+
+	storage := rulemocks.NewMockIStorage(t)
+	expectSync(storage)
+
+	threatFeed := feed.New(feed.Options{Name: "drop", URL: server.URL}, rule.NewService(storage), server.Client())
+	summary, err := threatFeed.Sync(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.Added)
+	require.Equal(t, 1, summary.Skipped)
+}
+
+func TestFeed_Sync_Path(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drop.txt")
+	require.NoError(t, os.WriteFile(path, []byte(dropList), 0o600))
+
+	storage := rulemocks.NewMockIStorage(t)
+	expectSync(storage)
+
+	threatFeed := feed.New(feed.Options{Name: "drop", Path: path}, rule.NewService(storage), nil)
+	summary, err := threatFeed.Sync(context.Background())
+
+	require.NoError(t, err)
+	require.Equal(t, 1, summary.Added)
+}
+
+func TestFeed_Sync_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		options feed.Options
+		err     error
+	}{
+		{name: "http status", options: feed.Options{Name: "drop", URL: server.URL}, err: feed.ErrUnexpectedHTTP},
+		{name: "missing file", options: feed.Options{Name: "drop", Path: "/nonexistent/drop.txt"}, err: os.ErrNotExist},
+		{name: "no source", options: feed.Options{Name: "drop"}, err: feed.ErrNoSource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// хранилище не вызывается: блоклист не загружен
+			storage := rulemocks.NewMockIStorage(t)
+
+			threatFeed := feed.New(tt.options, rule.NewService(storage), server.Client())
+			_, err := threatFeed.Sync(context.Background())
+
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package rule
+
+import (
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// Index префиксное дерево правил одного списка для поиска наиболее специфичной подсети (longest prefix match).
+// Адреса IPv4 и IPv6 хранятся в отдельных деревьях. Дерево не изменяется после построения:
+// истекшие правила остаются в нем до перестроения, но не совпадают.
+type Index struct {
+	v4 *indexNode
+	v6 *indexNode
+}
+
+type indexNode struct {
+	children [2]*indexNode
+	// Правила подсети узла в порядке добавления.
+	rules []*Rule
+}
+
+// NewIndex строит индекс правил. Правила с некорректным IP пропускаются.
+// Для одинаковых подсетей используется первое действующее правило.
+func NewIndex(rules Rules) *Index {
+	index := &Index{v4: &indexNode{}, v6: &indexNode{}}
+
+	for i := range rules {
+		prefix, ok := parsePrefix(rules[i].IP)
+		if !ok {
+			continue
+		}
+		prefix = prefix.Masked()
+
+		node := index.root(prefix.Addr())
+		addr := prefix.Addr().AsSlice()
+		for bit := 0; bit < prefix.Bits(); bit++ {
+			b := addrBit(addr, bit)
+			if node.children[b] == nil {
+				node.children[b] = &indexNode{}
+			}
+			node = node.children[b]
+		}
+
+		node.rules = append(node.rules, &rules[i])
+	}
+
+	return index
+}
+
+// Match возвращает действующее правило с самой длинной подсетью, содержащей ip, или nil.
+func (i *Index) Match(ip netip.Addr) *Rule {
+	ip = ip.Unmap().WithZone("")
+	now := time.Now()
+	node := i.root(ip)
+	addr := ip.AsSlice()
+
+	matched := node.active(now)
+	for bit := 0; bit < ip.BitLen(); bit++ {
+		node = node.children[addrBit(addr, bit)]
+		if node == nil {
+			break
+		}
+
+		if rule := node.active(now); rule != nil {
+			matched = rule
+		}
+	}
+
+	return matched
+}
+
+// Overlapping возвращает действующие правила, подсети которых пересекаются с prefix:
+// сначала содержащие prefix от более широких к более узким, затем вложенные в него.
+func (i *Index) Overlapping(prefix netip.Prefix) []*Rule {
+	prefix = prefix.Masked()
+	now := time.Now()
+	node := i.root(prefix.Addr())
+	addr := prefix.Addr().AsSlice()
+
+	var overlapping []*Rule
+	for bit := 0; ; bit++ {
+		overlapping = node.appendActive(overlapping, now)
+		if bit == prefix.Bits() {
+			break
+		}
+
+		node = node.children[addrBit(addr, bit)]
+		if node == nil {
+			return overlapping
+		}
+	}
+
+	for _, child := range node.children {
+		overlapping = child.appendSubtree(overlapping, now)
+	}
+
+	return overlapping
+}
+
+func (n *indexNode) active(now time.Time) *Rule {
+	for _, rule := range n.rules {
+		if !rule.Expired(now) {
+			return rule
+		}
+	}
+
+	return nil
+}
+
+func (n *indexNode) appendActive(rules []*Rule, now time.Time) []*Rule {
+	for _, rule := range n.rules {
+		if !rule.Expired(now) {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules
+}
+
+func (n *indexNode) appendSubtree(rules []*Rule, now time.Time) []*Rule {
+	if n == nil {
+		return rules
+	}
+
+	rules = n.appendActive(rules, now)
+	for _, child := range n.children {
+		rules = child.appendSubtree(rules, now)
+	}
+
+	return rules
+}
+
+func (i *Index) root(ip netip.Addr) *indexNode {
+	if ip.Is4() {
+		return i.v4
+	}
+
+	return i.v6
+}
+
+// NormalizeIPNet приводит IP или подсеть к каноничной записи: IPv6 в сокращенной форме в нижнем регистре,
+// IPv4, отображенный в IPv6, - в виде IPv4. Адрес подсети не маскируется.
+func NormalizeIPNet(ip string) (string, error) {
+	prefix, ok := parsePrefix(ip)
+	if !ok {
+		return "", ErrInvalidInputIP
+	}
+
+	if !strings.Contains(ip, "/") {
+		return prefix.Addr().String(), nil
+	}
+
+	return prefix.String(), nil
+}
+
+// parsePrefix разбирает IP или подсеть правила. Отдельный IP - подсеть из одного адреса.
+func parsePrefix(ip string) (netip.Prefix, bool) {
+	if strings.Contains(ip, "/") {
+		prefix, err := netip.ParsePrefix(ip)
+		if err != nil {
+			return netip.Prefix{}, false
+		}
+
+		if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+			prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+		}
+
+		return prefix, true
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	addr = addr.Unmap().WithZone("")
+
+	return netip.PrefixFrom(addr, addr.BitLen()), true
+}
+
+func addrBit(addr []byte, bit int) int {
+	return int(addr[bit/8]>>(7-bit%8)) & 1
+}
@@ -0,0 +1,126 @@
+package rule_test
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndex_Match(t *testing.T) {
+	index := rule.NewIndex(rule.Rules{
+		{ID: 1, IP: "10.0.0.0/8"},
+		{ID: 2, IP: "10.1.0.0/16"},
+		{ID: 3, IP: "10.1.2.3"},
+		{ID: 4, IP: "2001:db8::/32"},
+		{ID: 5, IP: "2001:db8:1::/48"},
+		{ID: 6, IP: "not-a-cidr"},
+		{ID: 7, IP: "10.1.0.0/16"},
+		{ID: 8, IP: "172.16.5.1/12"},
+	})
+
+	tests := []struct {
+		ip       string
+		expected int
+	}{
+		{ip: "10.200.0.1", expected: 1},
+		{ip: "10.1.200.1", expected: 2},
+		{ip: "10.1.2.3", expected: 3},
+		{ip: "::ffff:10.1.2.3", expected: 3},
+		{ip: "11.0.0.1", expected: 0},
+		{ip: "2001:db8:2::1", expected: 4},
+		{ip: "2001:db8:1::1", expected: 5},
+		{ip: "2001:db9::1", expected: 0},
+		{ip: "172.31.0.1", expected: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			matched := index.Match(netip.MustParseAddr(tt.ip))
+			if tt.expected == 0 {
+				require.Nil(t, matched)
+
+				return
+			}
+
+			require.NotNil(t, matched)
+			require.Equal(t, tt.expected, matched.ID)
+		})
+	}
+}
+
+func TestIndex_MatchAll(t *testing.T) {
+	index := rule.NewIndex(rule.Rules{
+		{ID: 1, IP: "0.0.0.0/0"},
+		{ID: 2, IP: "::/0"},
+	})
+
+	require.Equal(t, 1, index.Match(netip.MustParseAddr("8.8.8.8")).ID)
+	require.Equal(t, 2, index.Match(netip.MustParseAddr("2001:4860::8888")).ID)
+	require.Nil(t, rule.NewIndex(nil).Match(netip.MustParseAddr("8.8.8.8")))
+}
+
+func TestIndex_Overlapping(t *testing.T) {
+	expired := time.Now().Add(-time.Minute)
+	index := rule.NewIndex(rule.Rules{
+		{ID: 1, IP: "10.0.0.0/8"},
+		{ID: 2, IP: "10.1.0.0/16"},
+		{ID: 3, IP: "10.1.2.3"},
+		{ID: 4, IP: "10.2.0.0/16"},
+		{ID: 5, IP: "10.1.3.0/24", ExpiresAt: &expired},
+		{ID: 6, IP: "192.168.0.0/16"},
+		{ID: 7, IP: "2001:db8::/32"},
+	})
+
+	tests := []struct {
+		prefix   string
+		expected []int
+	}{
+		{prefix: "10.1.0.0/16", expected: []int{1, 2, 3}},
+		{prefix: "10.1.2.0/24", expected: []int{1, 2, 3}},
+		{prefix: "10.1.3.0/24", expected: []int{1, 2}},
+		{prefix: "10.0.0.0/8", expected: []int{1, 2, 3, 4}},
+		{prefix: "0.0.0.0/0", expected: []int{1, 2, 3, 4, 6}},
+		{prefix: "11.0.0.0/8", expected: nil},
+		{prefix: "2001:db8:1::/48", expected: []int{7}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			var ids []int
+			for _, r := range index.Overlapping(netip.MustParsePrefix(tt.prefix)) {
+				ids = append(ids, r.ID)
+			}
+
+			require.Equal(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestNormalizeIPNet(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{input: "192.168.1.1", expected: "192.168.1.1"},
+		{input: "192.168.1.1/24", expected: "192.168.1.1/24"},
+		{input: "2001:DB8:0:0::1", expected: "2001:db8::1"},
+		{input: "2001:0db8::/32", expected: "2001:db8::/32"},
+		{input: "::ffff:10.0.0.1", expected: "10.0.0.1"},
+		{input: "::ffff:10.0.0.0/104", expected: "10.0.0.0/8"},
+		{input: "fe80::1%eth0", expected: "fe80::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			normalized, err := rule.NormalizeIPNet(tt.input)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, normalized)
+		})
+	}
+
+	_, err := rule.NormalizeIPNet("10.0.0.0/33")
+	require.ErrorIs(t, err, rule.ErrInvalidInputIP)
+}
@@ -5,6 +5,8 @@
 package rule
 
 import (
+	"time"
+
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
 	mock "github.com/stretchr/testify/mock"
 )
@@ -36,6 +38,84 @@ func (_m *MockIStorage) EXPECT() *MockIStorage_Expecter {
 	return &MockIStorage_Expecter{mock: &_m.Mock}
 }
 
+// ApplyFeed provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) ApplyFeed(feed string, add rule.Rules, remove []int) (int, int, error) {
+	ret := _mock.Called(feed, add, remove)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyFeed")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(string, rule.Rules, []int) (int, int, error)); ok {
+		return returnFunc(feed, add, remove)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string, rule.Rules, []int) int); ok {
+		r0 = returnFunc(feed, add, remove)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(string, rule.Rules, []int) int); ok {
+		r1 = returnFunc(feed, add, remove)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(string, rule.Rules, []int) error); ok {
+		r2 = returnFunc(feed, add, remove)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockIStorage_ApplyFeed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ApplyFeed'
+type MockIStorage_ApplyFeed_Call struct {
+	*mock.Call
+}
+
+// ApplyFeed is a helper method to define mock.On call
+//   - feed string
+//   - add rule.Rules
+//   - remove []int
+func (_e *MockIStorage_Expecter) ApplyFeed(feed interface{}, add interface{}, remove interface{}) *MockIStorage_ApplyFeed_Call {
+	return &MockIStorage_ApplyFeed_Call{Call: _e.mock.On("ApplyFeed", feed, add, remove)}
+}
+
+func (_c *MockIStorage_ApplyFeed_Call) Run(run func(feed string, add rule.Rules, remove []int)) *MockIStorage_ApplyFeed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		var arg1 rule.Rules
+		if args[1] != nil {
+			arg1 = args[1].(rule.Rules)
+		}
+		var arg2 []int
+		if args[2] != nil {
+			arg2 = args[2].([]int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_ApplyFeed_Call) Return(added int, removed int, err error) *MockIStorage_ApplyFeed_Call {
+	_c.Call.Return(added, removed, err)
+	return _c
+}
+
+func (_c *MockIStorage_ApplyFeed_Call) RunAndReturn(run func(feed string, add rule.Rules, remove []int) (int, int, error)) *MockIStorage_ApplyFeed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function for the type MockIStorage
 func (_mock *MockIStorage) Create(rule1 rule.Rule) (int, error) {
 	ret := _mock.Called(rule1)
@@ -147,6 +227,66 @@ func (_c *MockIStorage_Delete_Call) RunAndReturn(run func(id int) error) *MockIS
 	return _c
 }
 
+// DeleteExpired provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) DeleteExpired(now time.Time) (int, error) {
+	ret := _mock.Called(now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpired")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(time.Time) (int, error)); ok {
+		return returnFunc(now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(time.Time) int); ok {
+		r0 = returnFunc(now)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = returnFunc(now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStorage_DeleteExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpired'
+type MockIStorage_DeleteExpired_Call struct {
+	*mock.Call
+}
+
+// DeleteExpired is a helper method to define mock.On call
+//   - now time.Time
+func (_e *MockIStorage_Expecter) DeleteExpired(now interface{}) *MockIStorage_DeleteExpired_Call {
+	return &MockIStorage_DeleteExpired_Call{Call: _e.mock.On("DeleteExpired", now)}
+}
+
+func (_c *MockIStorage_DeleteExpired_Call) Run(run func(now time.Time)) *MockIStorage_DeleteExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 time.Time
+		if args[0] != nil {
+			arg0 = args[0].(time.Time)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_DeleteExpired_Call) Return(n int, err error) *MockIStorage_DeleteExpired_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockIStorage_DeleteExpired_Call) RunAndReturn(run func(now time.Time) (int, error)) *MockIStorage_DeleteExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Find provides a mock function for the type MockIStorage
 func (_mock *MockIStorage) Find(ip string, ruleType rule.Type) (*rule.Rules, error) {
 	ret := _mock.Called(ip, ruleType)
@@ -215,6 +355,68 @@ func (_c *MockIStorage_Find_Call) RunAndReturn(run func(ip string, ruleType rule
 	return _c
 }
 
+// GetForFeed provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) GetForFeed(feed string) (*rule.Rules, error) {
+	ret := _mock.Called(feed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForFeed")
+	}
+
+	var r0 *rule.Rules
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(string) (*rule.Rules, error)); ok {
+		return returnFunc(feed)
+	}
+	if returnFunc, ok := ret.Get(0).(func(string) *rule.Rules); ok {
+		r0 = returnFunc(feed)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rule.Rules)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+		r1 = returnFunc(feed)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStorage_GetForFeed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetForFeed'
+type MockIStorage_GetForFeed_Call struct {
+	*mock.Call
+}
+
+// GetForFeed is a helper method to define mock.On call
+//   - feed string
+func (_e *MockIStorage_Expecter) GetForFeed(feed interface{}) *MockIStorage_GetForFeed_Call {
+	return &MockIStorage_GetForFeed_Call{Call: _e.mock.On("GetForFeed", feed)}
+}
+
+func (_c *MockIStorage_GetForFeed_Call) Run(run func(feed string)) *MockIStorage_GetForFeed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 string
+		if args[0] != nil {
+			arg0 = args[0].(string)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_GetForFeed_Call) Return(rules *rule.Rules, err error) *MockIStorage_GetForFeed_Call {
+	_c.Call.Return(rules, err)
+	return _c
+}
+
+func (_c *MockIStorage_GetForFeed_Call) RunAndReturn(run func(feed string) (*rule.Rules, error)) *MockIStorage_GetForFeed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetForType provides a mock function for the type MockIStorage
 func (_mock *MockIStorage) GetForType(ruleType rule.Type) (*rule.Rules, error) {
 	ret := _mock.Called(ruleType)
@@ -276,3 +478,143 @@ func (_c *MockIStorage_GetForType_Call) RunAndReturn(run func(ruleType rule.Type
 	_c.Call.Return(run)
 	return _c
 }
+
+// Import provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) Import(ruleType rule.Type, rules rule.Rules, replace bool) (int, int, error) {
+	ret := _mock.Called(ruleType, rules, replace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 int
+	var r1 int
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(rule.Type, rule.Rules, bool) (int, int, error)); ok {
+		return returnFunc(ruleType, rules, replace)
+	}
+	if returnFunc, ok := ret.Get(0).(func(rule.Type, rule.Rules, bool) int); ok {
+		r0 = returnFunc(ruleType, rules, replace)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(rule.Type, rule.Rules, bool) int); ok {
+		r1 = returnFunc(ruleType, rules, replace)
+	} else {
+		r1 = ret.Get(1).(int)
+	}
+	if returnFunc, ok := ret.Get(2).(func(rule.Type, rule.Rules, bool) error); ok {
+		r2 = returnFunc(ruleType, rules, replace)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockIStorage_Import_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Import'
+type MockIStorage_Import_Call struct {
+	*mock.Call
+}
+
+// Import is a helper method to define mock.On call
+//   - ruleType rule.Type
+//   - rules rule.Rules
+//   - replace bool
+func (_e *MockIStorage_Expecter) Import(ruleType interface{}, rules interface{}, replace interface{}) *MockIStorage_Import_Call {
+	return &MockIStorage_Import_Call{Call: _e.mock.On("Import", ruleType, rules, replace)}
+}
+
+func (_c *MockIStorage_Import_Call) Run(run func(ruleType rule.Type, rules rule.Rules, replace bool)) *MockIStorage_Import_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 rule.Type
+		if args[0] != nil {
+			arg0 = args[0].(rule.Type)
+		}
+		var arg1 rule.Rules
+		if args[1] != nil {
+			arg1 = args[1].(rule.Rules)
+		}
+		var arg2 bool
+		if args[2] != nil {
+			arg2 = args[2].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_Import_Call) Return(added int, removed int, err error) *MockIStorage_Import_Call {
+	_c.Call.Return(added, removed, err)
+	return _c
+}
+
+func (_c *MockIStorage_Import_Call) RunAndReturn(run func(ruleType rule.Type, rules rule.Rules, replace bool) (int, int, error)) *MockIStorage_Import_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type MockIStorage
+func (_mock *MockIStorage) List(filter rule.ListFilter) (*rule.Rules, error) {
+	ret := _mock.Called(filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *rule.Rules
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(rule.ListFilter) (*rule.Rules, error)); ok {
+		return returnFunc(filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(rule.ListFilter) *rule.Rules); ok {
+		r0 = returnFunc(filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rule.Rules)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(rule.ListFilter) error); ok {
+		r1 = returnFunc(filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStorage_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockIStorage_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - filter rule.ListFilter
+func (_e *MockIStorage_Expecter) List(filter interface{}) *MockIStorage_List_Call {
+	return &MockIStorage_List_Call{Call: _e.mock.On("List", filter)}
+}
+
+func (_c *MockIStorage_List_Call) Run(run func(filter rule.ListFilter)) *MockIStorage_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 rule.ListFilter
+		if args[0] != nil {
+			arg0 = args[0].(rule.ListFilter)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStorage_List_Call) Return(rules *rule.Rules, err error) *MockIStorage_List_Call {
+	_c.Call.Return(rules, err)
+	return _c
+}
+
+func (_c *MockIStorage_List_Call) RunAndReturn(run func(filter rule.ListFilter) (*rule.Rules, error)) *MockIStorage_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
@@ -1,5 +1,10 @@
 package rule
 
+import (
+	"io"
+	"time"
+)
+
 type Type string
 
 const (
@@ -7,29 +12,121 @@ const (
 	WhiteList Type = "white"
 )
 
+// Source способ, которым правило добавлено в список.
+type Source string
+
+const (
+	SourceManual  Source = "manual"
+	SourceCLI     Source = "cli"
+	SourceAutoBan Source = "auto-ban"
+	SourceImport  Source = "import"
+	SourceFeed    Source = "feed"
+)
+
 type Rules []Rule
 
 type Rule struct {
-	ID       int
-	IP       string
+	ID       int    `json:"id"`
+	IP       string `json:"ip"`
+	RuleType Type   `json:"type"`
+	// Время, после которого правило перестает действовать. Если не задано, правило бессрочное.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Произвольное описание причины добавления правила.
+	Reason    string    `json:"reason,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Source    Source    `json:"source"`
+	// Имя источника блоклиста, которому принадлежит правило с источником SourceFeed.
+	Feed string `json:"feed,omitempty"`
+}
+
+// AddOptions параметры добавляемого правила.
+type AddOptions struct {
+	// Время, после которого правило перестает действовать. Если не задано, правило бессрочное.
+	ExpiresAt *time.Time
+	Reason    string
+	CreatedBy string
+	// Способ добавления правила, по умолчанию SourceManual.
+	Source Source
+	// Добавить правило, даже если оно конфликтует с действующими правилами.
+	Force bool
+}
+
+// Expired проверяет, истек ли срок действия правила к моменту now.
+func (r Rule) Expired(now time.Time) bool {
+	return r.ExpiresAt != nil && !now.Before(*r.ExpiresAt)
+}
+
+// ListFilter отбор и постраничная выборка действующих правил списка. Пустые поля не ограничивают выборку.
+type ListFilter struct {
 	RuleType Type
+	// IP, который должен попадать в подсеть правила.
+	ContainsIP string
+	// Подсеть, в которую должна входить подсеть правила.
+	Prefix string
+	// Подстрока причины без учета регистра.
+	Reason    string
+	CreatedBy string
+	Source    Source
+	// Выборка правил с ID больше AfterID в порядке возрастания ID.
+	AfterID int
+	Limit   int
+}
+
+// Page страница правил. NextAfterID - значение AfterID для следующей страницы, 0 для последней.
+type Page struct {
+	Rules       Rules
+	NextAfterID int
 }
 
 type IStorage interface {
 	Create(rule Rule) (int, error)
 	Delete(id int) error
 
+	// DeleteExpired удаляет правила, срок действия которых истек к моменту now, и возвращает их количество.
+	DeleteExpired(now time.Time) (int, error)
+
+	// GetForType возвращает действующие правила списка.
 	GetForType(ruleType Type) (*Rules, error)
 	Find(ip string, ruleType Type) (*Rules, error)
+	// List возвращает не более filter.Limit действующих правил, подходящих под фильтр.
+	List(filter ListFilter) (*Rules, error)
+
+	// Import в одной транзакции удаляет правила списка ruleType с источником SourceImport, если задан replace,
+	// и добавляет правила, подсетей которых нет в списке. Возвращает количество добавленных и удаленных правил.
+	Import(ruleType Type, rules Rules, replace bool) (added, removed int, err error)
+
+	// GetForFeed возвращает правила черного списка, принадлежащие источнику блоклиста feed.
+	GetForFeed(feed string) (*Rules, error)
+	// ApplyFeed в одной транзакции удаляет правила источника feed с ID из remove и добавляет правила add,
+	// подсетей которых нет в черном списке. Возвращает количество добавленных и удаленных правил.
+	ApplyFeed(feed string, add Rules, remove []int) (added, removed int, err error)
 }
 
 type IService interface {
 	InWhiteList(ip string) (bool, error)
 	InBlackList(ip string) (bool, error)
+	// Match возвращает первое правило списка listType, под которое попадает ip, или nil.
+	Match(ip string, listType Type) (*Rule, error)
 
-	WhiteListAdd(ip string) error
+	WhiteListAdd(ip string, options AddOptions) error
 	WhiteListDelete(ip string) error
 
-	BlackListAdd(ip string) error
+	BlackListAdd(ip string, options AddOptions) error
 	BlackListDelete(ip string) error
+
+	// Find возвращает правила обоих списков для подсети ip, включая истекшие, но еще не удаленные.
+	Find(ip string) (*Rules, error)
+	// List возвращает страницу действующих правил списка filter.RuleType.
+	List(filter ListFilter) (*Page, error)
+
+	// Import добавляет правила из файла в список options.RuleType.
+	Import(r io.Reader, options ImportOptions) (*ImportSummary, error)
+	// Export записывает действующие правила списка ruleType в формате format.
+	Export(w io.Writer, ruleType Type, format Format) error
+	// SyncFeed приводит правила черного списка источника feed к содержимому блоклиста.
+	SyncFeed(feed string, r io.Reader) (*ImportSummary, error)
+
+	// DeleteExpired удаляет истекшие правила обоих списков и возвращает их количество.
+	DeleteExpired() (int, error)
 }
@@ -2,20 +2,53 @@ package rule
 
 import (
 	"errors"
-	"net"
+	"io"
+	"net/netip"
+	"slices"
+	"sync"
+	"time"
 )
 
 var (
 	ErrRuleNotFound   = errors.New("rule not found")
 	ErrInvalidInputIP = errors.New("incorrect IP passed")
+	ErrRuleExpired    = errors.New("rule expiry time is in the past")
+
+	ErrUnknownRuleType   = errors.New("unknown rule list type")
+	ErrUnknownImportMode = errors.New("unknown import mode")
+	ErrEmptyFeed         = errors.New("threat feed contains no valid rules")
+)
+
+// FeedCreatedBy автор правил, добавленных синхронизацией блоклистов.
+const FeedCreatedBy = "feed-sync"
+
+// RulesChangedChannel канал уведомлений PostgreSQL об изменении таблицы ip_net_rule.
+const RulesChangedChannel = "ip_net_rule_changed"
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 1000
 )
 
 type Service struct {
 	ruleStorage IStorage
+	cache       *indexCache
+}
+
+// indexCache индексы списков, загруженные из хранилища.
+// Поколение увеличивается при каждом сбросе, чтобы не сохранить индекс, загруженный до сброса.
+type indexCache struct {
+	sync.RWMutex
+
+	indexes    map[Type]*Index
+	generation uint64
 }
 
 func NewService(ruleStorage IStorage) *Service {
-	return &Service{ruleStorage: ruleStorage}
+	return &Service{
+		ruleStorage: ruleStorage,
+		cache:       &indexCache{indexes: make(map[Type]*Index)},
+	}
 }
 
 func (s Service) InWhiteList(ip string) (bool, error) {
@@ -26,33 +59,279 @@ func (s Service) InBlackList(ip string) (bool, error) {
 	return s.inList(ip, BlackList)
 }
 
-func (s Service) WhiteListAdd(ip string) error {
-	return s.listAdd(ip, WhiteList)
+func (s Service) WhiteListAdd(ip string, options AddOptions) error {
+	return s.listAdd(ip, WhiteList, options)
 }
 
 func (s Service) WhiteListDelete(ip string) error {
 	return s.listDelete(ip, WhiteList)
 }
 
-func (s Service) BlackListAdd(ip string) error {
-	return s.listAdd(ip, BlackList)
+func (s Service) BlackListAdd(ip string, options AddOptions) error {
+	return s.listAdd(ip, BlackList, options)
 }
 
 func (s Service) BlackListDelete(ip string) error {
 	return s.listDelete(ip, BlackList)
 }
 
-func (s Service) listAdd(ip string, listType Type) error {
-	_, err := s.ruleStorage.Create(Rule{
-		IP:       ip,
-		RuleType: listType,
+// Invalidate сбрасывает индексы всех списков: они будут загружены из хранилища при следующей проверке.
+// Вызывается при изменении правил другими репликами.
+func (s Service) Invalidate() {
+	s.cache.Lock()
+	defer s.cache.Unlock()
+
+	s.cache.indexes = make(map[Type]*Index)
+	s.cache.generation++
+}
+
+// DeleteExpired удаляет истекшие правила из хранилища. Истекшие правила не совпадают и до удаления.
+func (s Service) DeleteExpired() (int, error) {
+	deleted, err := s.ruleStorage.DeleteExpired(time.Now())
+	if deleted > 0 {
+		s.Invalidate()
+	}
+
+	return deleted, err
+}
+
+// Find возвращает правила белого и черного списков для подсети ip.
+func (s Service) Find(ip string) (*Rules, error) {
+	ipNet, err := NormalizeIPNet(ip)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(Rules, 0)
+	for _, listType := range []Type{WhiteList, BlackList} {
+		rules, err := s.ruleStorage.Find(ipNet, listType)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *rules...)
+	}
+
+	return &result, nil
+}
+
+// List нормализует фильтр и возвращает страницу правил. Размер страницы ограничен MaxPageSize,
+// по умолчанию DefaultPageSize.
+func (s Service) List(filter ListFilter) (*Page, error) {
+	if filter.ContainsIP != "" {
+		ip, err := netip.ParseAddr(filter.ContainsIP)
+		if err != nil {
+			return nil, ErrInvalidInputIP
+		}
+		filter.ContainsIP = ip.Unmap().WithZone("").String()
+	}
+
+	if filter.Prefix != "" {
+		prefix, err := NormalizeIPNet(filter.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		filter.Prefix = prefix
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultPageSize
+	}
+	filter.Limit = min(filter.Limit, MaxPageSize)
+
+	// лишнее правило показывает, что есть следующая страница
+	limit := filter.Limit
+	filter.Limit++
+
+	rules, err := s.ruleStorage.List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Rules: *rules}
+	if len(page.Rules) > limit {
+		page.Rules = page.Rules[:limit]
+		page.NextAfterID = page.Rules[limit-1].ID
+	}
+
+	return page, nil
+}
+
+// Import разбирает файл правил целиком и применяет его одной транзакцией. Некорректные строки пропускаются,
+// проверка конфликтов с существующими правилами не выполняется: повторяющиеся подсети не добавляются.
+func (s Service) Import(r io.Reader, options ImportOptions) (*ImportSummary, error) {
+	if options.RuleType != WhiteList && options.RuleType != BlackList {
+		return nil, ErrUnknownRuleType
+	}
+
+	if options.Mode != "" && options.Mode != ImportMerge && options.Mode != ImportReplace {
+		return nil, ErrUnknownImportMode
+	}
+
+	summary := &ImportSummary{}
+	parsed, err := parseRules(r, options.Format, summary)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(parsed))
+	rules := make(Rules, 0, len(parsed))
+	for _, rule := range parsed {
+		if seen[rule.IP] {
+			summary.Skipped++
+			continue
+		}
+		seen[rule.IP] = true
+
+		rule.RuleType = options.RuleType
+		rule.CreatedBy = options.CreatedBy
+		rule.Source = SourceImport
+		if rule.Reason == "" {
+			rule.Reason = options.Reason
+		}
+		rules = append(rules, rule)
+	}
+
+	added, removed, err := s.ruleStorage.Import(options.RuleType, rules, options.Mode == ImportReplace)
+	if err != nil {
+		return nil, err
+	}
+	s.Invalidate()
+
+	summary.Added = added
+	summary.Removed = removed
+	summary.Skipped += len(rules) - added
+
+	return summary, nil
+}
+
+func (s Service) Export(w io.Writer, ruleType Type, format Format) error {
+	if ruleType != WhiteList && ruleType != BlackList {
+		return ErrUnknownRuleType
+	}
+
+	rules, err := s.ruleStorage.GetForType(ruleType)
+	if err != nil {
+		return err
+	}
+
+	return writeRules(w, *rules, format)
+}
+
+// SyncFeed добавляет в черный список подсети блоклиста, которых нет среди правил источника feed,
+// и удаляет правила источника, отсутствующие в блоклисте. Skipped в результате - подсети, которые уже есть
+// в черном списке. Блоклист без корректных подсетей не применяется, чтобы сбой источника не снял все правила.
+func (s Service) SyncFeed(feed string, r io.Reader) (*ImportSummary, error) {
+	summary := &ImportSummary{}
+	parsed, err := parsePlain(r, summary)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed) == 0 {
+		return nil, ErrEmptyFeed
+	}
+
+	current, err := s.ruleStorage.GetForFeed(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make(map[string]int, len(*current))
+	for _, rule := range *current {
+		owned[rule.IP] = rule.ID
+	}
+
+	seen := make(map[string]bool, len(parsed))
+	var add Rules
+	for _, rule := range parsed {
+		if seen[rule.IP] {
+			continue
+		}
+		seen[rule.IP] = true
+
+		if _, ok := owned[rule.IP]; ok {
+			summary.Skipped++
+			continue
+		}
+
+		rule.RuleType = BlackList
+		rule.Reason = "threat feed " + feed
+		rule.CreatedBy = FeedCreatedBy
+		rule.Source = SourceFeed
+		rule.Feed = feed
+		add = append(add, rule)
+	}
+
+	var remove []int
+	for ip, id := range owned {
+		if !seen[ip] {
+			remove = append(remove, id)
+		}
+	}
+	slices.Sort(remove)
+
+	if len(add) == 0 && len(remove) == 0 {
+		return summary, nil
+	}
+
+	added, removed, err := s.ruleStorage.ApplyFeed(feed, add, remove)
+	if err != nil {
+		return nil, err
+	}
+	s.Invalidate()
+
+	summary.Added = added
+	summary.Removed = removed
+	summary.Skipped += len(add) - added
+
+	return summary, nil
+}
+
+func (s Service) listAdd(ip string, listType Type, options AddOptions) error {
+	ipNet, err := NormalizeIPNet(ip)
+	if err != nil {
+		return err
+	}
+
+	if options.ExpiresAt != nil && !options.ExpiresAt.After(time.Now()) {
+		return ErrRuleExpired
+	}
+
+	if !options.Force {
+		conflicts, err := s.findConflicts(ipNet, listType)
+		if err != nil {
+			return err
+		}
+
+		if len(conflicts) > 0 {
+			return &ConflictError{Conflicts: conflicts}
+		}
+	}
+
+	source := options.Source
+	if source == "" {
+		source = SourceManual
+	}
+
+	_, err = s.ruleStorage.Create(Rule{
+		IP:        ipNet,
+		RuleType:  listType,
+		ExpiresAt: options.ExpiresAt,
+		Reason:    options.Reason,
+		CreatedBy: options.CreatedBy,
+		Source:    source,
 	})
+	s.Invalidate()
 
 	return err
 }
 
 func (s Service) listDelete(ip string, listType Type) error {
-	rules, err := s.ruleStorage.Find(ip, listType)
+	ipNet, err := NormalizeIPNet(ip)
+	if err != nil {
+		return err
+	}
+
+	rules, err := s.ruleStorage.Find(ipNet, listType)
 	if err != nil {
 		return err
 	}
@@ -61,6 +340,7 @@ func (s Service) listDelete(ip string, listType Type) error {
 		return ErrRuleNotFound
 	}
 
+	defer s.Invalidate()
 	for _, rule := range *rules {
 		deleteErr := s.ruleStorage.Delete(rule.ID)
 		if deleteErr != nil {
@@ -71,26 +351,50 @@ func (s Service) listDelete(ip string, listType Type) error {
 	return nil
 }
 
-func (s Service) inList(ip string, listType Type) (bool, error) {
-	parsedIP := net.ParseIP(ip)
-	if parsedIP == nil {
-		return false, ErrInvalidInputIP
+// Match возвращает правило списка с самой специфичной подсетью, содержащей ip.
+// Правила ищутся в индексе, который загружается из хранилища при первом обращении.
+func (s Service) Match(ip string, listType Type) (*Rule, error) {
+	parsedIP, err := netip.ParseAddr(ip)
+	if err != nil {
+		return nil, ErrInvalidInputIP
+	}
+
+	index, err := s.index(listType)
+	if err != nil {
+		return nil, err
+	}
+
+	return index.Match(parsedIP), nil
+}
+
+func (s Service) index(listType Type) (*Index, error) {
+	s.cache.RLock()
+	index, found := s.cache.indexes[listType]
+	generation := s.cache.generation
+	s.cache.RUnlock()
+
+	if found {
+		return index, nil
 	}
 
 	rules, err := s.ruleStorage.GetForType(listType)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
+	index = NewIndex(*rules)
 
-	for _, rule := range *rules {
-		if parsedIP.Equal(net.ParseIP(rule.IP)) {
-			return true, nil
-		}
+	s.cache.Lock()
+	defer s.cache.Unlock()
 
-		if _, netIP, err := net.ParseCIDR(rule.IP); err == nil && netIP.Contains(parsedIP) {
-			return true, nil
-		}
+	if s.cache.generation == generation {
+		s.cache.indexes[listType] = index
 	}
 
-	return false, nil
+	return index, nil
+}
+
+func (s Service) inList(ip string, listType Type) (bool, error) {
+	rule, err := s.Match(ip, listType)
+
+	return rule != nil, err
 }
@@ -2,10 +2,13 @@ package rule_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
 	rulemocks "github.com/rainb0w-clwn/go_auth_limiter/internal/rule/mocks"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -150,19 +153,25 @@ func TestService_ListAdd(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			service, storage := newService(t)
 
+			storage.On("GetForType", mock.Anything).Return(&rule.Rules{}, nil).Twice()
 			storage.
 				On("Create", rule.Rule{
-					IP:       "127.0.0.1",
-					RuleType: tt.listType,
+					IP:        "127.0.0.1",
+					RuleType:  tt.listType,
+					Reason:    "office",
+					CreatedBy: "admin",
+					Source:    rule.SourceCLI,
 				}).
 				Return(1, nil).
 				Once()
 
+			options := rule.AddOptions{Reason: "office", CreatedBy: "admin", Source: rule.SourceCLI}
+
 			var err error
 			if tt.listType == rule.WhiteList {
-				err = service.WhiteListAdd("127.0.0.1")
+				err = service.WhiteListAdd("127.0.0.1", options)
 			} else {
-				err = service.BlackListAdd("127.0.0.1")
+				err = service.BlackListAdd("127.0.0.1", options)
 			}
 
 			require.NoError(t, err)
@@ -254,3 +263,551 @@ func TestService_ListDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestService_Match(t *testing.T) {
+	service, storage := newService(t)
+
+	storage.
+		On("GetForType", rule.BlackList).
+		Return(&rule.Rules{
+			{ID: 1, IP: "10.0.0.1", RuleType: rule.BlackList},
+			{ID: 2, IP: "192.168.0.0/16", RuleType: rule.BlackList},
+		}, nil)
+
+	matched, err := service.Match("192.168.10.5", rule.BlackList)
+	require.NoError(t, err)
+	require.NotNil(t, matched)
+	require.Equal(t, 2, matched.ID)
+	require.Equal(t, "192.168.0.0/16", matched.IP)
+
+	matched, err = service.Match("8.8.8.8", rule.BlackList)
+	require.NoError(t, err)
+	require.Nil(t, matched)
+
+	_, err = service.Match("not ip", rule.BlackList)
+	require.ErrorIs(t, err, rule.ErrInvalidInputIP)
+}
+
+func TestService_IndexCache(t *testing.T) {
+	service, storage := newService(t)
+
+	storage.
+		On("GetForType", rule.BlackList).
+		Return(&rule.Rules{{ID: 1, IP: "10.0.0.0/8", RuleType: rule.BlackList}}, nil).
+		Once()
+
+	// повторные проверки не обращаются к хранилищу
+	for range 3 {
+		inList, err := service.InBlackList("10.1.1.1")
+		require.NoError(t, err)
+		require.True(t, inList)
+	}
+	storage.AssertExpectations(t)
+
+	// конфликты ищутся в индексах без повторной загрузки, добавление правила сбрасывает индекс
+	storage.On("GetForType", rule.WhiteList).Return(&rule.Rules{}, nil).Once()
+	storage.
+		On("Create", rule.Rule{IP: "192.168.0.0/16", RuleType: rule.BlackList, Source: rule.SourceManual}).
+		Return(2, nil).
+		Once()
+	require.NoError(t, service.BlackListAdd("192.168.0.0/16", rule.AddOptions{}))
+
+	storage.
+		On("GetForType", rule.BlackList).
+		Return(&rule.Rules{
+			{ID: 1, IP: "10.0.0.0/8", RuleType: rule.BlackList},
+			{ID: 2, IP: "192.168.0.0/16", RuleType: rule.BlackList},
+		}, nil).
+		Once()
+	inList, err := service.InBlackList("192.168.1.1")
+	require.NoError(t, err)
+	require.True(t, inList)
+
+	// сброс по уведомлению
+	service.Invalidate()
+	storage.
+		On("GetForType", rule.BlackList).
+		Return(&rule.Rules{}, nil).
+		Once()
+	inList, err = service.InBlackList("192.168.1.1")
+	require.NoError(t, err)
+	require.False(t, inList)
+
+	storage.AssertExpectations(t)
+}
+
+func TestService_ListAddIPv6(t *testing.T) {
+	service, storage := newService(t)
+
+	storage.On("GetForType", mock.Anything).Return(&rule.Rules{}, nil).Twice()
+	storage.
+		On("Create", rule.Rule{IP: "2001:db8::/32", RuleType: rule.BlackList, Source: rule.SourceManual}).
+		Return(1, nil).
+		Once()
+	require.NoError(t, service.BlackListAdd("2001:0DB8::/32", rule.AddOptions{}))
+
+	storage.
+		On("Find", "2001:db8::/32", rule.BlackList).
+		Return(&rule.Rules{{ID: 1}}, nil).
+		Once()
+	storage.
+		On("Delete", 1).
+		Return(nil).
+		Once()
+	require.NoError(t, service.BlackListDelete("2001:db8:0::/32"))
+
+	require.ErrorIs(t, service.WhiteListAdd("not-a-cidr", rule.AddOptions{}), rule.ErrInvalidInputIP)
+	storage.AssertExpectations(t)
+}
+
+func TestService_Expiry(t *testing.T) {
+	service, storage := newService(t)
+
+	// правило с истекшим сроком не создается
+	past := time.Now().Add(-time.Minute)
+	require.ErrorIs(t, service.BlackListAdd("10.0.0.0/8", rule.AddOptions{ExpiresAt: &past}), rule.ErrRuleExpired)
+
+	future := time.Now().Add(time.Hour)
+	storage.On("GetForType", mock.Anything).Return(&rule.Rules{}, nil).Twice()
+	storage.
+		On("Create", rule.Rule{
+			IP:        "10.0.0.0/8",
+			RuleType:  rule.BlackList,
+			ExpiresAt: &future,
+			Source:    rule.SourceManual,
+		}).
+		Return(1, nil).
+		Once()
+	require.NoError(t, service.BlackListAdd("10.0.0.0/8", rule.AddOptions{ExpiresAt: &future}))
+
+	// истекшее правило не совпадает, даже если индекс загружен до истечения срока
+	expiresSoon := time.Now().Add(50 * time.Millisecond)
+	storage.
+		On("GetForType", rule.BlackList).
+		Return(&rule.Rules{
+			{ID: 1, IP: "10.0.0.0/8", RuleType: rule.BlackList},
+			{ID: 2, IP: "10.1.0.0/16", RuleType: rule.BlackList, ExpiresAt: &expiresSoon},
+		}, nil).
+		Once()
+
+	matched, err := service.Match("10.1.1.1", rule.BlackList)
+	require.NoError(t, err)
+	require.Equal(t, 2, matched.ID)
+
+	time.Sleep(60 * time.Millisecond)
+	matched, err = service.Match("10.1.1.1", rule.BlackList)
+	require.NoError(t, err)
+	require.Equal(t, 1, matched.ID)
+
+	// удаление истекших правил сбрасывает индекс
+	storage.On("DeleteExpired", mock.AnythingOfType("time.Time")).Return(1, nil).Once()
+	deleted, err := service.DeleteExpired()
+	require.NoError(t, err)
+	require.Equal(t, 1, deleted)
+
+	storage.
+		On("GetForType", rule.BlackList).
+		Return(&rule.Rules{{ID: 1, IP: "10.0.0.0/8", RuleType: rule.BlackList}}, nil).
+		Once()
+	_, err = service.Match("10.1.1.1", rule.BlackList)
+	require.NoError(t, err)
+
+	storage.AssertExpectations(t)
+}
+
+func TestService_Find(t *testing.T) {
+	service, storage := newService(t)
+
+	storage.
+		On("Find", "10.0.0.0/8", rule.WhiteList).
+		Return(&rule.Rules{}, nil).
+		Once()
+	storage.
+		On("Find", "10.0.0.0/8", rule.BlackList).
+		Return(&rule.Rules{{ID: 1, IP: "10.0.0.0/8", RuleType: rule.BlackList, Reason: "brute force"}}, nil).
+		Once()
+
+	rules, err := service.Find("10.0.0.0/8")
+	require.NoError(t, err)
+	require.Len(t, *rules, 1)
+	require.Equal(t, "brute force", (*rules)[0].Reason)
+
+	_, err = service.Find("not-a-cidr")
+	require.ErrorIs(t, err, rule.ErrInvalidInputIP)
+
+	storage.AssertExpectations(t)
+}
+
+func TestService_List(t *testing.T) {
+	service, storage := newService(t)
+
+	// фильтр нормализуется, запрашивается на одно правило больше размера страницы
+	storage.
+		On("List", rule.ListFilter{
+			RuleType:   rule.BlackList,
+			ContainsIP: "10.1.2.3",
+			Prefix:     "2001:db8::/32",
+			Limit:      3,
+		}).
+		Return(&rule.Rules{{ID: 1}, {ID: 5}, {ID: 9}}, nil).
+		Once()
+
+	page, err := service.List(rule.ListFilter{
+		RuleType:   rule.BlackList,
+		ContainsIP: "::ffff:10.1.2.3",
+		Prefix:     "2001:0DB8::/32",
+		Limit:      2,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.Rules, 2)
+	require.Equal(t, 5, page.NextAfterID)
+
+	// последняя страница
+	storage.
+		On("List", rule.ListFilter{RuleType: rule.BlackList, AfterID: 5, Limit: rule.DefaultPageSize + 1}).
+		Return(&rule.Rules{{ID: 9}}, nil).
+		Once()
+
+	page, err = service.List(rule.ListFilter{RuleType: rule.BlackList, AfterID: 5})
+	require.NoError(t, err)
+	require.Len(t, page.Rules, 1)
+	require.Zero(t, page.NextAfterID)
+
+	// размер страницы ограничен
+	storage.
+		On("List", rule.ListFilter{RuleType: rule.WhiteList, Limit: rule.MaxPageSize + 1}).
+		Return(&rule.Rules{}, nil).
+		Once()
+
+	_, err = service.List(rule.ListFilter{RuleType: rule.WhiteList, Limit: rule.MaxPageSize * 2})
+	require.NoError(t, err)
+
+	_, err = service.List(rule.ListFilter{RuleType: rule.WhiteList, ContainsIP: "10.0.0.0/8"})
+	require.ErrorIs(t, err, rule.ErrInvalidInputIP)
+
+	storage.AssertExpectations(t)
+}
+
+func TestService_ListAddConflicts(t *testing.T) {
+	whiteList := rule.Rules{
+		{ID: 1, IP: "10.0.0.0/16", RuleType: rule.WhiteList},
+	}
+	blackList := rule.Rules{
+		{ID: 2, IP: "192.168.0.0/16", RuleType: rule.BlackList},
+		{ID: 3, IP: "172.16.5.5", RuleType: rule.BlackList},
+	}
+
+	tests := []struct {
+		name      string
+		ip        string
+		listType  rule.Type
+		conflicts []rule.Conflict
+	}{
+		{
+			name:      "exact duplicate",
+			ip:        "172.16.5.5",
+			listType:  rule.BlackList,
+			conflicts: []rule.Conflict{{Kind: rule.ConflictDuplicate, Rule: blackList[1]}},
+		},
+		{
+			name:      "duplicate with other notation",
+			ip:        "192.168.1.1/16",
+			listType:  rule.BlackList,
+			conflicts: []rule.Conflict{{Kind: rule.ConflictDuplicate, Rule: blackList[0]}},
+		},
+		{
+			name:      "subsumed by broader rule",
+			ip:        "192.168.10.0/24",
+			listType:  rule.BlackList,
+			conflicts: []rule.Conflict{{Kind: rule.ConflictSubsumed, Rule: blackList[0]}},
+		},
+		{
+			name:      "narrower rule of opposite list",
+			ip:        "10.0.0.0/8",
+			listType:  rule.BlackList,
+			conflicts: []rule.Conflict{{Kind: rule.ConflictOverlap, Rule: whiteList[0]}},
+		},
+		{
+			name:      "broader rule of opposite list",
+			ip:        "192.168.1.0/24",
+			listType:  rule.WhiteList,
+			conflicts: []rule.Conflict{{Kind: rule.ConflictOverlap, Rule: blackList[0]}},
+		},
+		{
+			name:     "broader rule of same list",
+			ip:       "172.16.0.0/12",
+			listType: rule.BlackList,
+		},
+		{
+			name:     "no overlap",
+			ip:       "8.8.8.8",
+			listType: rule.WhiteList,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, storage := newService(t)
+
+			storage.On("GetForType", rule.WhiteList).Return(&whiteList, nil)
+			storage.On("GetForType", rule.BlackList).Return(&blackList, nil)
+			if len(tt.conflicts) == 0 {
+				storage.On("Create", mock.Anything).Return(4, nil).Once()
+			}
+
+			var err error
+			if tt.listType == rule.WhiteList {
+				err = service.WhiteListAdd(tt.ip, rule.AddOptions{})
+			} else {
+				err = service.BlackListAdd(tt.ip, rule.AddOptions{})
+			}
+
+			if len(tt.conflicts) == 0 {
+				require.NoError(t, err)
+			} else {
+				var conflictErr *rule.ConflictError
+				require.ErrorAs(t, err, &conflictErr)
+				require.ErrorIs(t, err, rule.ErrRuleConflict)
+				require.Equal(t, tt.conflicts, conflictErr.Conflicts)
+			}
+			storage.AssertExpectations(t)
+		})
+	}
+
+	t.Run("force", func(t *testing.T) {
+		service, storage := newService(t)
+
+		storage.
+			On("Create", rule.Rule{IP: "172.16.5.5", RuleType: rule.BlackList, Source: rule.SourceManual}).
+			Return(4, nil).
+			Once()
+
+		require.NoError(t, service.BlackListAdd("172.16.5.5", rule.AddOptions{Force: true}))
+		storage.AssertExpectations(t)
+	})
+}
+
+func TestService_Import(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name    string
+		format  rule.Format
+		data    string
+		rules   rule.Rules
+		invalid []rule.LineError
+	}{
+		{
+			name:   "plain",
+			format: rule.FormatPlain,
+			data: "# feed header\n" +
+				"10.0.0.0/8 ; SBL1\n" +
+				"\n" +
+				"192.168.1.1\n" +
+				"not-a-cidr\n" +
+				"10.0.0.0/8\n",
+			rules: rule.Rules{
+				{IP: "10.0.0.0/8", Reason: "feed"},
+				{IP: "192.168.1.1", Reason: "feed"},
+			},
+			invalid: []rule.LineError{{Line: 5, Message: `incorrect IP passed: "not-a-cidr"`}},
+		},
+		{
+			name:   "csv",
+			format: rule.FormatCSV,
+			data: "ip_net,reason,expires_at\n" +
+				"10.0.0.0/8,botnet," + expiresAt.Format(time.RFC3339) + "\n" +
+				"2001:DB8::/32\n" +
+				"172.16.0.0/12,old,2000-01-01T00:00:00Z\n",
+			rules: rule.Rules{
+				{IP: "10.0.0.0/8", Reason: "botnet", ExpiresAt: &expiresAt},
+				{IP: "2001:db8::/32", Reason: "feed"},
+			},
+			invalid: []rule.LineError{{Line: 4, Message: rule.ErrRuleExpired.Error()}},
+		},
+		{
+			name:   "json lines",
+			format: rule.FormatJSONLines,
+			data: `{"ipNet": "10.0.0.0/8", "reason": "botnet", "expiresAt": "` + expiresAt.Format(time.RFC3339) + `"}` + "\n" +
+				`{"ipNet": "192.168.0.0/16"}` + "\n" +
+				`{"ipNet": ` + "\n",
+			rules: rule.Rules{
+				{IP: "10.0.0.0/8", Reason: "botnet", ExpiresAt: &expiresAt},
+				{IP: "192.168.0.0/16", Reason: "feed"},
+			},
+			invalid: []rule.LineError{{Line: 3, Message: "unexpected end of JSON input"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, storage := newService(t)
+
+			expected := make(rule.Rules, 0, len(tt.rules))
+			for _, r := range tt.rules {
+				r.RuleType = rule.BlackList
+				r.CreatedBy = "importer"
+				r.Source = rule.SourceImport
+				expected = append(expected, r)
+			}
+
+			storage.
+				On("Import", rule.BlackList, mock.MatchedBy(func(rules rule.Rules) bool {
+					return assert.ObjectsAreEqualValues(expected, rules)
+				}), true).
+				Return(len(expected)-1, 3, nil).
+				Once()
+
+			summary, err := service.Import(strings.NewReader(tt.data), rule.ImportOptions{
+				RuleType:  rule.BlackList,
+				Format:    tt.format,
+				Mode:      rule.ImportReplace,
+				Reason:    "feed",
+				CreatedBy: "importer",
+			})
+			require.NoError(t, err)
+			require.Equal(t, len(expected)-1, summary.Added)
+			require.Equal(t, 3, summary.Removed)
+			require.Equal(t, len(tt.invalid), summary.Invalid)
+			require.Equal(t, tt.invalid, summary.Errors)
+			storage.AssertExpectations(t)
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		service, _ := newService(t)
+
+		_, err := service.Import(strings.NewReader(""), rule.ImportOptions{RuleType: rule.WhiteList, Format: "xml"})
+		require.ErrorIs(t, err, rule.ErrUnknownFormat)
+
+		_, err = service.Import(strings.NewReader(""), rule.ImportOptions{RuleType: "grey"})
+		require.ErrorIs(t, err, rule.ErrUnknownRuleType)
+	})
+}
+
+func TestService_Export(t *testing.T) {
+	createdAt := time.Date(2025, 12, 28, 10, 0, 0, 0, time.UTC)
+	rules := rule.Rules{
+		{IP: "10.0.0.0/8", Reason: "botnet, c2", CreatedBy: "soc", CreatedAt: createdAt, Source: rule.SourceCLI},
+		{IP: "2001:db8::/32", CreatedAt: createdAt, Source: rule.SourceImport},
+	}
+
+	tests := []struct {
+		format   rule.Format
+		expected string
+	}{
+		{
+			format:   rule.FormatPlain,
+			expected: "10.0.0.0/8\n2001:db8::/32\n",
+		},
+		{
+			format: rule.FormatCSV,
+			expected: "ip_net,reason,expires_at,created_by,created_at,source\n" +
+				"10.0.0.0/8,\"botnet, c2\",,soc,2025-12-28T10:00:00Z,cli\n" +
+				"2001:db8::/32,,,,2025-12-28T10:00:00Z,import\n",
+		},
+		{
+			format: rule.FormatJSONLines,
+			expected: `{"ipNet":"10.0.0.0/8","reason":"botnet, c2","createdBy":"soc",` +
+				`"createdAt":"2025-12-28T10:00:00Z","source":"cli"}` + "\n" +
+				`{"ipNet":"2001:db8::/32","createdAt":"2025-12-28T10:00:00Z","source":"import"}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			service, storage := newService(t)
+			storage.On("GetForType", rule.WhiteList).Return(&rules, nil).Once()
+
+			var out strings.Builder
+			require.NoError(t, service.Export(&out, rule.WhiteList, tt.format))
+			require.Equal(t, tt.expected, out.String())
+		})
+	}
+
+	// экспорт импортируется обратно
+	t.Run("round trip", func(t *testing.T) {
+		service, storage := newService(t)
+		storage.On("GetForType", rule.WhiteList).Return(&rules, nil).Once()
+
+		var out strings.Builder
+		require.NoError(t, service.Export(&out, rule.WhiteList, rule.FormatCSV))
+
+		storage.
+			On("Import", rule.WhiteList, mock.MatchedBy(func(imported rule.Rules) bool {
+				return len(imported) == 2 && imported[0].Reason == "botnet, c2" && imported[1].IP == "2001:db8::/32"
+			}), false).
+			Return(2, 0, nil).
+			Once()
+
+		summary, err := service.Import(strings.NewReader(out.String()), rule.ImportOptions{
+			RuleType: rule.WhiteList,
+			Format:   rule.FormatCSV,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 2, summary.Added)
+		require.Zero(t, summary.Invalid)
+	})
+}
+
+func TestService_SyncFeed(t *testing.T) {
+	service, storage := newService(t)
+
+	storage.EXPECT().GetForFeed("drop").Return(&rule.Rules{
+		{ID: 1, IP: "1.10.16.0/20", Feed: "drop"},
+		{ID: 2, IP: "1.19.0.0/16", Feed: "drop"},
+		{ID: 3, IP: "1.32.128.0/18", Feed: "drop"},
+	}, nil).Once()
+
+	feedRule := func(ip string) rule.Rule {
+		return rule.Rule{
+			IP:        ip,
+			RuleType:  rule.BlackList,
+			Reason:    "threat feed drop",
+			CreatedBy: rule.FeedCreatedBy,
+			Source:    rule.SourceFeed,
+			Feed:      "drop",
+		}
+	}
+	storage.EXPECT().
+		ApplyFeed("drop", rule.Rules{feedRule("2.56.192.0/22"), feedRule("5.134.128.0/19")}, []int{2, 3}).
+		Return(1, 2, nil).
+		Once()
+
+	summary, err := service.SyncFeed("drop", strings.NewReader(
+		"; Spamhaus DROP List\n"+
+			"1.10.16.0/20 ; SBL256894\n"+
+			"2.56.192.0/22 ; SBL459831\n"+
+			"5.134.128.0/19 ; SBL270738\n"+
+			"2.56.192.0/22 ; SBL459831\n"+
+			"bogus ; SBL1\n",
+	))
+
+	require.NoError(t, err)
+	require.Equal(t, &rule.ImportSummary{
+		Added:   1,
+		Skipped: 2,
+		Invalid: 1,
+		Removed: 2,
+		Errors:  []rule.LineError{{Line: 6, Message: `incorrect IP passed: "bogus"`}},
+	}, summary)
+}
+
+func TestService_SyncFeed_Unchanged(t *testing.T) {
+	service, storage := newService(t)
+
+	storage.EXPECT().GetForFeed("drop").Return(&rule.Rules{{ID: 1, IP: "1.10.16.0/20", Feed: "drop"}}, nil).Once()
+
+	summary, err := service.SyncFeed("drop", strings.NewReader("1.10.16.0/20 ; SBL256894\n"))
+
+	require.NoError(t, err)
+	require.Equal(t, &rule.ImportSummary{Skipped: 1}, summary)
+}
+
+func TestService_SyncFeed_Empty(t *testing.T) {
+	service, _ := newService(t)
+
+	_, err := service.SyncFeed("drop", strings.NewReader("; Spamhaus DROP List\nbogus\n"))
+
+	require.ErrorIs(t, err, rule.ErrEmptyFeed)
+}
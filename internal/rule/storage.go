@@ -1,13 +1,25 @@
 package rule
 
 import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/metrics"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/storage/postgres"
 )
 
 type sqlEntity struct {
-	ID       int    `db:"id"`
-	IP       string `db:"ip"`
-	RuleType string `db:"type"`
+	ID        int          `db:"id"`
+	IP        string       `db:"ip"`
+	RuleType  string       `db:"type"`
+	ExpiresAt sql.NullTime `db:"expires_at"`
+	Reason    string       `db:"reason"`
+	CreatedBy string       `db:"created_by"`
+	CreatedAt time.Time    `db:"created_at"`
+	Source    string       `db:"source"`
+	Feed      string       `db:"feed"`
 }
 
 type Storage struct {
@@ -19,14 +31,21 @@ func NewStorage(storage *postgres.Storage) *Storage {
 }
 
 func (s *Storage) Create(rule Rule) (int, error) {
+	defer metrics.ObserveQuery("rule", "Create", time.Now())
+
 	query := `
-		INSERT INTO ip_net_rule(ip, type) VALUES (:ip, :type)
+		INSERT INTO ip_net_rule(ip, type, expires_at, reason, created_by, source)
+		VALUES (:ip, :type, :expires_at, :reason, :created_by, :source)
 		RETURNING id
 	`
 
 	params := map[string]any{
-		"ip":   rule.IP,
-		"type": rule.RuleType,
+		"ip":         rule.IP,
+		"type":       rule.RuleType,
+		"expires_at": rule.ExpiresAt,
+		"reason":     rule.Reason,
+		"created_by": rule.CreatedBy,
+		"source":     rule.Source,
 	}
 
 	var id int
@@ -44,6 +63,8 @@ func (s *Storage) Create(rule Rule) (int, error) {
 }
 
 func (s *Storage) Delete(id int) error {
+	defer metrics.ObserveQuery("rule", "Delete", time.Now())
+
 	query := `
 		DELETE FROM ip_net_rule
 		WHERE id = :id
@@ -57,11 +78,36 @@ func (s *Storage) Delete(id int) error {
 	return err
 }
 
+func (s *Storage) DeleteExpired(now time.Time) (int, error) {
+	defer metrics.ObserveQuery("rule", "DeleteExpired", time.Now())
+
+	query := `
+		DELETE FROM ip_net_rule
+		WHERE expires_at <= :now
+	`
+
+	result, err := s.DB.NamedExecContext(
+		s.Ctx,
+		query,
+		map[string]any{"now": now},
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted, err := result.RowsAffected()
+
+	return int(deleted), err
+}
+
 func (s *Storage) GetForType(ruleType Type) (*Rules, error) {
+	defer metrics.ObserveQuery("rule", "GetForType", time.Now())
+
 	query := `
 		SELECT *
 		FROM ip_net_rule
 		WHERE type = :type
+			AND (expires_at IS NULL OR expires_at > now())
 	`
 
 	stmt, err := s.DB.PrepareNamedContext(s.Ctx, query)
@@ -92,6 +138,8 @@ func (s *Storage) GetForType(ruleType Type) (*Rules, error) {
 }
 
 func (s *Storage) Find(ip string, ruleType Type) (*Rules, error) {
+	defer metrics.ObserveQuery("rule", "Find", time.Now())
+
 	query := `
 		SELECT *
 		FROM ip_net_rule
@@ -127,11 +175,266 @@ func (s *Storage) Find(ip string, ruleType Type) (*Rules, error) {
 	return &result, nil
 }
 
+// likeEscaper экранирует спецсимволы шаблона LIKE: фильтр по причине ищет подстроку буквально.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (s *Storage) List(filter ListFilter) (*Rules, error) {
+	defer metrics.ObserveQuery("rule", "List", time.Now())
+
+	query := `
+		SELECT *
+		FROM ip_net_rule
+		WHERE type = :type
+			AND (expires_at IS NULL OR expires_at > now())
+			AND id > :after_id
+			AND (:contains_ip = '' OR CAST(ip AS inet) >>= CAST(NULLIF(:contains_ip, '') AS inet))
+			AND (:prefix = '' OR CAST(ip AS inet) <<= CAST(NULLIF(:prefix, '') AS inet))
+			AND (:reason = '' OR reason ILIKE '%' || :reason || '%' ESCAPE '\')
+			AND (:created_by = '' OR created_by = :created_by)
+			AND (:source = '' OR source = :source)
+		ORDER BY id
+		LIMIT :limit
+	`
+
+	stmt, err := s.DB.PrepareNamedContext(s.Ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var rows []sqlEntity
+
+	err = stmt.SelectContext(
+		s.Ctx,
+		&rows,
+		map[string]any{
+			"type":        filter.RuleType,
+			"after_id":    filter.AfterID,
+			"contains_ip": filter.ContainsIP,
+			"prefix":      filter.Prefix,
+			"reason":      likeEscaper.Replace(filter.Reason),
+			"created_by":  filter.CreatedBy,
+			"source":      filter.Source,
+			"limit":       filter.Limit,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(Rules, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, *s.sqlEntityToEntity(&r))
+	}
+
+	return &result, nil
+}
+
+// importBatchSize количество правил в одном INSERT при импорте.
+const importBatchSize = 1000
+
+func (s *Storage) Import(ruleType Type, rules Rules, replace bool) (int, int, error) {
+	defer metrics.ObserveQuery("rule", "Import", time.Now())
+
+	tx, err := s.DB.BeginTxx(s.Ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck // после Commit откат ничего не делает
+
+	if err = s.lockList(tx, ruleType); err != nil {
+		return 0, 0, err
+	}
+
+	removed := 0
+	if replace {
+		result, err := tx.NamedExecContext(
+			s.Ctx,
+			`DELETE FROM ip_net_rule WHERE type = :type AND source = :source`,
+			map[string]any{"type": ruleType, "source": SourceImport},
+		)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return 0, 0, err
+		}
+		removed = int(deleted)
+	}
+
+	added, err := s.insertMissing(tx, ruleType, rules)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+
+	return added, removed, nil
+}
+
+func (s *Storage) GetForFeed(feed string) (*Rules, error) {
+	defer metrics.ObserveQuery("rule", "GetForFeed", time.Now())
+
+	query := `
+		SELECT *
+		FROM ip_net_rule
+		WHERE type = :type
+			AND feed = :feed
+	`
+
+	stmt, err := s.DB.PrepareNamedContext(s.Ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	var rows []sqlEntity
+
+	err = stmt.SelectContext(
+		s.Ctx,
+		&rows,
+		map[string]any{
+			"type": BlackList,
+			"feed": feed,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(Rules, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, *s.sqlEntityToEntity(&r))
+	}
+
+	return &result, nil
+}
+
+func (s *Storage) ApplyFeed(feed string, add Rules, remove []int) (int, int, error) {
+	defer metrics.ObserveQuery("rule", "ApplyFeed", time.Now())
+
+	tx, err := s.DB.BeginTxx(s.Ctx, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck // после Commit откат ничего не делает
+
+	if err = s.lockList(tx, BlackList); err != nil {
+		return 0, 0, err
+	}
+
+	removed := 0
+	if len(remove) > 0 {
+		query, args, err := sqlx.In(`DELETE FROM ip_net_rule WHERE feed = ? AND id IN (?)`, feed, remove)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		result, err := tx.ExecContext(s.Ctx, tx.Rebind(query), args...)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		deleted, err := result.RowsAffected()
+		if err != nil {
+			return 0, 0, err
+		}
+		removed = int(deleted)
+	}
+
+	added, err := s.insertMissing(tx, BlackList, add)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, 0, err
+	}
+
+	return added, removed, nil
+}
+
+// lockList блокирует список ruleType до конца транзакции tx: импорты и синхронизации фидов
+// (в том числе с нескольких реплик) выполняются по очереди, и insertMissing видит правила,
+// добавленные параллельной транзакцией, вместо того чтобы добавить ту же подсеть еще раз.
+func (s *Storage) lockList(tx *sqlx.Tx, ruleType Type) error {
+	_, err := tx.ExecContext(
+		s.Ctx,
+		tx.Rebind(`SELECT pg_advisory_xact_lock(hashtext('ip_net_rule'), hashtext(?))`),
+		string(ruleType),
+	)
+
+	return err
+}
+
+// insertMissing добавляет в транзакции tx правила, подсетей которых еще нет в списке ruleType,
+// и возвращает их количество. Список должен быть заблокирован lockList.
+func (s *Storage) insertMissing(tx *sqlx.Tx, ruleType Type, rules Rules) (int, error) {
+	if len(rules) == 0 {
+		return 0, nil
+	}
+
+	var existing []string
+	err := tx.SelectContext(s.Ctx, &existing, tx.Rebind(`SELECT ip FROM ip_net_rule WHERE type = ?`), ruleType)
+	if err != nil {
+		return 0, err
+	}
+
+	exists := make(map[string]bool, len(existing))
+	for _, ip := range existing {
+		exists[ip] = true
+	}
+
+	params := make([]map[string]any, 0, len(rules))
+	for _, rule := range rules {
+		if exists[rule.IP] {
+			continue
+		}
+
+		params = append(params, map[string]any{
+			"ip":         rule.IP,
+			"type":       ruleType,
+			"expires_at": rule.ExpiresAt,
+			"reason":     rule.Reason,
+			"created_by": rule.CreatedBy,
+			"source":     rule.Source,
+			"feed":       rule.Feed,
+		})
+	}
+
+	query := `
+		INSERT INTO ip_net_rule(ip, type, expires_at, reason, created_by, source, feed)
+		VALUES (:ip, :type, :expires_at, :reason, :created_by, :source, :feed)
+	`
+	for start := 0; start < len(params); start += importBatchSize {
+		batch := params[start:min(start+importBatchSize, len(params))]
+		if _, err = tx.NamedExecContext(s.Ctx, query, batch); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(params), nil
+}
+
 func (s *Storage) sqlEntityToEntity(se *sqlEntity) *Rule {
 	e := &Rule{
-		ID:       se.ID,
-		IP:       se.IP,
-		RuleType: Type(se.RuleType),
+		ID:        se.ID,
+		IP:        se.IP,
+		RuleType:  Type(se.RuleType),
+		Reason:    se.Reason,
+		CreatedBy: se.CreatedBy,
+		CreatedAt: se.CreatedAt,
+		Source:    Source(se.Source),
+		Feed:      se.Feed,
+	}
+
+	if se.ExpiresAt.Valid {
+		expiresAt := se.ExpiresAt.Time
+		e.ExpiresAt = &expiresAt
 	}
 
 	return e
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/jmoiron/sqlx"
@@ -40,12 +41,19 @@ func TestStorage_Create(t *testing.T) {
 		WithArgs(
 			sqlmock.AnyArg(), // ip
 			sqlmock.AnyArg(), // type
+			sqlmock.AnyArg(), // expires_at
+			"office",         // reason
+			"admin",          // created_by
+			rule.SourceCLI,   // source
 		).
 		WillReturnRows(rows)
 
 	id, err := storage.Create(rule.Rule{
-		IP:       "127.0.0.1",
-		RuleType: rule.WhiteList,
+		IP:        "127.0.0.1",
+		RuleType:  rule.WhiteList,
+		Reason:    "office",
+		CreatedBy: "admin",
+		Source:    rule.SourceCLI,
 	})
 
 	require.NoError(t, err)
@@ -82,6 +90,43 @@ func TestStorage_Delete(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestStorage_DeleteExpired(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	now := time.Now()
+	mock.ExpectExec("DELETE FROM ip_net_rule").
+		WithArgs(now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	deleted, err := storage.DeleteExpired(now)
+
+	require.NoError(t, err)
+	require.Equal(t, 3, deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_GetForType_ExpiresAt(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "ip", "type", "expires_at"}).
+		AddRow(1, "127.0.0.1", "black", expiresAt).
+		AddRow(2, "10.0.0.0/24", "black", nil)
+
+	mock.ExpectPrepare("expires_at IS NULL OR expires_at > now()").
+		ExpectQuery().
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	result, err := storage.GetForType(rule.BlackList)
+
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+	require.Equal(t, expiresAt, *(*result)[0].ExpiresAt)
+	require.Nil(t, (*result)[1].ExpiresAt)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestStorage_GetForType(t *testing.T) {
 	storage, mock := newTestStorage(t)
 
@@ -131,6 +176,31 @@ func TestStorage_Find(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestStorage_Find_Metadata(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	createdAt := time.Date(2025, 12, 27, 10, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "ip", "type", "expires_at", "reason", "created_by", "created_at", "source"}).
+		AddRow(5, "10.0.0.0/8", "black", nil, "brute force", "admin", createdAt, "auto-ban")
+
+	mock.ExpectPrepare("SELECT \\*").
+		ExpectQuery().
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	result, err := storage.Find("10.0.0.0/8", rule.BlackList)
+
+	require.NoError(t, err)
+	require.Len(t, *result, 1)
+
+	require.Equal(t, "brute force", (*result)[0].Reason)
+	require.Equal(t, "admin", (*result)[0].CreatedBy)
+	require.Equal(t, createdAt, (*result)[0].CreatedAt)
+	require.Equal(t, rule.SourceAutoBan, (*result)[0].Source)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestStorage_Find_Empty(t *testing.T) {
 	storage, mock := newTestStorage(t)
 
@@ -161,3 +231,154 @@ func TestStorage_GetForType_SelectError(t *testing.T) {
 	require.Error(t, err)
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestStorage_List(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	rows := sqlmock.NewRows([]string{"id", "ip", "type", "reason", "source"}).
+		AddRow(3, "10.0.0.0/8", "black", "brute force", "cli").
+		AddRow(4, "10.1.0.0/16", "black", "brute force", "cli")
+
+	mock.ExpectPrepare("CAST\\(ip AS inet\\) >>=").
+		ExpectQuery().
+		WillReturnRows(rows)
+
+	result, err := storage.List(rule.ListFilter{
+		RuleType:   rule.BlackList,
+		ContainsIP: "10.1.2.3",
+		Reason:     "brute",
+		AfterID:    2,
+		Limit:      10,
+	})
+
+	require.NoError(t, err)
+	require.Len(t, *result, 2)
+	require.Equal(t, 3, (*result)[0].ID)
+	require.Equal(t, rule.SourceCLI, (*result)[1].Source)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_List_ReasonIsLiteral(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	reason := `50%_off\`
+	mock.ExpectPrepare("reason ILIKE '%' \\|\\| . \\|\\| '%' ESCAPE").
+		ExpectQuery().
+		WithArgs(
+			rule.WhiteList, 0, "", "", "", "",
+			`50\%\_off\\`, `50\%\_off\\`,
+			"", "", "", "", 10,
+		).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ip", "type"}))
+
+	_, err := storage.List(rule.ListFilter{RuleType: rule.WhiteList, Reason: reason, Limit: 10})
+
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_Import(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").
+		WithArgs(string(rule.BlackList)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM ip_net_rule WHERE type = . AND source = .").
+		WithArgs(rule.BlackList, rule.SourceImport).
+		WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectQuery("SELECT ip FROM ip_net_rule").
+		WithArgs(rule.BlackList).
+		WillReturnRows(sqlmock.NewRows([]string{"ip"}).AddRow("10.0.0.0/8"))
+	mock.ExpectExec("INSERT INTO ip_net_rule").
+		WithArgs(
+			"192.168.0.0/16", rule.BlackList, sqlmock.AnyArg(), "feed", "importer", rule.SourceImport, "",
+			"172.16.0.0/12", rule.BlackList, sqlmock.AnyArg(), "feed", "importer", rule.SourceImport, "",
+		).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	rules := rule.Rules{
+		{IP: "10.0.0.0/8", Reason: "feed", CreatedBy: "importer", Source: rule.SourceImport},
+		{IP: "192.168.0.0/16", Reason: "feed", CreatedBy: "importer", Source: rule.SourceImport},
+		{IP: "172.16.0.0/12", Reason: "feed", CreatedBy: "importer", Source: rule.SourceImport},
+	}
+	added, removed, err := storage.Import(rule.BlackList, rules, true)
+
+	require.NoError(t, err)
+	require.Equal(t, 2, added)
+	require.Equal(t, 5, removed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_Import_Rollback(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").
+		WithArgs(string(rule.WhiteList)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT ip FROM ip_net_rule").
+		WithArgs(rule.WhiteList).
+		WillReturnRows(sqlmock.NewRows([]string{"ip"}))
+	mock.ExpectExec("INSERT INTO ip_net_rule").
+		WillReturnError(sql.ErrConnDone)
+	mock.ExpectRollback()
+
+	_, _, err := storage.Import(rule.WhiteList, rule.Rules{{IP: "10.0.0.0/8"}}, false)
+
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_GetForFeed(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	rows := sqlmock.NewRows([]string{"id", "ip", "type", "source", "feed"}).
+		AddRow(7, "1.10.16.0/20", "black", "feed", "drop")
+
+	mock.ExpectPrepare("feed = ").
+		ExpectQuery().
+		WithArgs(rule.BlackList, "drop").
+		WillReturnRows(rows)
+
+	result, err := storage.GetForFeed("drop")
+
+	require.NoError(t, err)
+	require.Len(t, *result, 1)
+	require.Equal(t, 7, (*result)[0].ID)
+	require.Equal(t, rule.SourceFeed, (*result)[0].Source)
+	require.Equal(t, "drop", (*result)[0].Feed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_ApplyFeed(t *testing.T) {
+	storage, mock := newTestStorage(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SELECT pg_advisory_xact_lock").
+		WithArgs(string(rule.BlackList)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM ip_net_rule WHERE feed = . AND id IN \\(., .\\)").
+		WithArgs("drop", 3, 4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery("SELECT ip FROM ip_net_rule").
+		WithArgs(rule.BlackList).
+		WillReturnRows(sqlmock.NewRows([]string{"ip"}).AddRow("10.0.0.0/8"))
+	mock.ExpectExec("INSERT INTO ip_net_rule").
+		WithArgs("1.10.16.0/20", rule.BlackList, sqlmock.AnyArg(), "", rule.FeedCreatedBy, rule.SourceFeed, "drop").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	add := rule.Rules{
+		{IP: "10.0.0.0/8", CreatedBy: rule.FeedCreatedBy, Source: rule.SourceFeed, Feed: "drop"},
+		{IP: "1.10.16.0/20", CreatedBy: rule.FeedCreatedBy, Source: rule.SourceFeed, Feed: "drop"},
+	}
+	added, removed, err := storage.ApplyFeed("drop", add, []int{3, 4})
+
+	require.NoError(t, err)
+	require.Equal(t, 1, added)
+	require.Equal(t, 2, removed)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
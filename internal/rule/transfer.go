@@ -0,0 +1,274 @@
+package rule
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var ErrUnknownFormat = errors.New("unknown rules format")
+
+// Format формат файла правил для импорта и экспорта.
+type Format string
+
+const (
+	// FormatPlain подсеть в первом поле строки, комментарии после # или ;.
+	FormatPlain Format = "plain"
+	// FormatCSV колонки ip_net, reason, expires_at (RFC 3339), при экспорте также created_by, created_at, source.
+	FormatCSV Format = "csv"
+	// FormatJSONLines JSON объект правила в каждой строке.
+	FormatJSONLines Format = "jsonl"
+)
+
+// ImportMode способ применения импортируемых правил.
+type ImportMode string
+
+const (
+	// ImportMerge добавляет подсети, которых еще нет в списке.
+	ImportMerge ImportMode = "merge"
+	// ImportReplace заменяет ранее импортированные правила списка. Правила, добавленные иначе, сохраняются.
+	ImportReplace ImportMode = "replace"
+)
+
+// MaxImportErrors количество ошибок разбора, возвращаемых в результате импорта.
+const MaxImportErrors = 100
+
+type ImportOptions struct {
+	RuleType Type
+	Format   Format
+	Mode     ImportMode
+	// Причина для правил, у которых она не указана в файле.
+	Reason    string
+	CreatedBy string
+}
+
+type LineError struct {
+	Line    int    `json:"line"`
+	Message string `json:"message"`
+}
+
+// ImportSummary результат импорта. Skipped - подсети, уже присутствующие в списке или повторяющиеся в файле,
+// Removed - правила, удаленные при замене.
+type ImportSummary struct {
+	Added   int `json:"added"`
+	Skipped int `json:"skipped"`
+	Invalid int `json:"invalid"`
+	Removed int `json:"removed"`
+	// Первые MaxImportErrors ошибок разбора.
+	Errors []LineError `json:"errors,omitempty"`
+}
+
+// transferRecord правило в формате JSON lines.
+type transferRecord struct {
+	IPNet     string     `json:"ipNet"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	Source    Source     `json:"source,omitempty"`
+}
+
+var csvHeader = []string{"ip_net", "reason", "expires_at", "created_by", "created_at", "source"}
+
+// parseRules разбирает файл правил. Некорректные строки не прерывают разбор и попадают в summary.
+func parseRules(r io.Reader, format Format, summary *ImportSummary) (Rules, error) {
+	var rules Rules
+	var err error
+
+	switch format {
+	case FormatPlain, "":
+		rules, err = parsePlain(r, summary)
+	case FormatCSV:
+		rules, err = parseCSV(r, summary)
+	case FormatJSONLines:
+		rules, err = parseJSONLines(r, summary)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+
+	return rules, err
+}
+
+func parsePlain(r io.Reader, summary *ImportSummary) (Rules, error) {
+	var rules Rules
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		text, _, _ = strings.Cut(text, ";")
+
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if rule, ok := newImportedRule(fields[0], "", "", line, summary); ok {
+			rules = append(rules, rule)
+		}
+	}
+
+	return rules, scanner.Err()
+}
+
+func parseCSV(r io.Reader, summary *ImportSummary) (Rules, error) {
+	var rules Rules
+
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rules, nil
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			addLineError(summary, parseErr.Line, parseErr.Err.Error())
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if line == 1 && record[0] == csvHeader[0] {
+			continue
+		}
+
+		record = append(record, "", "")
+		if rule, ok := newImportedRule(record[0], record[1], record[2], line, summary); ok {
+			rules = append(rules, rule)
+		}
+	}
+}
+
+func parseJSONLines(r io.Reader, summary *ImportSummary) (Rules, error) {
+	var rules Rules
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var record transferRecord
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			addLineError(summary, line, err.Error())
+			continue
+		}
+
+		rule, ok := newImportedRule(record.IPNet, record.Reason, "", line, summary)
+		if !ok {
+			continue
+		}
+		if record.ExpiresAt != nil {
+			if !record.ExpiresAt.After(time.Now()) {
+				addLineError(summary, line, ErrRuleExpired.Error())
+				continue
+			}
+			rule.ExpiresAt = record.ExpiresAt
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, scanner.Err()
+}
+
+// newImportedRule проверяет поля строки файла и возвращает правило с нормализованной подсетью.
+func newImportedRule(ip, reason, expiresAt string, line int, summary *ImportSummary) (Rule, bool) {
+	ipNet, err := NormalizeIPNet(strings.TrimSpace(ip))
+	if err != nil {
+		addLineError(summary, line, fmt.Sprintf("%s: %q", err, ip))
+		return Rule{}, false
+	}
+
+	rule := Rule{IP: ipNet, Reason: strings.TrimSpace(reason)}
+
+	if expiresAt = strings.TrimSpace(expiresAt); expiresAt != "" {
+		t, err := time.Parse(time.RFC3339, expiresAt)
+		if err != nil {
+			addLineError(summary, line, err.Error())
+			return Rule{}, false
+		}
+		if !t.After(time.Now()) {
+			addLineError(summary, line, ErrRuleExpired.Error())
+			return Rule{}, false
+		}
+		rule.ExpiresAt = &t
+	}
+
+	return rule, true
+}
+
+func addLineError(summary *ImportSummary, line int, message string) {
+	summary.Invalid++
+	if len(summary.Errors) < MaxImportErrors {
+		summary.Errors = append(summary.Errors, LineError{Line: line, Message: message})
+	}
+}
+
+// writeRules записывает правила в формате format.
+func writeRules(w io.Writer, rules Rules, format Format) error {
+	switch format {
+	case FormatPlain, "":
+		for _, r := range rules {
+			if _, err := fmt.Fprintln(w, r.IP); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(csvHeader); err != nil {
+			return err
+		}
+
+		for _, r := range rules {
+			expiresAt := ""
+			if r.ExpiresAt != nil {
+				expiresAt = r.ExpiresAt.Format(time.RFC3339)
+			}
+
+			err := writer.Write([]string{
+				r.IP, r.Reason, expiresAt, r.CreatedBy, r.CreatedAt.Format(time.RFC3339), string(r.Source),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+
+		return writer.Error()
+	case FormatJSONLines:
+		encoder := json.NewEncoder(w)
+		for _, r := range rules {
+			err := encoder.Encode(transferRecord{
+				IPNet:     r.IP,
+				Reason:    r.Reason,
+				ExpiresAt: r.ExpiresAt,
+				CreatedBy: r.CreatedBy,
+				CreatedAt: &r.CreatedAt,
+				Source:    r.Source,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
@@ -0,0 +1,88 @@
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/requestid"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// XActorKey метаданные с именем пользователя, от имени которого выполняется изменение.
+const XActorKey = "x-actor"
+
+func (s Service) AuditLog(_ context.Context, req *proto.AuditLogRequest) (*proto.AuditLogResponse, error) {
+	beforeID := 0
+	if req.PageToken != "" {
+		var err error
+		beforeID, err = strconv.Atoi(req.PageToken)
+		if err != nil || beforeID < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", req.PageToken)
+		}
+	}
+
+	page, err := s.app.AuditLog(audit.ListFilter{
+		Action:   audit.Action(req.Action),
+		Actor:    req.Actor,
+		Target:   req.Target,
+		BeforeID: beforeID,
+		Limit:    int(req.PageSize),
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed listing audit log: %s", err))
+
+		return nil, status.Errorf(codes.Unknown, "%s", err.Error())
+	}
+
+	response := &proto.AuditLogResponse{Entries: make([]*proto.AuditEntry, 0, len(page.Entries))}
+	for _, entry := range page.Entries {
+		response.Entries = append(response.Entries, &proto.AuditEntry{
+			Id:        int64(entry.ID),
+			Action:    string(entry.Action),
+			Actor:     entry.Actor,
+			RequestId: entry.RequestID,
+			Peer:      entry.Peer,
+			Target:    entry.Target,
+			Before:    string(entry.Before),
+			After:     string(entry.After),
+			CreatedAt: timestamppb.New(entry.CreatedAt),
+		})
+	}
+	if page.NextBeforeID > 0 {
+		response.NextPageToken = strconv.Itoa(page.NextBeforeID)
+	}
+
+	return response, nil
+}
+
+// auditActor определяет инициатора изменения: метаданные x-actor, первый адрес x-forwarded-for
+// (запросы через HTTP шлюз) или адрес клиента. x-actor и x-forwarded-for задает сам клиент,
+// поэтому рядом всегда сохраняется адрес соединения.
+func auditActor(ctx context.Context) audit.Actor {
+	actor := audit.Actor{RequestID: requestid.FromContext(ctx)}
+	if peerInfo, ok := peer.FromContext(ctx); ok {
+		actor.Peer = peerInfo.Addr.String()
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(XActorKey); len(values) > 0 && values[0] != "" {
+		actor.Identity = values[0]
+		return actor
+	}
+	if values := md.Get("x-forwarded-for"); len(values) > 0 && values[0] != "" {
+		forwardedFor, _, _ := strings.Cut(values[0], ",")
+		actor.Identity = strings.TrimSpace(forwardedFor)
+		return actor
+	}
+	actor.Identity = actor.Peer
+
+	return actor
+}
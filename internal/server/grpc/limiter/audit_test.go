@@ -0,0 +1,101 @@
+package limiter_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	mocks "github.com/rainb0w-clwn/go_auth_limiter/internal/interfaces/mocks"
+	grpclimiter "github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/requestid"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func TestService_AuditLog(t *testing.T) {
+	ctx := context.Background()
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	createdAt := time.Date(2025, 12, 29, 10, 0, 0, 0, time.UTC)
+	app.On("AuditLog", audit.ListFilter{Actor: "soc", BeforeID: 20, Limit: 1}).Return(&audit.Page{
+		Entries: audit.Entries{{
+			ID:        19,
+			Action:    audit.ActionBlackListAdd,
+			Actor:     "soc",
+			RequestID: "req-1",
+			Target:    "10.0.0.0/8",
+			After:     json.RawMessage(`[{"ip":"10.0.0.0/8"}]`),
+			CreatedAt: createdAt,
+		}},
+		NextBeforeID: 19,
+	}, nil)
+
+	resp, err := s.AuditLog(ctx, &proto.AuditLogRequest{Actor: "soc", PageToken: "20", PageSize: 1})
+	require.NoError(t, err)
+	require.Len(t, resp.Entries, 1)
+	require.Equal(t, "19", resp.NextPageToken)
+
+	entry := resp.Entries[0]
+	require.Equal(t, "blacklist.add", entry.Action)
+	require.Equal(t, "req-1", entry.RequestId)
+	require.Empty(t, entry.Before)
+	require.JSONEq(t, `[{"ip":"10.0.0.0/8"}]`, entry.After)
+	require.Equal(t, createdAt, entry.CreatedAt.AsTime())
+
+	_, err = s.AuditLog(ctx, &proto.AuditLogRequest{PageToken: "bad"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	app.AssertExpectations(t)
+}
+
+func TestService_AuditActor(t *testing.T) {
+	peerCtx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5000},
+	})
+	requestCtx := metadata.AppendToOutgoingContext(peerCtx, requestid.XRequestIDKey, "req-1")
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		expected audit.Actor
+	}{
+		{
+			// заявленный клиентом инициатор сохраняется вместе с адресом соединения
+			name:     "x-actor",
+			ctx:      metadata.NewIncomingContext(requestCtx, metadata.Pairs(grpclimiter.XActorKey, "soc")),
+			expected: audit.Actor{Identity: "soc", RequestID: "req-1", Peer: "10.0.0.1:5000"},
+		},
+		{
+			name:     "x-forwarded-for",
+			ctx:      metadata.NewIncomingContext(requestCtx, metadata.Pairs("x-forwarded-for", "1.2.3.4, 10.0.0.2")),
+			expected: audit.Actor{Identity: "1.2.3.4", RequestID: "req-1", Peer: "10.0.0.1:5000"},
+		},
+		{
+			name:     "peer",
+			ctx:      peerCtx,
+			expected: audit.Actor{Identity: "10.0.0.1:5000", Peer: "10.0.0.1:5000"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := new(mocks.MockApplication)
+			s := grpclimiter.NewService(app, new(mocks.MockLogger))
+
+			app.On("BlackListDelete", tt.expected, "10.0.0.0/8").Return(nil)
+			_, err := s.BlackListDelete(tt.ctx, &proto.BlackListDeleteRequest{IpNet: "10.0.0.0/8"})
+			require.NoError(t, err)
+
+			app.AssertExpectations(t)
+		})
+	}
+}
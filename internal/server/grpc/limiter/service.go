@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/interfaces"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
@@ -11,6 +14,7 @@ import (
 	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type Service struct {
@@ -26,19 +30,19 @@ func NewService(app appinterfaces.Application, logger appinterfaces.Logger) *Ser
 	}
 }
 
-func (s Service) WhiteListAdd(_ context.Context, req *proto.WhiteListAddRequest) (*proto.WhiteListAddResponse, error) {
-	err := s.app.WhiteListAdd(req.IpNet)
+func (s Service) WhiteListAdd(ctx context.Context, req *proto.WhiteListAddRequest) (*proto.WhiteListAddResponse, error) { //nolint:lll
+	err := s.app.WhiteListAdd(auditActor(ctx), req.IpNet, addOptions(req))
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed adding to white list: %s", err))
 
-		return nil, status.Errorf(codes.Unknown, "%s", err.Error())
+		return nil, ruleAddError(err)
 	}
 
 	return &proto.WhiteListAddResponse{}, nil
 }
 
-func (s Service) WhiteListDelete(_ context.Context, req *proto.WhiteListDeleteRequest) (*proto.WhiteListDeleteResponse, error) { //nolint:lll
-	err := s.app.WhiteListDelete(req.IpNet)
+func (s Service) WhiteListDelete(ctx context.Context, req *proto.WhiteListDeleteRequest) (*proto.WhiteListDeleteResponse, error) { //nolint:lll
+	err := s.app.WhiteListDelete(auditActor(ctx), req.IpNet)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed deleting from white list: %s", err))
 
@@ -53,19 +57,19 @@ func (s Service) WhiteListDelete(_ context.Context, req *proto.WhiteListDeleteRe
 	return &proto.WhiteListDeleteResponse{}, nil
 }
 
-func (s Service) BlackListAdd(_ context.Context, req *proto.BlackListAddRequest) (*proto.BlackListAddResponse, error) {
-	err := s.app.BlackListAdd(req.IpNet)
+func (s Service) BlackListAdd(ctx context.Context, req *proto.BlackListAddRequest) (*proto.BlackListAddResponse, error) { //nolint:lll
+	err := s.app.BlackListAdd(auditActor(ctx), req.IpNet, addOptions(req))
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed adding to black list: %s", err))
 
-		return nil, status.Errorf(codes.Unknown, "%s", err.Error())
+		return nil, ruleAddError(err)
 	}
 
 	return &proto.BlackListAddResponse{}, nil
 }
 
-func (s Service) BlackListDelete(_ context.Context, req *proto.BlackListDeleteRequest) (*proto.BlackListDeleteResponse, error) { //nolint:lll
-	err := s.app.BlackListDelete(req.IpNet)
+func (s Service) BlackListDelete(ctx context.Context, req *proto.BlackListDeleteRequest) (*proto.BlackListDeleteResponse, error) { //nolint:lll
+	err := s.app.BlackListDelete(auditActor(ctx), req.IpNet)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed deleting from black list: %s", err))
 
@@ -80,8 +84,8 @@ func (s Service) BlackListDelete(_ context.Context, req *proto.BlackListDeleteRe
 	return &proto.BlackListDeleteResponse{}, nil
 }
 
-func (s Service) BucketReset(_ context.Context, req *proto.BucketResetRequest) (*proto.BucketResetResponse, error) {
-	err := s.app.LimitReset(req.Ip, req.Login)
+func (s Service) BucketReset(ctx context.Context, req *proto.BucketResetRequest) (*proto.BucketResetResponse, error) {
+	err := s.app.LimitReset(auditActor(ctx), req.Ip, req.Login)
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed resetting limits: %s", err))
 
@@ -94,17 +98,235 @@ func (s Service) BucketReset(_ context.Context, req *proto.BucketResetRequest) (
 }
 
 func (s Service) LimitCheck(_ context.Context, req *proto.LimitCheckRequest) (*proto.LimitCheckResponse, error) {
-	satisfies, err := s.app.LimitCheck(req.Ip, req.Login, req.Password)
+	decision, err := s.app.LimitCheck(req.Ip, req.Login, req.Password)
+
+	return s.checkResponse(decision, err)
+}
+
+func (s Service) Check(_ context.Context, req *proto.CheckRequest) (*proto.LimitCheckResponse, error) {
+	decision, err := s.app.Check(req.Dimensions)
+
+	return s.checkResponse(decision, err)
+}
+
+// checkResponse возвращает ответ LimitCheck и Check по решению или статус ошибки проверки.
+func (s Service) checkResponse(decision limiter.Decision, err error) (*proto.LimitCheckResponse, error) {
 	if err != nil {
 		s.logger.Error(fmt.Sprintf("Failed checking limit: %s", err))
 
 		code := codes.Unknown
-		if errors.Is(err, limiter.ErrIncorrectIdentity) {
+		if errors.Is(err, limiter.ErrIncorrectIdentity) || errors.Is(err, rule.ErrInvalidInputIP) {
 			code = codes.InvalidArgument
 		}
 
 		return nil, status.Errorf(code, "%s", err.Error())
 	}
 
-	return &proto.LimitCheckResponse{Allowed: satisfies}, nil
+	return &proto.LimitCheckResponse{
+		Allowed:    decision.Allowed,
+		Remaining:  toInt32(decision.Remaining),
+		Limit:      toInt32(decision.Limit),
+		RetryAfter: toInt32(int(math.Ceil(decision.RetryAfter.Seconds()))),
+		ResetAfter: toInt32(int(math.Ceil(decision.ResetAfter.Seconds()))),
+		LimitType:  decision.LimitType.String(),
+		Reason:     decisionReasons[decision.Reason],
+		RuleId:     int64(decision.RuleID),
+		RuleIpNet:  decision.RuleIPNet,
+		Shadow:     decision.Shadow,
+		LimitTier:  decision.Tier,
+	}, nil
+}
+
+func (s Service) RuleFind(_ context.Context, req *proto.RuleFindRequest) (*proto.RuleFindResponse, error) {
+	rules, err := s.app.RuleFind(req.IpNet)
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed finding rules: %s", err))
+
+		return nil, status.Errorf(ruleAddErrorCode(err), "%s", err.Error())
+	}
+
+	response := &proto.RuleFindResponse{Rules: make([]*proto.Rule, 0, len(*rules))}
+	for _, r := range *rules {
+		response.Rules = append(response.Rules, toProtoRule(r))
+	}
+
+	return response, nil
+}
+
+func (s Service) WhiteListList(_ context.Context, req *proto.RuleListRequest) (*proto.RuleListResponse, error) {
+	return s.ruleList(rule.WhiteList, req)
+}
+
+func (s Service) BlackListList(_ context.Context, req *proto.RuleListRequest) (*proto.RuleListResponse, error) {
+	return s.ruleList(rule.BlackList, req)
+}
+
+func (s Service) ruleList(listType rule.Type, req *proto.RuleListRequest) (*proto.RuleListResponse, error) {
+	afterID := 0
+	if req.PageToken != "" {
+		var err error
+		afterID, err = strconv.Atoi(req.PageToken)
+		if err != nil || afterID < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid page token %q", req.PageToken)
+		}
+	}
+
+	page, err := s.app.RuleList(rule.ListFilter{
+		RuleType:   listType,
+		ContainsIP: req.ContainsIp,
+		Prefix:     req.Prefix,
+		Reason:     req.Reason,
+		CreatedBy:  req.CreatedBy,
+		Source:     ruleSource(req.Source),
+		AfterID:    afterID,
+		Limit:      int(req.PageSize),
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed listing rules: %s", err))
+
+		return nil, status.Errorf(ruleAddErrorCode(err), "%s", err.Error())
+	}
+
+	response := &proto.RuleListResponse{Rules: make([]*proto.Rule, 0, len(page.Rules))}
+	for _, r := range page.Rules {
+		response.Rules = append(response.Rules, toProtoRule(r))
+	}
+	if page.NextAfterID > 0 {
+		response.NextPageToken = strconv.Itoa(page.NextAfterID)
+	}
+
+	return response, nil
+}
+
+func (s Service) ShadowModeSet(ctx context.Context, req *proto.ShadowModeSetRequest) (*proto.ShadowModeSetResponse, error) { //nolint:lll
+	s.app.ShadowModeSet(auditActor(ctx), req.Enabled, req.LimitTypes)
+
+	return &proto.ShadowModeSetResponse{}, nil
+}
+
+var decisionReasons = map[limiter.Reason]proto.DecisionReason{
+	limiter.ReasonPassed:    proto.DecisionReason_DECISION_REASON_PASSED,
+	limiter.ReasonRateLimit: proto.DecisionReason_DECISION_REASON_RATE_LIMIT,
+	limiter.ReasonBlackList: proto.DecisionReason_DECISION_REASON_BLACKLIST,
+	limiter.ReasonWhiteList: proto.DecisionReason_DECISION_REASON_WHITELIST,
+}
+
+var listTypes = map[rule.Type]proto.ListType{
+	rule.WhiteList: proto.ListType_LIST_TYPE_WHITELIST,
+	rule.BlackList: proto.ListType_LIST_TYPE_BLACKLIST,
+}
+
+var ruleSources = map[rule.Source]proto.RuleSource{
+	rule.SourceManual:  proto.RuleSource_RULE_SOURCE_MANUAL,
+	rule.SourceCLI:     proto.RuleSource_RULE_SOURCE_CLI,
+	rule.SourceAutoBan: proto.RuleSource_RULE_SOURCE_AUTO_BAN,
+	rule.SourceImport:  proto.RuleSource_RULE_SOURCE_IMPORT,
+	rule.SourceFeed:    proto.RuleSource_RULE_SOURCE_FEED,
+}
+
+// ruleAddRequest общая часть запросов WhiteListAdd и BlackListAdd.
+type ruleAddRequest interface {
+	GetExpiresAt() *timestamppb.Timestamp
+	GetReason() string
+	GetCreatedBy() string
+	GetSource() proto.RuleSource
+	GetForce() bool
+}
+
+// addOptions собирает параметры добавляемого правила.
+func addOptions(req ruleAddRequest) rule.AddOptions {
+	return rule.AddOptions{
+		ExpiresAt: expiresAt(req.GetExpiresAt()),
+		Reason:    req.GetReason(),
+		CreatedBy: req.GetCreatedBy(),
+		Source:    ruleSource(req.GetSource()),
+		Force:     req.GetForce(),
+	}
+}
+
+// ruleSource возвращает источник правила или пустую строку для RULE_SOURCE_UNSPECIFIED.
+func ruleSource(source proto.RuleSource) rule.Source {
+	for ruleSource, protoSource := range ruleSources {
+		if protoSource == source {
+			return ruleSource
+		}
+	}
+
+	return ""
+}
+
+func toProtoRule(r rule.Rule) *proto.Rule {
+	protoRule := &proto.Rule{
+		Id:        int64(r.ID),
+		IpNet:     r.IP,
+		ListType:  listTypes[r.RuleType],
+		Reason:    r.Reason,
+		CreatedBy: r.CreatedBy,
+		CreatedAt: timestamppb.New(r.CreatedAt),
+		Source:    ruleSources[r.Source],
+		Feed:      r.Feed,
+	}
+
+	if r.ExpiresAt != nil {
+		protoRule.ExpiresAt = timestamppb.New(*r.ExpiresAt)
+	}
+
+	return protoRule
+}
+
+func expiresAt(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+
+	t := timestamp.AsTime()
+
+	return &t
+}
+
+var conflictKinds = map[rule.ConflictKind]proto.RuleConflictKind{
+	rule.ConflictDuplicate: proto.RuleConflictKind_RULE_CONFLICT_KIND_DUPLICATE,
+	rule.ConflictOverlap:   proto.RuleConflictKind_RULE_CONFLICT_KIND_OVERLAP,
+	rule.ConflictSubsumed:  proto.RuleConflictKind_RULE_CONFLICT_KIND_SUBSUMED,
+}
+
+// ruleAddError возвращает статус ошибки добавления правила. Конфликт с существующими правилами -
+// ALREADY_EXISTS для дубликата и FAILED_PRECONDITION для остальных случаев, с конфликтами в деталях.
+func ruleAddError(err error) error {
+	var conflictErr *rule.ConflictError
+	if !errors.As(err, &conflictErr) {
+		return status.Errorf(ruleAddErrorCode(err), "%s", err.Error())
+	}
+
+	code := codes.FailedPrecondition
+	if conflictErr.Duplicate() {
+		code = codes.AlreadyExists
+	}
+
+	details := &proto.RuleConflicts{Conflicts: make([]*proto.RuleConflict, 0, len(conflictErr.Conflicts))}
+	for _, conflict := range conflictErr.Conflicts {
+		details.Conflicts = append(details.Conflicts, &proto.RuleConflict{
+			Kind: conflictKinds[conflict.Kind],
+			Rule: toProtoRule(conflict.Rule),
+		})
+	}
+
+	st, detailsErr := status.New(code, err.Error()).WithDetails(details)
+	if detailsErr != nil {
+		return status.Errorf(code, "%s", err.Error())
+	}
+
+	return st.Err()
+}
+
+func ruleAddErrorCode(err error) codes.Code {
+	if errors.Is(err, rule.ErrInvalidInputIP) || errors.Is(err, rule.ErrRuleExpired) {
+		return codes.InvalidArgument
+	}
+
+	return codes.Unknown
+}
+
+func toInt32(value int) int32 {
+	return int32(min(max(value, math.MinInt32), math.MaxInt32)) //nolint:gosec // значение ограничено диапазоном int32
 }
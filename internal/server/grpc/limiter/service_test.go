@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
 	mocks "github.com/rainb0w-clwn/go_auth_limiter/internal/interfaces/mocks"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/limiter"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
@@ -14,6 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 //nolint:dupl
@@ -24,7 +27,7 @@ func TestService_WhiteListAdd(t *testing.T) {
 	s := grpclimiter.NewService(app, logger)
 
 	// успешный вызов
-	app.On("WhiteListAdd", "1.2.3.4").Return(nil)
+	app.On("WhiteListAdd", audit.Actor{}, "1.2.3.4", rule.AddOptions{}).Return(nil)
 	resp, err := s.WhiteListAdd(ctx, &proto.WhiteListAddRequest{IpNet: "1.2.3.4"})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -33,7 +36,7 @@ func TestService_WhiteListAdd(t *testing.T) {
 
 	// вызов с ошибкой
 	testErr := errors.New("some error")
-	app.On("WhiteListAdd", "5.6.7.8").Return(testErr)
+	app.On("WhiteListAdd", audit.Actor{}, "5.6.7.8", rule.AddOptions{}).Return(testErr)
 	logger.On("Error", mock.Anything).Return()
 
 	resp, err = s.WhiteListAdd(ctx, &proto.WhiteListAddRequest{IpNet: "5.6.7.8"})
@@ -47,6 +50,101 @@ func TestService_WhiteListAdd(t *testing.T) {
 	logger.AssertExpectations(t)
 }
 
+func TestService_BlackListAdd_ExpiresAt(t *testing.T) {
+	ctx := context.Background()
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	expiresAt := time.Now().Add(6 * time.Hour).UTC()
+	app.On("BlackListAdd", audit.Actor{}, "10.0.0.0/24", mock.MatchedBy(func(options rule.AddOptions) bool {
+		return options.ExpiresAt != nil && options.ExpiresAt.Equal(expiresAt)
+	})).Return(nil)
+	resp, err := s.BlackListAdd(ctx, &proto.BlackListAddRequest{
+		IpNet:     "10.0.0.0/24",
+		ExpiresAt: timestamppb.New(expiresAt),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	// истекшее правило - некорректный аргумент
+	app.On("BlackListAdd", audit.Actor{}, "10.0.1.0/24", mock.Anything).Return(rule.ErrRuleExpired)
+	logger.On("Error", mock.Anything).Return()
+	resp, err = s.BlackListAdd(ctx, &proto.BlackListAddRequest{IpNet: "10.0.1.0/24"})
+	require.Nil(t, resp)
+	st, _ := status.FromError(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	app.AssertExpectations(t)
+}
+
+func TestService_BlackListAdd_Metadata(t *testing.T) {
+	ctx := context.Background()
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	app.On("BlackListAdd", audit.Actor{}, "10.0.0.0/24", rule.AddOptions{
+		Reason:    "credential stuffing",
+		CreatedBy: "soc",
+		Source:    rule.SourceCLI,
+	}).Return(nil)
+	resp, err := s.BlackListAdd(ctx, &proto.BlackListAddRequest{
+		IpNet:     "10.0.0.0/24",
+		Reason:    "credential stuffing",
+		CreatedBy: "soc",
+		Source:    proto.RuleSource_RULE_SOURCE_CLI,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	app.AssertExpectations(t)
+}
+
+func TestService_RuleFind(t *testing.T) {
+	ctx := context.Background()
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	createdAt := time.Date(2025, 12, 27, 10, 0, 0, 0, time.UTC)
+	expiresAt := createdAt.Add(time.Hour)
+	app.On("RuleFind", "10.0.0.0/24").Return(&rule.Rules{
+		{
+			ID:        7,
+			IP:        "10.0.0.0/24",
+			RuleType:  rule.BlackList,
+			ExpiresAt: &expiresAt,
+			Reason:    "brute force",
+			CreatedBy: "soc",
+			CreatedAt: createdAt,
+			Source:    rule.SourceAutoBan,
+		},
+	}, nil)
+
+	resp, err := s.RuleFind(ctx, &proto.RuleFindRequest{IpNet: "10.0.0.0/24"})
+	require.NoError(t, err)
+	require.Len(t, resp.Rules, 1)
+
+	found := resp.Rules[0]
+	require.Equal(t, int64(7), found.Id)
+	require.Equal(t, proto.ListType_LIST_TYPE_BLACKLIST, found.ListType)
+	require.Equal(t, "brute force", found.Reason)
+	require.Equal(t, "soc", found.CreatedBy)
+	require.Equal(t, createdAt, found.CreatedAt.AsTime())
+	require.Equal(t, expiresAt, found.ExpiresAt.AsTime())
+	require.Equal(t, proto.RuleSource_RULE_SOURCE_AUTO_BAN, found.Source)
+
+	// некорректная подсеть
+	app.On("RuleFind", "bad").Return(nil, rule.ErrInvalidInputIP)
+	logger.On("Error", mock.Anything).Return()
+	_, err = s.RuleFind(ctx, &proto.RuleFindRequest{IpNet: "bad"})
+	st, _ := status.FromError(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	app.AssertExpectations(t)
+}
+
 func TestService_WhiteListDelete(t *testing.T) {
 	ctx := context.Background()
 	app := new(mocks.MockApplication)
@@ -54,13 +152,13 @@ func TestService_WhiteListDelete(t *testing.T) {
 	s := grpclimiter.NewService(app, logger)
 
 	// успешное удаление
-	app.On("WhiteListDelete", "1.2.3.4").Return(nil)
+	app.On("WhiteListDelete", audit.Actor{}, "1.2.3.4").Return(nil)
 	resp, err := s.WhiteListDelete(ctx, &proto.WhiteListDeleteRequest{IpNet: "1.2.3.4"})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 
 	// удаление несуществующего правила
-	app.On("WhiteListDelete", "5.6.7.8").Return(rule.ErrRuleNotFound)
+	app.On("WhiteListDelete", audit.Actor{}, "5.6.7.8").Return(rule.ErrRuleNotFound)
 	logger.On("Error", mock.Anything).Return()
 
 	resp, err = s.WhiteListDelete(ctx, &proto.WhiteListDeleteRequest{IpNet: "5.6.7.8"})
@@ -82,7 +180,7 @@ func TestService_BlackListAdd(t *testing.T) {
 	s := grpclimiter.NewService(app, logger)
 
 	// успешный вызов
-	app.On("BlackListAdd", "1.2.3.4").Return(nil)
+	app.On("BlackListAdd", audit.Actor{}, "1.2.3.4", rule.AddOptions{}).Return(nil)
 	resp, err := s.BlackListAdd(ctx, &proto.BlackListAddRequest{IpNet: "1.2.3.4"})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -91,7 +189,7 @@ func TestService_BlackListAdd(t *testing.T) {
 
 	// вызов с ошибкой
 	testErr := errors.New("blacklist error")
-	app.On("BlackListAdd", "5.6.7.8").Return(testErr)
+	app.On("BlackListAdd", audit.Actor{}, "5.6.7.8", rule.AddOptions{}).Return(testErr)
 	logger.On("Error", mock.Anything).Return()
 
 	resp, err = s.BlackListAdd(ctx, &proto.BlackListAddRequest{IpNet: "5.6.7.8"})
@@ -112,13 +210,13 @@ func TestService_BlackListDelete(t *testing.T) {
 	s := grpclimiter.NewService(app, logger)
 
 	// успешное удаление
-	app.On("BlackListDelete", "1.2.3.4").Return(nil)
+	app.On("BlackListDelete", audit.Actor{}, "1.2.3.4").Return(nil)
 	resp, err := s.BlackListDelete(ctx, &proto.BlackListDeleteRequest{IpNet: "1.2.3.4"})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 
 	// удаление несуществующего правила
-	app.On("BlackListDelete", "5.6.7.8").Return(rule.ErrRuleNotFound)
+	app.On("BlackListDelete", audit.Actor{}, "5.6.7.8").Return(rule.ErrRuleNotFound)
 	logger.On("Error", mock.Anything).Return()
 
 	resp, err = s.BlackListDelete(ctx, &proto.BlackListDeleteRequest{IpNet: "5.6.7.8"})
@@ -139,16 +237,64 @@ func TestService_LimitCheck(t *testing.T) {
 	s := grpclimiter.NewService(app, logger)
 
 	// успешная проверка лимита
-	app.On("LimitCheck", "1.2.3.4", "user", "pass").Return(true, nil)
+	app.On("LimitCheck", "1.2.3.4", "user", "pass").Return(limiter.Decision{Allowed: true, Remaining: 2, Limit: 10}, nil)
 	resp, err := s.LimitCheck(ctx, &proto.LimitCheckRequest{Ip: "1.2.3.4", Login: "user", Password: "pass"})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
 	require.True(t, resp.Allowed)
+	require.Equal(t, int32(2), resp.Remaining)
+	require.Equal(t, int32(10), resp.Limit)
+	require.Zero(t, resp.RetryAfter)
 	app.AssertExpectations(t)
 	logger.AssertExpectations(t)
 
+	// запрос отклонен лимитом
+	app.On("LimitCheck", "1.2.3.4", "user", "secret").Return(limiter.Decision{
+		Allowed:    false,
+		Limit:      10,
+		Reason:     limiter.ReasonRateLimit,
+		RetryAfter: 1500 * time.Millisecond,
+		LimitType:  limiter.LoginLimit,
+		Tier:       "day",
+	}, nil)
+	resp, err = s.LimitCheck(ctx, &proto.LimitCheckRequest{Ip: "1.2.3.4", Login: "user", Password: "secret"})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+	require.Zero(t, resp.Remaining)
+	require.Equal(t, int32(2), resp.RetryAfter)
+	require.Equal(t, "login", resp.LimitType)
+	require.Equal(t, "day", resp.LimitTier)
+	require.Equal(t, proto.DecisionReason_DECISION_REASON_RATE_LIMIT, resp.Reason)
+
+	// IP в черном списке
+	app.On("LimitCheck", "6.6.6.6", "user", "pass").Return(limiter.Decision{
+		Allowed:   false,
+		Reason:    limiter.ReasonBlackList,
+		RuleID:    7,
+		RuleIPNet: "6.6.6.0/24",
+	}, nil)
+	resp, err = s.LimitCheck(ctx, &proto.LimitCheckRequest{Ip: "6.6.6.6", Login: "user", Password: "pass"})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+	require.Equal(t, proto.DecisionReason_DECISION_REASON_BLACKLIST, resp.Reason)
+	require.Equal(t, int64(7), resp.RuleId)
+	require.Equal(t, "6.6.6.0/24", resp.RuleIpNet)
+
+	// превышен лимит в режиме наблюдения
+	app.On("LimitCheck", "1.2.3.4", "shadow", "pass").Return(limiter.Decision{
+		Allowed:   true,
+		Reason:    limiter.ReasonRateLimit,
+		LimitType: limiter.LoginLimit,
+		Shadow:    true,
+	}, nil)
+	resp, err = s.LimitCheck(ctx, &proto.LimitCheckRequest{Ip: "1.2.3.4", Login: "shadow", Password: "pass"})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+	require.True(t, resp.Shadow)
+	require.Equal(t, proto.DecisionReason_DECISION_REASON_RATE_LIMIT, resp.Reason)
+
 	// ошибка неверной идентификации
-	app.On("LimitCheck", "1.2.3.4", "user", "wrongpass").Return(false, limiter.ErrIncorrectIdentity)
+	app.On("LimitCheck", "1.2.3.4", "user", "wrongpass").Return(limiter.Decision{}, limiter.ErrIncorrectIdentity)
 	logger.On("Error", mock.Anything).Return()
 
 	resp, err = s.LimitCheck(ctx, &proto.LimitCheckRequest{Ip: "1.2.3.4", Login: "user", Password: "wrongpass"})
@@ -162,6 +308,40 @@ func TestService_LimitCheck(t *testing.T) {
 	logger.AssertExpectations(t)
 }
 
+func TestService_Check(t *testing.T) {
+	ctx := context.Background()
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	dimensions := map[string]string{"tenant": "acme", "device": "abc"}
+
+	// запрос отклонен лимитом измерения device
+	app.On("Check", limiter.UserIdentityDto(dimensions)).Return(limiter.Decision{
+		Allowed:    false,
+		Limit:      1,
+		Reason:     limiter.ReasonRateLimit,
+		RetryAfter: time.Minute,
+		LimitType:  "device",
+	}, nil).Once()
+	resp, err := s.Check(ctx, &proto.CheckRequest{Dimensions: dimensions})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+	require.Equal(t, "device", resp.LimitType)
+	require.Equal(t, int32(60), resp.RetryAfter)
+
+	// измерение без лимита
+	app.On("Check", limiter.UserIdentityDto(dimensions)).Return(limiter.Decision{}, limiter.ErrIncorrectIdentity).Once()
+	logger.On("Error", mock.Anything).Return()
+
+	resp, err = s.Check(ctx, &proto.CheckRequest{Dimensions: dimensions})
+	require.Nil(t, resp)
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	app.AssertExpectations(t)
+	logger.AssertExpectations(t)
+}
+
 func TestService_BucketReset(t *testing.T) {
 	ctx := context.Background()
 	app := new(mocks.MockApplication)
@@ -169,7 +349,7 @@ func TestService_BucketReset(t *testing.T) {
 	s := grpclimiter.NewService(app, logger)
 
 	// успешный сброс
-	app.On("LimitReset", "1.2.3.4", "user").Return(nil)
+	app.On("LimitReset", audit.Actor{}, "1.2.3.4", "user").Return(nil)
 	resp, err := s.BucketReset(ctx, &proto.BucketResetRequest{Ip: "1.2.3.4", Login: "user"})
 	require.NoError(t, err)
 	require.NotNil(t, resp)
@@ -178,7 +358,7 @@ func TestService_BucketReset(t *testing.T) {
 
 	// ошибка при сбросе
 	testErr := errors.New("reset error")
-	app.On("LimitReset", "5.6.7.8", "other").Return(testErr)
+	app.On("LimitReset", audit.Actor{}, "5.6.7.8", "other").Return(testErr)
 	logger.On("Error", mock.Anything).Return()
 
 	resp, err = s.BucketReset(ctx, &proto.BucketResetRequest{Ip: "5.6.7.8", Login: "other"})
@@ -191,3 +371,100 @@ func TestService_BucketReset(t *testing.T) {
 	app.AssertExpectations(t)
 	logger.AssertExpectations(t)
 }
+
+func TestService_ShadowModeSet(t *testing.T) {
+	ctx := context.Background()
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	app.On("ShadowModeSet", audit.Actor{}, false, []string{"login"}).Return()
+	resp, err := s.ShadowModeSet(ctx, &proto.ShadowModeSetRequest{Enabled: false, LimitTypes: []string{"login"}})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	app.AssertExpectations(t)
+	logger.AssertExpectations(t)
+}
+
+func TestService_BlackListList(t *testing.T) {
+	ctx := context.Background()
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	app.On("RuleList", rule.ListFilter{
+		RuleType:   rule.BlackList,
+		ContainsIP: "10.1.2.3",
+		Reason:     "brute",
+		Source:     rule.SourceAutoBan,
+		AfterID:    10,
+		Limit:      2,
+	}).Return(&rule.Page{
+		Rules:       rule.Rules{{ID: 11, IP: "10.0.0.0/8", RuleType: rule.BlackList}, {ID: 12, IP: "10.1.0.0/16"}},
+		NextAfterID: 12,
+	}, nil)
+
+	resp, err := s.BlackListList(ctx, &proto.RuleListRequest{
+		PageSize:   2,
+		PageToken:  "10",
+		ContainsIp: "10.1.2.3",
+		Reason:     "brute",
+		Source:     proto.RuleSource_RULE_SOURCE_AUTO_BAN,
+	})
+	require.NoError(t, err)
+	require.Len(t, resp.Rules, 2)
+	require.Equal(t, "10.0.0.0/8", resp.Rules[0].IpNet)
+	require.Equal(t, "12", resp.NextPageToken)
+
+	// последняя страница без токена
+	app.On("RuleList", rule.ListFilter{RuleType: rule.WhiteList}).Return(&rule.Page{}, nil)
+	resp, err = s.WhiteListList(ctx, &proto.RuleListRequest{})
+	require.NoError(t, err)
+	require.Empty(t, resp.NextPageToken)
+
+	// некорректный токен
+	_, err = s.BlackListList(ctx, &proto.RuleListRequest{PageToken: "abc"})
+	st, _ := status.FromError(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	app.AssertExpectations(t)
+}
+
+func TestService_WhiteListAdd_Conflict(t *testing.T) {
+	ctx := context.Background()
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	logger.On("Error", mock.Anything).Return()
+	s := grpclimiter.NewService(app, logger)
+
+	blackListRule := rule.Rule{ID: 2, IP: "10.0.0.0/8", RuleType: rule.BlackList}
+	app.On("WhiteListAdd", audit.Actor{}, "10.1.0.0/16", rule.AddOptions{}).Return(&rule.ConflictError{
+		Conflicts: []rule.Conflict{{Kind: rule.ConflictOverlap, Rule: blackListRule}},
+	})
+	app.On("WhiteListAdd", audit.Actor{}, "10.1.0.0/16", rule.AddOptions{Force: true}).Return(nil)
+
+	_, err := s.WhiteListAdd(ctx, &proto.WhiteListAddRequest{IpNet: "10.1.0.0/16"})
+	st, _ := status.FromError(err)
+	require.Equal(t, codes.FailedPrecondition, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	details, ok := st.Details()[0].(*proto.RuleConflicts)
+	require.True(t, ok)
+	require.Len(t, details.Conflicts, 1)
+	require.Equal(t, proto.RuleConflictKind_RULE_CONFLICT_KIND_OVERLAP, details.Conflicts[0].Kind)
+	require.Equal(t, "10.0.0.0/8", details.Conflicts[0].Rule.IpNet)
+
+	// дубликат
+	app.On("WhiteListAdd", audit.Actor{}, "10.2.0.0/16", rule.AddOptions{}).Return(&rule.ConflictError{
+		Conflicts: []rule.Conflict{{Kind: rule.ConflictDuplicate, Rule: rule.Rule{ID: 3, IP: "10.2.0.0/16"}}},
+	})
+	_, err = s.WhiteListAdd(ctx, &proto.WhiteListAddRequest{IpNet: "10.2.0.0/16"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	resp, err := s.WhiteListAdd(ctx, &proto.WhiteListAddRequest{IpNet: "10.1.0.0/16", Force: true})
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	app.AssertExpectations(t)
+}
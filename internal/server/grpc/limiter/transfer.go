@@ -0,0 +1,151 @@
+package limiter
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// exportChunkSize размер части файла в ответе ExportRules.
+const exportChunkSize = 64 * 1024
+
+var ruleTypes = map[proto.ListType]rule.Type{
+	proto.ListType_LIST_TYPE_WHITELIST: rule.WhiteList,
+	proto.ListType_LIST_TYPE_BLACKLIST: rule.BlackList,
+}
+
+var ruleFormats = map[proto.RuleFormat]rule.Format{
+	proto.RuleFormat_RULE_FORMAT_UNSPECIFIED: rule.FormatPlain,
+	proto.RuleFormat_RULE_FORMAT_PLAIN:       rule.FormatPlain,
+	proto.RuleFormat_RULE_FORMAT_CSV:         rule.FormatCSV,
+	proto.RuleFormat_RULE_FORMAT_JSON_LINES:  rule.FormatJSONLines,
+}
+
+var importModes = map[proto.ImportMode]rule.ImportMode{
+	proto.ImportMode_IMPORT_MODE_UNSPECIFIED: rule.ImportMerge,
+	proto.ImportMode_IMPORT_MODE_MERGE:       rule.ImportMerge,
+	proto.ImportMode_IMPORT_MODE_REPLACE:     rule.ImportReplace,
+}
+
+// ImportRules передает части файла в приложение по мере получения. Правила применяются,
+// только если файл получен полностью.
+func (s Service) ImportRules(stream proto.AuthLimiter_ImportRulesServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	header := first.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "first message must contain the import header")
+	}
+
+	reader, writer := io.Pipe()
+	defer reader.Close()
+
+	go func() {
+		for {
+			req, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				writer.Close()
+				return
+			}
+			if err != nil {
+				writer.CloseWithError(err)
+				return
+			}
+
+			if req.GetHeader() != nil {
+				writer.CloseWithError(status.Error(codes.InvalidArgument, "import header must be sent once"))
+				return
+			}
+
+			if _, err = writer.Write(req.GetChunk()); err != nil {
+				return
+			}
+		}
+	}()
+
+	summary, err := s.app.RuleImport(auditActor(stream.Context()), bufio.NewReader(reader), rule.ImportOptions{
+		RuleType:  ruleTypes[header.ListType],
+		Format:    ruleFormats[header.Format],
+		Mode:      importModes[header.Mode],
+		Reason:    header.Reason,
+		CreatedBy: header.CreatedBy,
+	})
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed importing rules: %s", err))
+
+		return transferError(err)
+	}
+
+	response := &proto.ImportRulesResponse{
+		Added:   toInt32(summary.Added),
+		Skipped: toInt32(summary.Skipped),
+		Invalid: toInt32(summary.Invalid),
+		Removed: toInt32(summary.Removed),
+		Errors:  make([]*proto.ImportLineError, 0, len(summary.Errors)),
+	}
+	for _, lineErr := range summary.Errors {
+		response.Errors = append(response.Errors, &proto.ImportLineError{
+			Line:    toInt32(lineErr.Line),
+			Message: lineErr.Message,
+		})
+	}
+
+	return stream.SendAndClose(response)
+}
+
+func (s Service) ExportRules(req *proto.ExportRulesRequest, stream proto.AuthLimiter_ExportRulesServer) error {
+	writer := bufio.NewWriterSize(chunkWriter{stream}, exportChunkSize)
+
+	err := s.app.RuleExport(writer, ruleTypes[req.ListType], ruleFormats[req.Format])
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		s.logger.Error(fmt.Sprintf("Failed exporting rules: %s", err))
+
+		return transferError(err)
+	}
+
+	return nil
+}
+
+// chunkWriter отправляет записанные данные частями ответа ExportRules.
+type chunkWriter struct {
+	stream proto.AuthLimiter_ExportRulesServer
+}
+
+func (w chunkWriter) Write(p []byte) (int, error) {
+	// bufio.Writer переиспользует буфер, поэтому часть копируется
+	chunk := make([]byte, len(p))
+	copy(chunk, p)
+
+	if err := w.stream.Send(&proto.ExportRulesResponse{Chunk: chunk}); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func transferError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	code := codes.Unknown
+	if errors.Is(err, rule.ErrUnknownFormat) ||
+		errors.Is(err, rule.ErrUnknownRuleType) ||
+		errors.Is(err, rule.ErrUnknownImportMode) {
+		code = codes.InvalidArgument
+	}
+
+	return status.Errorf(code, "%s", err.Error())
+}
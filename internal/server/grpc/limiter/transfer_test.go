@@ -0,0 +1,143 @@
+package limiter_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/audit"
+	mocks "github.com/rainb0w-clwn/go_auth_limiter/internal/interfaces/mocks"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/rule"
+	grpclimiter "github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type importStream struct {
+	grpc.ServerStream
+	requests []*proto.ImportRulesRequest
+	response *proto.ImportRulesResponse
+}
+
+func (s *importStream) Recv() (*proto.ImportRulesRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+
+	return req, nil
+}
+
+func (s *importStream) SendAndClose(response *proto.ImportRulesResponse) error {
+	s.response = response
+	return nil
+}
+
+func (s *importStream) Context() context.Context {
+	return context.Background()
+}
+
+type exportStream struct {
+	grpc.ServerStream
+	chunks [][]byte
+}
+
+func (s *exportStream) Send(response *proto.ExportRulesResponse) error {
+	s.chunks = append(s.chunks, response.Chunk)
+	return nil
+}
+
+func (s *exportStream) Context() context.Context {
+	return context.Background()
+}
+
+func TestService_ImportRules(t *testing.T) {
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	app.
+		On("RuleImport", audit.Actor{}, mock.Anything, rule.ImportOptions{
+			RuleType:  rule.BlackList,
+			Format:    rule.FormatCSV,
+			Mode:      rule.ImportReplace,
+			Reason:    "feed",
+			CreatedBy: "sync",
+		}).
+		Run(func(args mock.Arguments) {
+			data, err := io.ReadAll(args.Get(1).(io.Reader))
+			require.NoError(t, err)
+			require.Equal(t, "10.0.0.0/8\n192.168.0.0/16\n", string(data))
+		}).
+		Return(&rule.ImportSummary{
+			Added:   2,
+			Skipped: 1,
+			Invalid: 1,
+			Errors:  []rule.LineError{{Line: 3, Message: "incorrect IP passed"}},
+		}, nil)
+
+	stream := &importStream{requests: []*proto.ImportRulesRequest{
+		{Payload: &proto.ImportRulesRequest_Header{Header: &proto.ImportRulesHeader{
+			ListType:  proto.ListType_LIST_TYPE_BLACKLIST,
+			Format:    proto.RuleFormat_RULE_FORMAT_CSV,
+			Mode:      proto.ImportMode_IMPORT_MODE_REPLACE,
+			Reason:    "feed",
+			CreatedBy: "sync",
+		}}},
+		{Payload: &proto.ImportRulesRequest_Chunk{Chunk: []byte("10.0.0.0/8\n192.")}},
+		{Payload: &proto.ImportRulesRequest_Chunk{Chunk: []byte("168.0.0/16\n")}},
+	}}
+
+	require.NoError(t, s.ImportRules(stream))
+	require.Equal(t, int32(2), stream.response.Added)
+	require.Equal(t, int32(1), stream.response.Skipped)
+	require.Equal(t, int32(1), stream.response.Invalid)
+	require.Len(t, stream.response.Errors, 1)
+	require.Equal(t, int32(3), stream.response.Errors[0].Line)
+
+	// заголовок должен быть первым
+	err := s.ImportRules(&importStream{requests: []*proto.ImportRulesRequest{
+		{Payload: &proto.ImportRulesRequest_Chunk{Chunk: []byte("10.0.0.0/8\n")}},
+	}})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	app.AssertExpectations(t)
+}
+
+func TestService_ExportRules(t *testing.T) {
+	app := new(mocks.MockApplication)
+	logger := new(mocks.MockLogger)
+	s := grpclimiter.NewService(app, logger)
+
+	app.
+		On("RuleExport", mock.Anything, rule.WhiteList, rule.FormatJSONLines).
+		Run(func(args mock.Arguments) {
+			_, err := io.WriteString(args.Get(0).(io.Writer), `{"ipNet":"10.0.0.0/8"}`+"\n")
+			require.NoError(t, err)
+		}).
+		Return(nil).
+		Once()
+
+	stream := &exportStream{}
+	require.NoError(t, s.ExportRules(&proto.ExportRulesRequest{
+		ListType: proto.ListType_LIST_TYPE_WHITELIST,
+		Format:   proto.RuleFormat_RULE_FORMAT_JSON_LINES,
+	}, stream))
+	require.Equal(t, [][]byte{[]byte(`{"ipNet":"10.0.0.0/8"}` + "\n")}, stream.chunks)
+
+	// ошибка экспорта
+	app.On("RuleExport", mock.Anything, rule.BlackList, rule.FormatPlain).Return(errors.New("db is down")).Once()
+	logger.On("Error", mock.Anything).Return()
+
+	err := s.ExportRules(&proto.ExportRulesRequest{ListType: proto.ListType_LIST_TYPE_BLACKLIST}, &exportStream{})
+	require.Equal(t, codes.Unknown, status.Code(err))
+
+	app.AssertExpectations(t)
+}
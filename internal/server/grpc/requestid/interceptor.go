@@ -20,6 +20,36 @@ func New() grpc.UnaryServerInterceptor {
 	}
 }
 
+// NewStream передает ID запроса в контекст потоковых методов так же, как New.
+func NewStream() grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := metadata.AppendToOutgoingContext(stream.Context(), XRequestIDKey, getRequestID(stream.Context()))
+		return handler(srv, &requestIDStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// FromContext возвращает ID запроса, добавленный интерсептором.
+func FromContext(ctx context.Context) string {
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if !ok {
+		return ""
+	}
+	header := md.Get(XRequestIDKey)
+	if len(header) == 0 {
+		return ""
+	}
+	return header[0]
+}
+
+type requestIDStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *requestIDStream) Context() context.Context {
+	return s.ctx
+}
+
 func getRequestID(ctx context.Context) string {
 	requestID := getStringFromContext(ctx, XRequestIDKey)
 	if requestID == "" {
@@ -9,6 +9,7 @@ import (
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/interfaces"
 	grpclimiter "github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/limiter"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/log"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/metrics"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/requestid"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/grpc/validate"
 	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
@@ -35,9 +36,15 @@ func New(options Options, logger appinterfaces.Logger, app appinterfaces.Applica
 		grpc.ConnectionTimeout(options.ConnectTimeout),
 		grpc.ChainUnaryInterceptor(
 			requestid.New(),
+			metrics.New(),
 			validate.New(),
 			log.New(logger),
 		),
+		grpc.ChainStreamInterceptor(
+			requestid.NewStream(),
+			metrics.NewStream(),
+			validate.NewStream(),
+		),
 	)
 	proto.RegisterAuthLimiterServer(serverGRPC, grpclimiter.NewService(app, logger))
 	return &server{serverGRPC, logger}
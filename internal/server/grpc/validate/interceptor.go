@@ -29,3 +29,37 @@ func New() grpc.UnaryServerInterceptor {
 		return handler(ctx, req)
 	}
 }
+
+// NewStream проверяет каждое сообщение, полученное потоковым методом.
+func NewStream() grpc.StreamServerInterceptor {
+	v, _ := protovalidate.New()
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		_ *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		return handler(srv, &validatingStream{ServerStream: stream, validator: v})
+	}
+}
+
+type validatingStream struct {
+	grpc.ServerStream
+	validator protovalidate.Validator
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	r, ok := m.(proto.Message)
+	if !ok {
+		return status.Error(codes.InvalidArgument, "Invalid message")
+	}
+	if err := s.validator.Validate(r); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
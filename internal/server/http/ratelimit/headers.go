@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+const (
+	RetryAfterKey         = "Retry-After"
+	RateLimitLimitKey     = "RateLimit-Limit"
+	RateLimitRemainingKey = "RateLimit-Remaining"
+	RateLimitResetKey     = "RateLimit-Reset"
+)
+
+// Headers дублирует результат проверки лимита в заголовках ответа Retry-After и RateLimit-*.
+// Для IP из белого списка заголовки RateLimit-* не выставляются.
+// В режиме наблюдения заголовки не выставляются: клиент не должен замечать превышения лимита.
+func Headers(_ context.Context, writer http.ResponseWriter, message protobuf.Message) error {
+	response, ok := message.(*proto.LimitCheckResponse)
+	if !ok || response.Shadow {
+		return nil
+	}
+
+	if response.RetryAfter > 0 {
+		writer.Header().Set(RetryAfterKey, strconv.Itoa(int(response.RetryAfter)))
+	}
+
+	if response.Remaining < 0 {
+		return nil
+	}
+
+	writer.Header().Set(RateLimitLimitKey, strconv.Itoa(int(response.Limit)))
+	writer.Header().Set(RateLimitRemainingKey, strconv.Itoa(int(response.Remaining)))
+	writer.Header().Set(RateLimitResetKey, strconv.Itoa(int(response.ResetAfter)))
+
+	return nil
+}
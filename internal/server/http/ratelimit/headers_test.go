@@ -0,0 +1,67 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/http/ratelimit"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		response *proto.LimitCheckResponse
+		headers  map[string]string
+	}{
+		{
+			name:     "allowed",
+			response: &proto.LimitCheckResponse{Allowed: true, Remaining: 1, Limit: 5, ResetAfter: 240},
+			headers: map[string]string{
+				ratelimit.RateLimitLimitKey:     "5",
+				ratelimit.RateLimitRemainingKey: "1",
+				ratelimit.RateLimitResetKey:     "240",
+			},
+		},
+		{
+			name:     "denied",
+			response: &proto.LimitCheckResponse{Remaining: 0, Limit: 5, RetryAfter: 60, ResetAfter: 300},
+			headers: map[string]string{
+				ratelimit.RetryAfterKey:         "60",
+				ratelimit.RateLimitLimitKey:     "5",
+				ratelimit.RateLimitRemainingKey: "0",
+				ratelimit.RateLimitResetKey:     "300",
+			},
+		},
+		{
+			name:     "whitelisted",
+			response: &proto.LimitCheckResponse{Allowed: true, Remaining: -1},
+			headers:  map[string]string{},
+		},
+		{
+			name:     "shadow",
+			response: &proto.LimitCheckResponse{Allowed: true, Shadow: true, RetryAfter: 60, ResetAfter: 60},
+			headers:  map[string]string{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			require.NoError(t, ratelimit.Headers(context.Background(), recorder, tc.response))
+
+			require.Len(t, recorder.Header(), len(tc.headers))
+			for key, value := range tc.headers {
+				require.Equal(t, value, recorder.Header().Get(key), key)
+			}
+		})
+	}
+
+	t.Run("other messages", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		require.NoError(t, ratelimit.Headers(context.Background(), recorder, &proto.RuleFindResponse{}))
+		require.Empty(t, recorder.Header())
+	})
+}
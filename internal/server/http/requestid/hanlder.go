@@ -2,8 +2,10 @@ package requestid
 
 import (
 	"net/http"
+	"net/textproto"
 
 	"github.com/google/uuid"
+	"github.com/meshapi/grpc-api-gateway/gateway"
 )
 
 const XRequestIDKey = "X-Request-Id"
@@ -13,6 +15,7 @@ func New(next http.Handler) http.HandlerFunc {
 		requestID := request.Header.Get(XRequestIDKey)
 		if requestID == "" {
 			requestID = newRequestID()
+			request.Header.Set(XRequestIDKey, requestID)
 		}
 
 		writer.Header().Set("X-Request-ID", requestID)
@@ -21,6 +24,15 @@ func New(next http.Handler) http.HandlerFunc {
 	}
 }
 
+// HeaderMatcher передает X-Request-Id в метаданные gRPC запроса,
+// остальные заголовки обрабатываются gateway.DefaultHeaderMatcher.
+func HeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == XRequestIDKey {
+		return "x-request-id", true
+	}
+	return gateway.DefaultHeaderMatcher(key)
+}
+
 func newRequestID() string {
 	return uuid.NewString()
 }
@@ -0,0 +1,162 @@
+package rules
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/meshapi/grpc-api-gateway/gateway"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// uploadChunkSize размер части файла в запросе ImportRules.
+const uploadChunkSize = 64 * 1024
+
+var listTypes = map[string]proto.ListType{
+	"white": proto.ListType_LIST_TYPE_WHITELIST,
+	"black": proto.ListType_LIST_TYPE_BLACKLIST,
+}
+
+var formats = map[string]proto.RuleFormat{
+	"":      proto.RuleFormat_RULE_FORMAT_PLAIN,
+	"plain": proto.RuleFormat_RULE_FORMAT_PLAIN,
+	"csv":   proto.RuleFormat_RULE_FORMAT_CSV,
+	"jsonl": proto.RuleFormat_RULE_FORMAT_JSON_LINES,
+}
+
+var contentTypes = map[proto.RuleFormat]string{
+	proto.RuleFormat_RULE_FORMAT_PLAIN:      "text/plain; charset=utf-8",
+	proto.RuleFormat_RULE_FORMAT_CSV:        "text/csv; charset=utf-8",
+	proto.RuleFormat_RULE_FORMAT_JSON_LINES: "application/jsonl",
+}
+
+var modes = map[string]proto.ImportMode{
+	"":        proto.ImportMode_IMPORT_MODE_MERGE,
+	"merge":   proto.ImportMode_IMPORT_MODE_MERGE,
+	"replace": proto.ImportMode_IMPORT_MODE_REPLACE,
+}
+
+// NewImport загружает файл правил из тела запроса через ImportRules.
+// Параметры: listType (white|black), format (plain|csv|jsonl), mode (merge|replace), reason, createdBy.
+func NewImport(mux *gateway.ServeMux, client proto.AuthLimiterClient) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		_, marshaler := mux.MarshalerForRequest(req)
+		ctx, err := gateway.AnnotateContext(
+			req.Context(), mux, req, proto.AuthLimiter_ImportRules_FullMethodName,
+			gateway.WithHTTPPathPattern("/rules/import"),
+		)
+		if err != nil {
+			mux.HTTPError(req.Context(), marshaler, writer, req, err)
+			return
+		}
+		ctx = gateway.NewServerMetadataContext(ctx, gateway.ServerMetadata{})
+
+		query := req.URL.Query()
+		listType, listTypeOK := listTypes[query.Get("listType")]
+		format, formatOK := formats[query.Get("format")]
+		mode, modeOK := modes[query.Get("mode")]
+		if !listTypeOK || !formatOK || !modeOK {
+			mux.HTTPError(ctx, marshaler, writer, req, status.Error(
+				codes.InvalidArgument,
+				"listType must be white or black, format plain, csv or jsonl, mode merge or replace",
+			))
+			return
+		}
+
+		stream, err := client.ImportRules(ctx)
+		if err != nil {
+			mux.HTTPError(ctx, marshaler, writer, req, err)
+			return
+		}
+
+		err = stream.Send(&proto.ImportRulesRequest{Payload: &proto.ImportRulesRequest_Header{
+			Header: &proto.ImportRulesHeader{
+				ListType:  listType,
+				Format:    format,
+				Mode:      mode,
+				Reason:    query.Get("reason"),
+				CreatedBy: query.Get("createdBy"),
+			},
+		}})
+
+		buf := make([]byte, uploadChunkSize)
+		for err == nil {
+			var n int
+			n, err = io.ReadFull(req.Body, buf)
+			if n > 0 {
+				chunk := make([]byte, n)
+				copy(chunk, buf[:n])
+				if sendErr := stream.Send(&proto.ImportRulesRequest{
+					Payload: &proto.ImportRulesRequest_Chunk{Chunk: chunk},
+				}); sendErr != nil {
+					err = sendErr
+				}
+			}
+		}
+		// при ошибке отправки причина возвращается в CloseAndRecv
+		if !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			if _, ok := status.FromError(err); !ok {
+				mux.HTTPError(ctx, marshaler, writer, req, status.Error(codes.InvalidArgument, err.Error()))
+				return
+			}
+		}
+
+		response, err := stream.CloseAndRecv()
+		if err != nil {
+			mux.HTTPError(ctx, marshaler, writer, req, err)
+			return
+		}
+
+		mux.ForwardResponseMessage(ctx, marshaler, writer, req, response)
+	}
+}
+
+// NewExport выгружает действующие правила списка через ExportRules.
+// Параметры: listType (white|black), format (plain|csv|jsonl).
+func NewExport(mux *gateway.ServeMux, client proto.AuthLimiterClient) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		_, marshaler := mux.MarshalerForRequest(req)
+		ctx, err := gateway.AnnotateContext(
+			req.Context(), mux, req, proto.AuthLimiter_ExportRules_FullMethodName,
+			gateway.WithHTTPPathPattern("/rules/export"),
+		)
+		if err != nil {
+			mux.HTTPError(req.Context(), marshaler, writer, req, err)
+			return
+		}
+
+		query := req.URL.Query()
+		listType, listTypeOK := listTypes[query.Get("listType")]
+		format, formatOK := formats[query.Get("format")]
+		if !listTypeOK || !formatOK {
+			mux.HTTPError(ctx, marshaler, writer, req, status.Error(
+				codes.InvalidArgument,
+				"listType must be white or black, format plain, csv or jsonl",
+			))
+			return
+		}
+
+		stream, err := client.ExportRules(ctx, &proto.ExportRulesRequest{ListType: listType, Format: format})
+		if err != nil {
+			mux.HTTPError(ctx, marshaler, writer, req, err)
+			return
+		}
+
+		// первая часть получается до записи заголовков, чтобы вернуть ошибку экспорта статусом ответа
+		response, err := stream.Recv()
+		if err != nil && !errors.Is(err, io.EOF) {
+			mux.HTTPError(ctx, marshaler, writer, req, err)
+			return
+		}
+
+		writer.Header().Set("Content-Type", contentTypes[format])
+		for err == nil {
+			if _, err = writer.Write(response.Chunk); err != nil {
+				return
+			}
+			response, err = stream.Recv()
+		}
+	}
+}
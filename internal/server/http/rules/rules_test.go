@@ -0,0 +1,158 @@
+package rules_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/meshapi/grpc-api-gateway/gateway"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/http/requestid"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/http/rules"
+	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type client struct {
+	proto.AuthLimiterClient
+	ctx      context.Context
+	imported *importStream
+	exported *exportStream
+}
+
+func (c *client) ImportRules(
+	ctx context.Context, _ ...grpc.CallOption,
+) (grpc.ClientStreamingClient[proto.ImportRulesRequest, proto.ImportRulesResponse], error) {
+	c.ctx = ctx
+	c.imported = &importStream{}
+	return c.imported, nil
+}
+
+func (c *client) ExportRules(
+	ctx context.Context, in *proto.ExportRulesRequest, _ ...grpc.CallOption,
+) (grpc.ServerStreamingClient[proto.ExportRulesResponse], error) {
+	c.ctx = ctx
+	c.exported.request = in
+	return c.exported, nil
+}
+
+type importStream struct {
+	grpc.ClientStream
+	requests []*proto.ImportRulesRequest
+}
+
+func (s *importStream) Send(req *proto.ImportRulesRequest) error {
+	s.requests = append(s.requests, req)
+	return nil
+}
+
+func (s *importStream) CloseAndRecv() (*proto.ImportRulesResponse, error) {
+	return &proto.ImportRulesResponse{Added: int32(len(s.requests) - 1)}, nil
+}
+
+type exportStream struct {
+	grpc.ClientStream
+	request *proto.ExportRulesRequest
+	chunks  [][]byte
+}
+
+func (s *exportStream) Recv() (*proto.ExportRulesResponse, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+
+	return &proto.ExportRulesResponse{Chunk: chunk}, nil
+}
+
+func TestImport(t *testing.T) {
+	t.Run("invalid arguments", func(t *testing.T) {
+		mux := gateway.NewServeMux()
+		c := &client{}
+		req := httptest.NewRequest(http.MethodPost, "/rules/import?listType=gray", nil)
+		rec := httptest.NewRecorder()
+
+		rules.NewImport(mux, c).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Nil(t, c.imported)
+	})
+
+	t.Run("streams body in chunks with request metadata", func(t *testing.T) {
+		mux := gateway.NewServeMux(gateway.WithIncomingHeaderMatcher(requestid.HeaderMatcher))
+		c := &client{}
+		body := bytes.Repeat([]byte("10.0.0.0/8\n"), 10000)
+		req := httptest.NewRequest(
+			http.MethodPost, "/rules/import?listType=black&format=plain&mode=replace&reason=feed",
+			bytes.NewReader(body),
+		)
+		req.RemoteAddr = "192.0.2.10:5555"
+		req.Header.Set("Grpc-Metadata-X-Actor", "ops")
+		req.Header.Set(requestid.XRequestIDKey, "req-1")
+		rec := httptest.NewRecorder()
+
+		rules.NewImport(mux, c).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.JSONEq(t, `{"added":2,"skipped":0,"invalid":0,"removed":0,"errors":[]}`, rec.Body.String())
+
+		md, ok := metadata.FromOutgoingContext(c.ctx)
+		require.True(t, ok)
+		require.Equal(t, []string{"ops"}, md.Get("x-actor"))
+		require.Equal(t, []string{"req-1"}, md.Get("x-request-id"))
+		require.Equal(t, []string{"192.0.2.10"}, md.Get("x-forwarded-for"))
+
+		requests := c.imported.requests
+		require.Len(t, requests, 3)
+		require.Equal(t, &proto.ImportRulesHeader{
+			ListType: proto.ListType_LIST_TYPE_BLACKLIST,
+			Format:   proto.RuleFormat_RULE_FORMAT_PLAIN,
+			Mode:     proto.ImportMode_IMPORT_MODE_REPLACE,
+			Reason:   "feed",
+		}, requests[0].GetHeader())
+		require.Len(t, requests[1].GetChunk(), 64*1024)
+		require.Equal(t, body, append(requests[1].GetChunk(), requests[2].GetChunk()...))
+	})
+}
+
+func TestExport(t *testing.T) {
+	t.Run("invalid arguments", func(t *testing.T) {
+		mux := gateway.NewServeMux()
+		c := &client{}
+		req := httptest.NewRequest(http.MethodGet, "/rules/export?listType=white&format=xml", nil)
+		rec := httptest.NewRecorder()
+
+		rules.NewExport(mux, c).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		require.Nil(t, c.ctx)
+	})
+
+	t.Run("writes streamed chunks with request metadata", func(t *testing.T) {
+		mux := gateway.NewServeMux()
+		c := &client{exported: &exportStream{chunks: [][]byte{[]byte("a,b\n"), []byte("c,d\n")}}}
+		req := httptest.NewRequest(http.MethodGet, "/rules/export?listType=white&format=csv", nil)
+		req.RemoteAddr = "192.0.2.10:5555"
+		rec := httptest.NewRecorder()
+
+		rules.NewExport(mux, c).ServeHTTP(rec, req)
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+		require.Equal(t, "a,b\nc,d\n", rec.Body.String())
+		require.Equal(t, &proto.ExportRulesRequest{
+			ListType: proto.ListType_LIST_TYPE_WHITELIST,
+			Format:   proto.RuleFormat_RULE_FORMAT_CSV,
+		}, c.exported.request)
+
+		md, ok := metadata.FromOutgoingContext(c.ctx)
+		require.True(t, ok)
+		require.Equal(t, []string{"192.0.2.10"}, md.Get("x-forwarded-for"))
+	})
+}
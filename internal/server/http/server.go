@@ -7,11 +7,14 @@ import (
 	"time"
 
 	"github.com/meshapi/grpc-api-gateway/gateway"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/config"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/interfaces"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/http/health"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/http/log"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/http/ratelimit"
 	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/http/requestid"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/server/http/rules"
 	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
@@ -60,13 +63,20 @@ func (s *server) Start(ctx context.Context) error {
 		return err
 	}
 
-	mux := gateway.NewServeMux()
+	mux := gateway.NewServeMux(
+		gateway.WithForwardResponseOption(ratelimit.Headers),
+		gateway.WithIncomingHeaderMatcher(requestid.HeaderMatcher),
+	)
 	for _, f := range []func(context.Context, *gateway.ServeMux, *grpc.ClientConn){
 		proto.RegisterAuthLimiterHandler,
 	} {
 		f(ctx, mux, conn)
 	}
 	mux.Handle("GET", "/health", health.New())
+	mux.Handle("GET", "/metrics", promhttp.Handler())
+	client := proto.NewAuthLimiterClient(conn)
+	mux.Handle("POST", "/rules/import", rules.NewImport(mux, client))
+	mux.Handle("GET", "/rules/export", rules.NewExport(mux, client))
 	s.Handler = requestid.New(log.New(s.logger, mux))
 
 	err = s.ListenAndServe()
@@ -0,0 +1,37 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rainb0w-clwn/go_auth_limiter/internal/config"
+)
+
+// Listen подписывается на канал уведомлений PostgreSQL (LISTEN) и вызывает handler на каждое уведомление.
+// Для подписки открывается отдельное соединение. Возвращает ошибку при разрыве соединения или отмене ctx.
+func (s *Storage) Listen(ctx context.Context, channel string, handler func(payload string)) error {
+	cfg := config.GetFromContext(ctx)
+	if cfg == nil {
+		return config.ErrNoConfigInContext
+	}
+
+	conn, err := pgx.Connect(ctx, cfg.DB.DSN)
+	if err != nil {
+		return fmt.Errorf(ErrConnectFailed.Error()+":%w", err)
+	}
+	defer conn.Close(context.WithoutCancel(ctx))
+
+	if _, err = conn.Exec(ctx, "listen "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		handler(notification.Payload)
+	}
+}
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+create table bucket_snapshot
+(
+    limit_type  varchar(50) not null,
+    bucket_key  text        not null,
+    tokens      int         not null,
+    last_refill timestamptz not null,
+    primary key (limit_type, bucket_key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS bucket_snapshot;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table rate_limit
+    add column refill_count int         null,
+    add column refill_time  varchar(50) null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table rate_limit
+    drop column if exists refill_count,
+    drop column if exists refill_time;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+create or replace function notify_rate_limit_changed() returns trigger as
+$$
+begin
+    perform pg_notify('rate_limit_changed', tg_op);
+    return null;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger rate_limit_changed
+    after insert or update or delete or truncate
+    on rate_limit
+    for each statement
+execute function notify_rate_limit_changed();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop trigger if exists rate_limit_changed on rate_limit;
+drop function if exists notify_rate_limit_changed();
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table rate_limit
+    add column algorithm varchar(50) not null default 'token_bucket';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table rate_limit
+    drop column if exists algorithm;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+create or replace function notify_ip_net_rule_changed() returns trigger as
+$$
+begin
+    perform pg_notify('ip_net_rule_changed', tg_op);
+    return null;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create trigger ip_net_rule_changed
+    after insert or update or delete or truncate
+    on ip_net_rule
+    for each statement
+execute function notify_ip_net_rule_changed();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop trigger if exists ip_net_rule_changed on ip_net_rule;
+drop function if exists notify_ip_net_rule_changed();
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Каноничная IPv6 сеть с префиксом занимает до 43 символов, полная форма с IPv4 (0:0:0:0:0:ffff:1.2.3.4/128) - до 49.
+alter table ip_net_rule
+    alter column ip type varchar(64);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table ip_net_rule
+    alter column ip type varchar(50);
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table ip_net_rule
+    add column expires_at timestamptz null;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+create index ip_net_rule_expires_at_idx on ip_net_rule (expires_at) where expires_at is not null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index if exists ip_net_rule_expires_at_idx;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+alter table ip_net_rule
+    drop column if exists expires_at;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table ip_net_rule
+    add column reason     varchar(255) not null default '',
+    add column created_by varchar(255) not null default '',
+    add column created_at timestamptz  not null default now(),
+    add column source     varchar(20)  not null default 'manual';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table ip_net_rule
+    drop column if exists reason,
+    drop column if exists created_by,
+    drop column if exists created_at,
+    drop column if exists source;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table ip_net_rule
+    add column feed varchar(255) not null default '';
+-- +goose StatementEnd
+-- +goose StatementBegin
+create index ip_net_rule_feed_idx on ip_net_rule (feed) where feed <> '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index if exists ip_net_rule_feed_idx;
+-- +goose StatementEnd
+-- +goose StatementBegin
+alter table ip_net_rule
+    drop column if exists feed;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+create table audit_log
+(
+    id         bigint generated always as identity primary key,
+    action     varchar(50)  not null,
+    actor      varchar(255) not null default '',
+    request_id varchar(255) not null default '',
+    target     varchar(255) not null default '',
+    before     jsonb        null,
+    after      jsonb        null,
+    created_at timestamptz  not null default now()
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+create index audit_log_target_idx on audit_log (target);
+-- +goose StatementEnd
+-- +goose StatementBegin
+create or replace function audit_log_append_only() returns trigger as
+$$
+begin
+    raise exception 'audit_log is append-only';
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+-- +goose StatementBegin
+create trigger audit_log_append_only
+    before update or delete or truncate
+    on audit_log
+    for each statement
+execute function audit_log_append_only();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop trigger if exists audit_log_append_only on audit_log;
+-- +goose StatementEnd
+-- +goose StatementBegin
+drop function if exists audit_log_append_only();
+-- +goose StatementEnd
+-- +goose StatementBegin
+drop table if exists audit_log;
+-- +goose StatementEnd
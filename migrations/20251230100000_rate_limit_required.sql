@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table rate_limit
+    add column required boolean not null default false;
+-- +goose StatementEnd
+-- +goose StatementBegin
+update rate_limit
+set required = true
+where type in ('login', 'password', 'ip');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table rate_limit
+    drop column if exists required;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+insert into rate_limit(type, value, description)
+values ('device', 50, 'Ограничение для отпечатка устройства'),
+       ('user-agent', 1000, 'Ограничение для хеша User-Agent'),
+       ('tenant', 10000, 'Ограничение для арендатора'),
+       ('asn', 10000, 'Ограничение для автономной системы')
+on conflict (type) do nothing;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+delete
+from rate_limit
+where (type, value, description, required) in (('device', 50, 'Ограничение для отпечатка устройства', false),
+                                               ('user-agent', 1000, 'Ограничение для хеша User-Agent', false),
+                                               ('tenant', 10000, 'Ограничение для арендатора', false),
+                                               ('asn', 10000, 'Ограничение для автономной системы', false));
+-- +goose StatementEnd
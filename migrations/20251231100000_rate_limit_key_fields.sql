@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Поля identity через запятую, из значений которых составляется ключ bucket'а (например, 'login,ip').
+alter table rate_limit
+    add column key_fields varchar(255) null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table rate_limit
+    drop column if exists key_fields;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Дополнительные уровни лимита: [{"name": "day", "value": 50, "refill_count": 50, "refill_time": "24h"}].
+alter table rate_limit
+    add column tiers jsonb null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table rate_limit
+    drop column if exists tiers;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table rate_limit
+    add constraint rate_limit_type_check check (type <> '' and type !~ '[_:]');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table rate_limit
+    drop constraint if exists rate_limit_type_check;
+-- +goose StatementEnd
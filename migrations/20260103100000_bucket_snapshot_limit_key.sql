@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table bucket_snapshot
+    alter column limit_type type text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+delete
+from bucket_snapshot
+where length(limit_type) > 50;
+-- +goose StatementEnd
+-- +goose StatementBegin
+alter table bucket_snapshot
+    alter column limit_type type varchar(50);
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table audit_log
+    add column peer varchar(255) not null default '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table audit_log
+    drop column if exists peer;
+-- +goose StatementEnd
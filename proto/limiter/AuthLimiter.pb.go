@@ -11,6 +11,7 @@ import (
 	_ "github.com/meshapi/grpc-api-gateway/api"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -23,9 +24,351 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type DecisionReason int32
+
+const (
+	DecisionReason_DECISION_REASON_UNSPECIFIED DecisionReason = 0
+	// Attempt passed all rate limits.
+	DecisionReason_DECISION_REASON_PASSED DecisionReason = 1
+	// Attempt denied by a rate limit, see limit_type.
+	DecisionReason_DECISION_REASON_RATE_LIMIT DecisionReason = 2
+	// Attempt denied: IP is blacklisted.
+	DecisionReason_DECISION_REASON_BLACKLIST DecisionReason = 3
+	// Attempt allowed without rate limiting: IP is whitelisted.
+	DecisionReason_DECISION_REASON_WHITELIST DecisionReason = 4
+)
+
+// Enum value maps for DecisionReason.
+var (
+	DecisionReason_name = map[int32]string{
+		0: "DECISION_REASON_UNSPECIFIED",
+		1: "DECISION_REASON_PASSED",
+		2: "DECISION_REASON_RATE_LIMIT",
+		3: "DECISION_REASON_BLACKLIST",
+		4: "DECISION_REASON_WHITELIST",
+	}
+	DecisionReason_value = map[string]int32{
+		"DECISION_REASON_UNSPECIFIED": 0,
+		"DECISION_REASON_PASSED":      1,
+		"DECISION_REASON_RATE_LIMIT":  2,
+		"DECISION_REASON_BLACKLIST":   3,
+		"DECISION_REASON_WHITELIST":   4,
+	}
+)
+
+func (x DecisionReason) Enum() *DecisionReason {
+	p := new(DecisionReason)
+	*p = x
+	return p
+}
+
+func (x DecisionReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DecisionReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_limiter_AuthLimiter_proto_enumTypes[0].Descriptor()
+}
+
+func (DecisionReason) Type() protoreflect.EnumType {
+	return &file_proto_limiter_AuthLimiter_proto_enumTypes[0]
+}
+
+func (x DecisionReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DecisionReason.Descriptor instead.
+func (DecisionReason) EnumDescriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{0}
+}
+
+type RuleConflictKind int32
+
+const (
+	RuleConflictKind_RULE_CONFLICT_KIND_UNSPECIFIED RuleConflictKind = 0
+	// Same network is already in the list.
+	RuleConflictKind_RULE_CONFLICT_KIND_DUPLICATE RuleConflictKind = 1
+	// Network overlaps a rule of the opposite list.
+	RuleConflictKind_RULE_CONFLICT_KIND_OVERLAP RuleConflictKind = 2
+	// Network lies within a broader rule of the same list.
+	RuleConflictKind_RULE_CONFLICT_KIND_SUBSUMED RuleConflictKind = 3
+)
+
+// Enum value maps for RuleConflictKind.
+var (
+	RuleConflictKind_name = map[int32]string{
+		0: "RULE_CONFLICT_KIND_UNSPECIFIED",
+		1: "RULE_CONFLICT_KIND_DUPLICATE",
+		2: "RULE_CONFLICT_KIND_OVERLAP",
+		3: "RULE_CONFLICT_KIND_SUBSUMED",
+	}
+	RuleConflictKind_value = map[string]int32{
+		"RULE_CONFLICT_KIND_UNSPECIFIED": 0,
+		"RULE_CONFLICT_KIND_DUPLICATE":   1,
+		"RULE_CONFLICT_KIND_OVERLAP":     2,
+		"RULE_CONFLICT_KIND_SUBSUMED":    3,
+	}
+)
+
+func (x RuleConflictKind) Enum() *RuleConflictKind {
+	p := new(RuleConflictKind)
+	*p = x
+	return p
+}
+
+func (x RuleConflictKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RuleConflictKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_limiter_AuthLimiter_proto_enumTypes[1].Descriptor()
+}
+
+func (RuleConflictKind) Type() protoreflect.EnumType {
+	return &file_proto_limiter_AuthLimiter_proto_enumTypes[1]
+}
+
+func (x RuleConflictKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RuleConflictKind.Descriptor instead.
+func (RuleConflictKind) EnumDescriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{1}
+}
+
+type RuleFormat int32
+
+const (
+	RuleFormat_RULE_FORMAT_UNSPECIFIED RuleFormat = 0
+	// One network per line, comments after # or ;.
+	RuleFormat_RULE_FORMAT_PLAIN RuleFormat = 1
+	// Columns ip_net, reason, expires_at (RFC 3339); export adds created_by, created_at, source.
+	RuleFormat_RULE_FORMAT_CSV RuleFormat = 2
+	// JSON object per line: ipNet, reason, expiresAt.
+	RuleFormat_RULE_FORMAT_JSON_LINES RuleFormat = 3
+)
+
+// Enum value maps for RuleFormat.
+var (
+	RuleFormat_name = map[int32]string{
+		0: "RULE_FORMAT_UNSPECIFIED",
+		1: "RULE_FORMAT_PLAIN",
+		2: "RULE_FORMAT_CSV",
+		3: "RULE_FORMAT_JSON_LINES",
+	}
+	RuleFormat_value = map[string]int32{
+		"RULE_FORMAT_UNSPECIFIED": 0,
+		"RULE_FORMAT_PLAIN":       1,
+		"RULE_FORMAT_CSV":         2,
+		"RULE_FORMAT_JSON_LINES":  3,
+	}
+)
+
+func (x RuleFormat) Enum() *RuleFormat {
+	p := new(RuleFormat)
+	*p = x
+	return p
+}
+
+func (x RuleFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RuleFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_limiter_AuthLimiter_proto_enumTypes[2].Descriptor()
+}
+
+func (RuleFormat) Type() protoreflect.EnumType {
+	return &file_proto_limiter_AuthLimiter_proto_enumTypes[2]
+}
+
+func (x RuleFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RuleFormat.Descriptor instead.
+func (RuleFormat) EnumDescriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{2}
+}
+
+type ImportMode int32
+
+const (
+	ImportMode_IMPORT_MODE_UNSPECIFIED ImportMode = 0
+	// Add networks that are not in the list yet.
+	ImportMode_IMPORT_MODE_MERGE ImportMode = 1
+	// Replace previously imported rules of the list; rules added otherwise are kept.
+	ImportMode_IMPORT_MODE_REPLACE ImportMode = 2
+)
+
+// Enum value maps for ImportMode.
+var (
+	ImportMode_name = map[int32]string{
+		0: "IMPORT_MODE_UNSPECIFIED",
+		1: "IMPORT_MODE_MERGE",
+		2: "IMPORT_MODE_REPLACE",
+	}
+	ImportMode_value = map[string]int32{
+		"IMPORT_MODE_UNSPECIFIED": 0,
+		"IMPORT_MODE_MERGE":       1,
+		"IMPORT_MODE_REPLACE":     2,
+	}
+)
+
+func (x ImportMode) Enum() *ImportMode {
+	p := new(ImportMode)
+	*p = x
+	return p
+}
+
+func (x ImportMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImportMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_limiter_AuthLimiter_proto_enumTypes[3].Descriptor()
+}
+
+func (ImportMode) Type() protoreflect.EnumType {
+	return &file_proto_limiter_AuthLimiter_proto_enumTypes[3]
+}
+
+func (x ImportMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImportMode.Descriptor instead.
+func (ImportMode) EnumDescriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{3}
+}
+
+type ListType int32
+
+const (
+	ListType_LIST_TYPE_UNSPECIFIED ListType = 0
+	ListType_LIST_TYPE_WHITELIST   ListType = 1
+	ListType_LIST_TYPE_BLACKLIST   ListType = 2
+)
+
+// Enum value maps for ListType.
+var (
+	ListType_name = map[int32]string{
+		0: "LIST_TYPE_UNSPECIFIED",
+		1: "LIST_TYPE_WHITELIST",
+		2: "LIST_TYPE_BLACKLIST",
+	}
+	ListType_value = map[string]int32{
+		"LIST_TYPE_UNSPECIFIED": 0,
+		"LIST_TYPE_WHITELIST":   1,
+		"LIST_TYPE_BLACKLIST":   2,
+	}
+)
+
+func (x ListType) Enum() *ListType {
+	p := new(ListType)
+	*p = x
+	return p
+}
+
+func (x ListType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_limiter_AuthLimiter_proto_enumTypes[4].Descriptor()
+}
+
+func (ListType) Type() protoreflect.EnumType {
+	return &file_proto_limiter_AuthLimiter_proto_enumTypes[4]
+}
+
+func (x ListType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListType.Descriptor instead.
+func (ListType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{4}
+}
+
+type RuleSource int32
+
+const (
+	RuleSource_RULE_SOURCE_UNSPECIFIED RuleSource = 0
+	// Added through the API.
+	RuleSource_RULE_SOURCE_MANUAL RuleSource = 1
+	// Added with limiter_cli.
+	RuleSource_RULE_SOURCE_CLI RuleSource = 2
+	// Added automatically for repeated rate limit violations.
+	RuleSource_RULE_SOURCE_AUTO_BAN RuleSource = 3
+	// Added by bulk import.
+	RuleSource_RULE_SOURCE_IMPORT RuleSource = 4
+	// Synchronized from a threat feed.
+	RuleSource_RULE_SOURCE_FEED RuleSource = 5
+)
+
+// Enum value maps for RuleSource.
+var (
+	RuleSource_name = map[int32]string{
+		0: "RULE_SOURCE_UNSPECIFIED",
+		1: "RULE_SOURCE_MANUAL",
+		2: "RULE_SOURCE_CLI",
+		3: "RULE_SOURCE_AUTO_BAN",
+		4: "RULE_SOURCE_IMPORT",
+		5: "RULE_SOURCE_FEED",
+	}
+	RuleSource_value = map[string]int32{
+		"RULE_SOURCE_UNSPECIFIED": 0,
+		"RULE_SOURCE_MANUAL":      1,
+		"RULE_SOURCE_CLI":         2,
+		"RULE_SOURCE_AUTO_BAN":    3,
+		"RULE_SOURCE_IMPORT":      4,
+		"RULE_SOURCE_FEED":        5,
+	}
+)
+
+func (x RuleSource) Enum() *RuleSource {
+	p := new(RuleSource)
+	*p = x
+	return p
+}
+
+func (x RuleSource) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RuleSource) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_limiter_AuthLimiter_proto_enumTypes[5].Descriptor()
+}
+
+func (RuleSource) Type() protoreflect.EnumType {
+	return &file_proto_limiter_AuthLimiter_proto_enumTypes[5]
+}
+
+func (x RuleSource) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RuleSource.Descriptor instead.
+func (RuleSource) EnumDescriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{5}
+}
+
 type WhiteListAddRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IpNet         string                 `protobuf:"bytes,1,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	IpNet string                 `protobuf:"bytes,1,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
+	// Rule stops matching after this time and is removed by the reaper; permanent when unset.
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Free-text reason the rule was added.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Operator or system that added the rule.
+	CreatedBy string `protobuf:"bytes,4,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	// How the rule was added, RULE_SOURCE_MANUAL when unspecified.
+	Source RuleSource `protobuf:"varint,5,opt,name=source,proto3,enum=AuthLimiter.RuleSource" json:"source,omitempty"`
+	// Add the rule even if it duplicates, is subsumed by or overlaps existing rules.
+	Force         bool `protobuf:"varint,6,opt,name=force,proto3" json:"force,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -67,6 +410,41 @@ func (x *WhiteListAddRequest) GetIpNet() string {
 	return ""
 }
 
+func (x *WhiteListAddRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *WhiteListAddRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *WhiteListAddRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *WhiteListAddRequest) GetSource() RuleSource {
+	if x != nil {
+		return x.Source
+	}
+	return RuleSource_RULE_SOURCE_UNSPECIFIED
+}
+
+func (x *WhiteListAddRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
 type WhiteListDeleteRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IpNet         string                 `protobuf:"bytes,1,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
@@ -112,8 +490,18 @@ func (x *WhiteListDeleteRequest) GetIpNet() string {
 }
 
 type BlackListAddRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IpNet         string                 `protobuf:"bytes,1,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	IpNet string                 `protobuf:"bytes,1,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
+	// Rule stops matching after this time and is removed by the reaper; permanent when unset.
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Free-text reason the rule was added.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Operator or system that added the rule.
+	CreatedBy string `protobuf:"bytes,4,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	// How the rule was added, RULE_SOURCE_MANUAL when unspecified.
+	Source RuleSource `protobuf:"varint,5,opt,name=source,proto3,enum=AuthLimiter.RuleSource" json:"source,omitempty"`
+	// Add the rule even if it duplicates, is subsumed by or overlaps existing rules.
+	Force         bool `protobuf:"varint,6,opt,name=force,proto3" json:"force,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,14 +513,1289 @@ func (x *BlackListAddRequest) Reset() {
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BlackListAddRequest) String() string {
+func (x *BlackListAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlackListAddRequest) ProtoMessage() {}
+
+func (x *BlackListAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlackListAddRequest.ProtoReflect.Descriptor instead.
+func (*BlackListAddRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *BlackListAddRequest) GetIpNet() string {
+	if x != nil {
+		return x.IpNet
+	}
+	return ""
+}
+
+func (x *BlackListAddRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *BlackListAddRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *BlackListAddRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *BlackListAddRequest) GetSource() RuleSource {
+	if x != nil {
+		return x.Source
+	}
+	return RuleSource_RULE_SOURCE_UNSPECIFIED
+}
+
+func (x *BlackListAddRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+type BlackListDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IpNet         string                 `protobuf:"bytes,1,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlackListDeleteRequest) Reset() {
+	*x = BlackListDeleteRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlackListDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlackListDeleteRequest) ProtoMessage() {}
+
+func (x *BlackListDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlackListDeleteRequest.ProtoReflect.Descriptor instead.
+func (*BlackListDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *BlackListDeleteRequest) GetIpNet() string {
+	if x != nil {
+		return x.IpNet
+	}
+	return ""
+}
+
+type RuleFindRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IpNet         string                 `protobuf:"bytes,1,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuleFindRequest) Reset() {
+	*x = RuleFindRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleFindRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleFindRequest) ProtoMessage() {}
+
+func (x *RuleFindRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleFindRequest.ProtoReflect.Descriptor instead.
+func (*RuleFindRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RuleFindRequest) GetIpNet() string {
+	if x != nil {
+		return x.IpNet
+	}
+	return ""
+}
+
+type AuditLogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Entries per page, 50 when unset.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page.
+	PageToken     string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	Action        string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Actor         string `protobuf:"bytes,4,opt,name=actor,proto3" json:"actor,omitempty"`
+	Target        string `protobuf:"bytes,5,opt,name=target,proto3" json:"target,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditLogRequest) Reset() {
+	*x = AuditLogRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLogRequest) ProtoMessage() {}
+
+func (x *AuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLogRequest.ProtoReflect.Descriptor instead.
+func (*AuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AuditLogRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *AuditLogRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *AuditLogRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditLogRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditLogRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+type RuleListRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Rules per page, 50 when unset.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// next_page_token of the previous page.
+	PageToken string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Only rules whose network contains this IP.
+	ContainsIp string `protobuf:"bytes,3,opt,name=contains_ip,json=containsIp,proto3" json:"contains_ip,omitempty"`
+	// Only rules whose network lies within this network.
+	Prefix string `protobuf:"bytes,4,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// Case-insensitive substring of the rule reason.
+	Reason        string     `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedBy     string     `protobuf:"bytes,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	Source        RuleSource `protobuf:"varint,7,opt,name=source,proto3,enum=AuthLimiter.RuleSource" json:"source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuleListRequest) Reset() {
+	*x = RuleListRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleListRequest) ProtoMessage() {}
+
+func (x *RuleListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleListRequest.ProtoReflect.Descriptor instead.
+func (*RuleListRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RuleListRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *RuleListRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *RuleListRequest) GetContainsIp() string {
+	if x != nil {
+		return x.ContainsIp
+	}
+	return ""
+}
+
+func (x *RuleListRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *RuleListRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RuleListRequest) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+func (x *RuleListRequest) GetSource() RuleSource {
+	if x != nil {
+		return x.Source
+	}
+	return RuleSource_RULE_SOURCE_UNSPECIFIED
+}
+
+type ImportRulesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*ImportRulesRequest_Header
+	//	*ImportRulesRequest_Chunk
+	Payload       isImportRulesRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRulesRequest) Reset() {
+	*x = ImportRulesRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRulesRequest) ProtoMessage() {}
+
+func (x *ImportRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRulesRequest.ProtoReflect.Descriptor instead.
+func (*ImportRulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ImportRulesRequest) GetPayload() isImportRulesRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *ImportRulesRequest) GetHeader() *ImportRulesHeader {
+	if x != nil {
+		if x, ok := x.Payload.(*ImportRulesRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *ImportRulesRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*ImportRulesRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isImportRulesRequest_Payload interface {
+	isImportRulesRequest_Payload()
+}
+
+type ImportRulesRequest_Header struct {
+	Header *ImportRulesHeader `protobuf:"bytes,1,opt,name=header,proto3,oneof"`
+}
+
+type ImportRulesRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*ImportRulesRequest_Header) isImportRulesRequest_Payload() {}
+
+func (*ImportRulesRequest_Chunk) isImportRulesRequest_Payload() {}
+
+type ImportRulesHeader struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ListType ListType               `protobuf:"varint,1,opt,name=list_type,json=listType,proto3,enum=AuthLimiter.ListType" json:"list_type,omitempty"`
+	// RULE_FORMAT_PLAIN when unspecified.
+	Format RuleFormat `protobuf:"varint,2,opt,name=format,proto3,enum=AuthLimiter.RuleFormat" json:"format,omitempty"`
+	// IMPORT_MODE_MERGE when unspecified.
+	Mode ImportMode `protobuf:"varint,3,opt,name=mode,proto3,enum=AuthLimiter.ImportMode" json:"mode,omitempty"`
+	// Reason for lines that have none.
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedBy     string `protobuf:"bytes,5,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRulesHeader) Reset() {
+	*x = ImportRulesHeader{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRulesHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRulesHeader) ProtoMessage() {}
+
+func (x *ImportRulesHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRulesHeader.ProtoReflect.Descriptor instead.
+func (*ImportRulesHeader) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ImportRulesHeader) GetListType() ListType {
+	if x != nil {
+		return x.ListType
+	}
+	return ListType_LIST_TYPE_UNSPECIFIED
+}
+
+func (x *ImportRulesHeader) GetFormat() RuleFormat {
+	if x != nil {
+		return x.Format
+	}
+	return RuleFormat_RULE_FORMAT_UNSPECIFIED
+}
+
+func (x *ImportRulesHeader) GetMode() ImportMode {
+	if x != nil {
+		return x.Mode
+	}
+	return ImportMode_IMPORT_MODE_UNSPECIFIED
+}
+
+func (x *ImportRulesHeader) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ImportRulesHeader) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
+}
+
+type ExportRulesRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ListType ListType               `protobuf:"varint,1,opt,name=list_type,json=listType,proto3,enum=AuthLimiter.ListType" json:"list_type,omitempty"`
+	// RULE_FORMAT_PLAIN when unspecified.
+	Format        RuleFormat `protobuf:"varint,2,opt,name=format,proto3,enum=AuthLimiter.RuleFormat" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportRulesRequest) Reset() {
+	*x = ExportRulesRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportRulesRequest) ProtoMessage() {}
+
+func (x *ExportRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportRulesRequest.ProtoReflect.Descriptor instead.
+func (*ExportRulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ExportRulesRequest) GetListType() ListType {
+	if x != nil {
+		return x.ListType
+	}
+	return ListType_LIST_TYPE_UNSPECIFIED
+}
+
+func (x *ExportRulesRequest) GetFormat() RuleFormat {
+	if x != nil {
+		return x.Format
+	}
+	return RuleFormat_RULE_FORMAT_UNSPECIFIED
+}
+
+type BucketResetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketResetRequest) Reset() {
+	*x = BucketResetRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketResetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketResetRequest) ProtoMessage() {}
+
+func (x *BucketResetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketResetRequest.ProtoReflect.Descriptor instead.
+func (*BucketResetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BucketResetRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *BucketResetRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type LimitCheckRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Ip            string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LimitCheckRequest) Reset() {
+	*x = LimitCheckRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LimitCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LimitCheckRequest) ProtoMessage() {}
+
+func (x *LimitCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LimitCheckRequest.ProtoReflect.Descriptor instead.
+func (*LimitCheckRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LimitCheckRequest) GetLogin() string {
+	if x != nil {
+		return x.Login
+	}
+	return ""
+}
+
+func (x *LimitCheckRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *LimitCheckRequest) GetIp() string {
+	if x != nil {
+		return x.Ip
+	}
+	return ""
+}
+
+type CheckRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Dimension values by limit type. Required dimensions of the rate_limit table must be set,
+	// dimensions with empty values are ignored, dimensions without a limit are rejected.
+	Dimensions    map[string]string `protobuf:"bytes,1,rep,name=dimensions,proto3" json:"dimensions,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckRequest) Reset() {
+	*x = CheckRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckRequest) ProtoMessage() {}
+
+func (x *CheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckRequest.ProtoReflect.Descriptor instead.
+func (*CheckRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CheckRequest) GetDimensions() map[string]string {
+	if x != nil {
+		return x.Dimensions
+	}
+	return nil
+}
+
+type ShadowModeSetRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Shadow mode for all rate limits.
+	Enabled bool `protobuf:"varint,1,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	// Limit types (login/password/ip) in shadow mode regardless of enabled.
+	LimitTypes    []string `protobuf:"bytes,2,rep,name=limit_types,json=limitTypes,proto3" json:"limit_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShadowModeSetRequest) Reset() {
+	*x = ShadowModeSetRequest{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShadowModeSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShadowModeSetRequest) ProtoMessage() {}
+
+func (x *ShadowModeSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShadowModeSetRequest.ProtoReflect.Descriptor instead.
+func (*ShadowModeSetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ShadowModeSetRequest) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *ShadowModeSetRequest) GetLimitTypes() []string {
+	if x != nil {
+		return x.LimitTypes
+	}
+	return nil
+}
+
+type WhiteListAddResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WhiteListAddResponse) Reset() {
+	*x = WhiteListAddResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WhiteListAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhiteListAddResponse) ProtoMessage() {}
+
+func (x *WhiteListAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhiteListAddResponse.ProtoReflect.Descriptor instead.
+func (*WhiteListAddResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{14}
+}
+
+type WhiteListDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WhiteListDeleteResponse) Reset() {
+	*x = WhiteListDeleteResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WhiteListDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WhiteListDeleteResponse) ProtoMessage() {}
+
+func (x *WhiteListDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WhiteListDeleteResponse.ProtoReflect.Descriptor instead.
+func (*WhiteListDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{15}
+}
+
+type BlackListAddResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlackListAddResponse) Reset() {
+	*x = BlackListAddResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlackListAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlackListAddResponse) ProtoMessage() {}
+
+func (x *BlackListAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlackListAddResponse.ProtoReflect.Descriptor instead.
+func (*BlackListAddResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{16}
+}
+
+type BlackListDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlackListDeleteResponse) Reset() {
+	*x = BlackListDeleteResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlackListDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlackListDeleteResponse) ProtoMessage() {}
+
+func (x *BlackListDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlackListDeleteResponse.ProtoReflect.Descriptor instead.
+func (*BlackListDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{17}
+}
+
+type BucketResetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BucketResetResponse) Reset() {
+	*x = BucketResetResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BucketResetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BucketResetResponse) ProtoMessage() {}
+
+func (x *BucketResetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BucketResetResponse.ProtoReflect.Descriptor instead.
+func (*BucketResetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{18}
+}
+
+type ShadowModeSetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShadowModeSetResponse) Reset() {
+	*x = ShadowModeSetResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShadowModeSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShadowModeSetResponse) ProtoMessage() {}
+
+func (x *ShadowModeSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShadowModeSetResponse.ProtoReflect.Descriptor instead.
+func (*ShadowModeSetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{19}
+}
+
+type AuditLogResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Entries []*AuditEntry          `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	// Token of the next (older) page, empty for the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditLogResponse) Reset() {
+	*x = AuditLogResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLogResponse) ProtoMessage() {}
+
+func (x *AuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLogResponse.ProtoReflect.Descriptor instead.
+func (*AuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AuditLogResponse) GetEntries() []*AuditEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *AuditLogResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type AuditEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// whitelist.add, whitelist.delete, blacklist.add, blacklist.delete, rules.import, limit.reset or shadow.set.
+	Action string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// Caller as reported by the client: x-actor metadata or forwarded client address, peer address otherwise.
+	Actor     string `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	RequestId string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	// Changed object: network, bucket keys or list.
+	Target string `protobuf:"bytes,5,opt,name=target,proto3" json:"target,omitempty"`
+	// JSON state before the change, empty when there was none.
+	Before string `protobuf:"bytes,6,opt,name=before,proto3" json:"before,omitempty"`
+	// JSON state after the change, empty when there is none.
+	After     string                 `protobuf:"bytes,7,opt,name=after,proto3" json:"after,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Connection address of the caller, not supplied by the client.
+	Peer          string `protobuf:"bytes,9,opt,name=peer,proto3" json:"peer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditEntry) Reset() {
+	*x = AuditEntry{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditEntry) ProtoMessage() {}
+
+func (x *AuditEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditEntry.ProtoReflect.Descriptor instead.
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AuditEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetBefore() string {
+	if x != nil {
+		return x.Before
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetAfter() string {
+	if x != nil {
+		return x.After
+	}
+	return ""
+}
+
+func (x *AuditEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *AuditEntry) GetPeer() string {
+	if x != nil {
+		return x.Peer
+	}
+	return ""
+}
+
+type RuleFindResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*Rule                `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RuleFindResponse) Reset() {
+	*x = RuleFindResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RuleFindResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RuleFindResponse) ProtoMessage() {}
+
+func (x *RuleFindResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RuleFindResponse.ProtoReflect.Descriptor instead.
+func (*RuleFindResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RuleFindResponse) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type ImportRulesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Added int32                  `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	// Networks already in the list or repeated in the file.
+	Skipped int32 `protobuf:"varint,2,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Invalid int32 `protobuf:"varint,3,opt,name=invalid,proto3" json:"invalid,omitempty"`
+	// Previously imported rules removed by IMPORT_MODE_REPLACE.
+	Removed int32 `protobuf:"varint,4,opt,name=removed,proto3" json:"removed,omitempty"`
+	// First 100 invalid lines.
+	Errors        []*ImportLineError `protobuf:"bytes,5,rep,name=errors,proto3" json:"errors,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportRulesResponse) Reset() {
+	*x = ImportRulesResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportRulesResponse) ProtoMessage() {}
+
+func (x *ImportRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportRulesResponse.ProtoReflect.Descriptor instead.
+func (*ImportRulesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ImportRulesResponse) GetAdded() int32 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+func (x *ImportRulesResponse) GetSkipped() int32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ImportRulesResponse) GetInvalid() int32 {
+	if x != nil {
+		return x.Invalid
+	}
+	return 0
+}
+
+func (x *ImportRulesResponse) GetRemoved() int32 {
+	if x != nil {
+		return x.Removed
+	}
+	return 0
+}
+
+func (x *ImportRulesResponse) GetErrors() []*ImportLineError {
+	if x != nil {
+		return x.Errors
+	}
+	return nil
+}
+
+type ImportLineError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line          int32                  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportLineError) Reset() {
+	*x = ImportLineError{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportLineError) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlackListAddRequest) ProtoMessage() {}
+func (*ImportLineError) ProtoMessage() {}
 
-func (x *BlackListAddRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[2]
+func (x *ImportLineError) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -143,40 +1806,47 @@ func (x *BlackListAddRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlackListAddRequest.ProtoReflect.Descriptor instead.
-func (*BlackListAddRequest) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use ImportLineError.ProtoReflect.Descriptor instead.
+func (*ImportLineError) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *BlackListAddRequest) GetIpNet() string {
+func (x *ImportLineError) GetLine() int32 {
 	if x != nil {
-		return x.IpNet
+		return x.Line
+	}
+	return 0
+}
+
+func (x *ImportLineError) GetMessage() string {
+	if x != nil {
+		return x.Message
 	}
 	return ""
 }
 
-type BlackListDeleteRequest struct {
+type ExportRulesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	IpNet         string                 `protobuf:"bytes,1,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
+	Chunk         []byte                 `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BlackListDeleteRequest) Reset() {
-	*x = BlackListDeleteRequest{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[3]
+func (x *ExportRulesResponse) Reset() {
+	*x = ExportRulesResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BlackListDeleteRequest) String() string {
+func (x *ExportRulesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BlackListDeleteRequest) ProtoMessage() {}
+func (*ExportRulesResponse) ProtoMessage() {}
 
-func (x *BlackListDeleteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[3]
+func (x *ExportRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -187,41 +1857,42 @@ func (x *BlackListDeleteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BlackListDeleteRequest.ProtoReflect.Descriptor instead.
-func (*BlackListDeleteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ExportRulesResponse.ProtoReflect.Descriptor instead.
+func (*ExportRulesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *BlackListDeleteRequest) GetIpNet() string {
+func (x *ExportRulesResponse) GetChunk() []byte {
 	if x != nil {
-		return x.IpNet
+		return x.Chunk
 	}
-	return ""
+	return nil
 }
 
-type BucketResetRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
-	Ip            string                 `protobuf:"bytes,2,opt,name=ip,proto3" json:"ip,omitempty"`
+type RuleListResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Rules []*Rule                `protobuf:"bytes,1,rep,name=rules,proto3" json:"rules,omitempty"`
+	// Token of the next page, empty for the last page.
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BucketResetRequest) Reset() {
-	*x = BucketResetRequest{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[4]
+func (x *RuleListResponse) Reset() {
+	*x = RuleListResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BucketResetRequest) String() string {
+func (x *RuleListResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BucketResetRequest) ProtoMessage() {}
+func (*RuleListResponse) ProtoMessage() {}
 
-func (x *BucketResetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[4]
+func (x *RuleListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -232,49 +1903,57 @@ func (x *BucketResetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BucketResetRequest.ProtoReflect.Descriptor instead.
-func (*BucketResetRequest) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use RuleListResponse.ProtoReflect.Descriptor instead.
+func (*RuleListResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *BucketResetRequest) GetLogin() string {
+func (x *RuleListResponse) GetRules() []*Rule {
 	if x != nil {
-		return x.Login
+		return x.Rules
 	}
-	return ""
+	return nil
 }
 
-func (x *BucketResetRequest) GetIp() string {
+func (x *RuleListResponse) GetNextPageToken() string {
 	if x != nil {
-		return x.Ip
+		return x.NextPageToken
 	}
 	return ""
 }
 
-type LimitCheckRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Login         string                 `protobuf:"bytes,1,opt,name=login,proto3" json:"login,omitempty"`
-	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	Ip            string                 `protobuf:"bytes,3,opt,name=ip,proto3" json:"ip,omitempty"`
+type Rule struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	IpNet    string                 `protobuf:"bytes,2,opt,name=ip_net,json=ipNet,proto3" json:"ip_net,omitempty"`
+	ListType ListType               `protobuf:"varint,3,opt,name=list_type,json=listType,proto3,enum=AuthLimiter.ListType" json:"list_type,omitempty"`
+	// Unset for permanent rules.
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Reason    string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedBy string                 `protobuf:"bytes,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Source    RuleSource             `protobuf:"varint,8,opt,name=source,proto3,enum=AuthLimiter.RuleSource" json:"source,omitempty"`
+	// Threat feed that owns the rule, set for RULE_SOURCE_FEED.
+	Feed          string `protobuf:"bytes,9,opt,name=feed,proto3" json:"feed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LimitCheckRequest) Reset() {
-	*x = LimitCheckRequest{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[5]
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LimitCheckRequest) String() string {
+func (x *Rule) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LimitCheckRequest) ProtoMessage() {}
+func (*Rule) ProtoMessage() {}
 
-func (x *LimitCheckRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[5]
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -285,89 +1964,115 @@ func (x *LimitCheckRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LimitCheckRequest.ProtoReflect.Descriptor instead.
-func (*LimitCheckRequest) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *LimitCheckRequest) GetLogin() string {
+func (x *Rule) GetId() int64 {
 	if x != nil {
-		return x.Login
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-func (x *LimitCheckRequest) GetPassword() string {
+func (x *Rule) GetIpNet() string {
 	if x != nil {
-		return x.Password
+		return x.IpNet
 	}
 	return ""
 }
 
-func (x *LimitCheckRequest) GetIp() string {
+func (x *Rule) GetListType() ListType {
 	if x != nil {
-		return x.Ip
+		return x.ListType
 	}
-	return ""
+	return ListType_LIST_TYPE_UNSPECIFIED
 }
 
-type WhiteListAddResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Rule) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
 }
 
-func (x *WhiteListAddResponse) Reset() {
-	*x = WhiteListAddResponse{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[6]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Rule) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
 }
 
-func (x *WhiteListAddResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Rule) GetCreatedBy() string {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return ""
 }
 
-func (*WhiteListAddResponse) ProtoMessage() {}
+func (x *Rule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
 
-func (x *WhiteListAddResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[6]
+func (x *Rule) GetSource() RuleSource {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Source
 	}
-	return mi.MessageOf(x)
+	return RuleSource_RULE_SOURCE_UNSPECIFIED
 }
 
-// Deprecated: Use WhiteListAddResponse.ProtoReflect.Descriptor instead.
-func (*WhiteListAddResponse) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{6}
+func (x *Rule) GetFeed() string {
+	if x != nil {
+		return x.Feed
+	}
+	return ""
 }
 
-type WhiteListDeleteResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
+type LimitCheckResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Allowed bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	// Remaining attempts under the strictest limit, -1 when not limited (whitelisted IP).
+	Remaining int32 `protobuf:"varint,2,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// Size of the strictest limit.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Seconds until the attempt may be allowed again, set when denied by a rate limit.
+	RetryAfter int32 `protobuf:"varint,4,opt,name=retry_after,json=retryAfter,proto3" json:"retry_after,omitempty"`
+	// Limit type (dimension, login/password/ip, device, tenant, ...) that denied the attempt.
+	LimitType string `protobuf:"bytes,5,opt,name=limit_type,json=limitType,proto3" json:"limit_type,omitempty"`
+	// Why the attempt was allowed or denied.
+	Reason DecisionReason `protobuf:"varint,6,opt,name=reason,proto3,enum=AuthLimiter.DecisionReason" json:"reason,omitempty"`
+	// Blacklist/whitelist rule that decided, set for DECISION_REASON_BLACKLIST and DECISION_REASON_WHITELIST.
+	RuleId    int64  `protobuf:"varint,7,opt,name=rule_id,json=ruleId,proto3" json:"rule_id,omitempty"`
+	RuleIpNet string `protobuf:"bytes,8,opt,name=rule_ip_net,json=ruleIpNet,proto3" json:"rule_ip_net,omitempty"`
+	// Attempt is allowed only because the exceeded limit is in shadow mode.
+	Shadow bool `protobuf:"varint,9,opt,name=shadow,proto3" json:"shadow,omitempty"`
+	// Tier of limit_type that denied the attempt, empty for the base tier.
+	LimitTier string `protobuf:"bytes,10,opt,name=limit_tier,json=limitTier,proto3" json:"limit_tier,omitempty"`
+	// Seconds until the strictest limit is fully replenished (upper estimate), 0 when it is unused.
+	ResetAfter    int32 `protobuf:"varint,11,opt,name=reset_after,json=resetAfter,proto3" json:"reset_after,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *WhiteListDeleteResponse) Reset() {
-	*x = WhiteListDeleteResponse{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[7]
+func (x *LimitCheckResponse) Reset() {
+	*x = LimitCheckResponse{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *WhiteListDeleteResponse) String() string {
+func (x *LimitCheckResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*WhiteListDeleteResponse) ProtoMessage() {}
+func (*LimitCheckResponse) ProtoMessage() {}
 
-func (x *WhiteListDeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[7]
+func (x *LimitCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -378,104 +2083,112 @@ func (x *WhiteListDeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use WhiteListDeleteResponse.ProtoReflect.Descriptor instead.
-func (*WhiteListDeleteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use LimitCheckResponse.ProtoReflect.Descriptor instead.
+func (*LimitCheckResponse) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{28}
 }
 
-type BlackListAddResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *LimitCheckResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
 }
 
-func (x *BlackListAddResponse) Reset() {
-	*x = BlackListAddResponse{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[8]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *LimitCheckResponse) GetRemaining() int32 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
 }
 
-func (x *BlackListAddResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *LimitCheckResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
 }
 
-func (*BlackListAddResponse) ProtoMessage() {}
-
-func (x *BlackListAddResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[8]
+func (x *LimitCheckResponse) GetRetryAfter() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.RetryAfter
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use BlackListAddResponse.ProtoReflect.Descriptor instead.
-func (*BlackListAddResponse) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{8}
+func (x *LimitCheckResponse) GetLimitType() string {
+	if x != nil {
+		return x.LimitType
+	}
+	return ""
 }
 
-type BlackListDeleteResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *LimitCheckResponse) GetReason() DecisionReason {
+	if x != nil {
+		return x.Reason
+	}
+	return DecisionReason_DECISION_REASON_UNSPECIFIED
 }
 
-func (x *BlackListDeleteResponse) Reset() {
-	*x = BlackListDeleteResponse{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[9]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *LimitCheckResponse) GetRuleId() int64 {
+	if x != nil {
+		return x.RuleId
+	}
+	return 0
 }
 
-func (x *BlackListDeleteResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *LimitCheckResponse) GetRuleIpNet() string {
+	if x != nil {
+		return x.RuleIpNet
+	}
+	return ""
 }
 
-func (*BlackListDeleteResponse) ProtoMessage() {}
+func (x *LimitCheckResponse) GetShadow() bool {
+	if x != nil {
+		return x.Shadow
+	}
+	return false
+}
 
-func (x *BlackListDeleteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[9]
+func (x *LimitCheckResponse) GetLimitTier() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.LimitTier
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use BlackListDeleteResponse.ProtoReflect.Descriptor instead.
-func (*BlackListDeleteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{9}
+func (x *LimitCheckResponse) GetResetAfter() int32 {
+	if x != nil {
+		return x.ResetAfter
+	}
+	return 0
 }
 
-type BucketResetResponse struct {
+// Error details of ALREADY_EXISTS (duplicate) and FAILED_PRECONDITION (overlap, subsumed)
+// returned by WhiteListAdd and BlackListAdd without force.
+type RuleConflicts struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Conflicts     []*RuleConflict        `protobuf:"bytes,1,rep,name=conflicts,proto3" json:"conflicts,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *BucketResetResponse) Reset() {
-	*x = BucketResetResponse{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[10]
+func (x *RuleConflicts) Reset() {
+	*x = RuleConflicts{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *BucketResetResponse) String() string {
+func (x *RuleConflicts) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*BucketResetResponse) ProtoMessage() {}
+func (*RuleConflicts) ProtoMessage() {}
 
-func (x *BucketResetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[10]
+func (x *RuleConflicts) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -486,33 +2199,42 @@ func (x *BucketResetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use BucketResetResponse.ProtoReflect.Descriptor instead.
-func (*BucketResetResponse) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use RuleConflicts.ProtoReflect.Descriptor instead.
+func (*RuleConflicts) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{29}
 }
 
-type LimitCheckResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Allowed       bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+func (x *RuleConflicts) GetConflicts() []*RuleConflict {
+	if x != nil {
+		return x.Conflicts
+	}
+	return nil
+}
+
+type RuleConflict struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  RuleConflictKind       `protobuf:"varint,1,opt,name=kind,proto3,enum=AuthLimiter.RuleConflictKind" json:"kind,omitempty"`
+	// Existing rule the added network conflicts with.
+	Rule          *Rule `protobuf:"bytes,2,opt,name=rule,proto3" json:"rule,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *LimitCheckResponse) Reset() {
-	*x = LimitCheckResponse{}
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[11]
+func (x *RuleConflict) Reset() {
+	*x = RuleConflict{}
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *LimitCheckResponse) String() string {
+func (x *RuleConflict) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*LimitCheckResponse) ProtoMessage() {}
+func (*RuleConflict) ProtoMessage() {}
 
-func (x *LimitCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[11]
+func (x *RuleConflict) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_limiter_AuthLimiter_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -523,66 +2245,261 @@ func (x *LimitCheckResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use LimitCheckResponse.ProtoReflect.Descriptor instead.
-func (*LimitCheckResponse) Descriptor() ([]byte, []int) {
-	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use RuleConflict.ProtoReflect.Descriptor instead.
+func (*RuleConflict) Descriptor() ([]byte, []int) {
+	return file_proto_limiter_AuthLimiter_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *LimitCheckResponse) GetAllowed() bool {
+func (x *RuleConflict) GetKind() RuleConflictKind {
 	if x != nil {
-		return x.Allowed
+		return x.Kind
 	}
-	return false
+	return RuleConflictKind_RULE_CONFLICT_KIND_UNSPECIFIED
+}
+
+func (x *RuleConflict) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
 }
 
 var File_proto_limiter_AuthLimiter_proto protoreflect.FileDescriptor
 
 const file_proto_limiter_AuthLimiter_proto_rawDesc = "" +
 	"\n" +
-	"\x1fproto/limiter/AuthLimiter.proto\x12\vAuthLimiter\x1a!meshapi/gateway/annotations.proto\x1a\x1bbuf/validate/validate.proto\"\x80\x01\n" +
-	"\x13WhiteListAddRequest\x12\\\n" +
-	"\x06ip_net\x18\x01 \x01(\tBE\xbaHB\xc8\x01\x01r=2;^([0-9]{1,3}\\.){3}[0-9]{1,3}(\\/([0-9]|[1-2][0-9]|3[0-2]))?$R\x05ipNet:\v\xbaJ\bj\x06ip_net\"\x83\x01\n" +
-	"\x16WhiteListDeleteRequest\x12\\\n" +
-	"\x06ip_net\x18\x01 \x01(\tBE\xbaHB\xc8\x01\x01r=2;^([0-9]{1,3}\\.){3}[0-9]{1,3}(\\/([0-9]|[1-2][0-9]|3[0-2]))?$R\x05ipNet:\v\xbaJ\bj\x06ip_net\"\x80\x01\n" +
-	"\x13BlackListAddRequest\x12\\\n" +
-	"\x06ip_net\x18\x01 \x01(\tBE\xbaHB\xc8\x01\x01r=2;^([0-9]{1,3}\\.){3}[0-9]{1,3}(\\/([0-9]|[1-2][0-9]|3[0-2]))?$R\x05ipNet:\v\xbaJ\bj\x06ip_net\"\x83\x01\n" +
-	"\x16BlackListDeleteRequest\x12\\\n" +
-	"\x06ip_net\x18\x01 \x01(\tBE\xbaHB\xc8\x01\x01r=2;^([0-9]{1,3}\\.){3}[0-9]{1,3}(\\/([0-9]|[1-2][0-9]|3[0-2]))?$R\x05ipNet:\v\xbaJ\bj\x06ip_net\"y\n" +
+	"\x1fproto/limiter/AuthLimiter.proto\x12\vAuthLimiter\x1a!meshapi/gateway/annotations.proto\x1a\x1bbuf/validate/validate.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8f\x03\n" +
+	"\x13WhiteListAddRequest\x12|\n" +
+	"\x06ip_net\x18\x01 \x01(\tBe\xbaHb\xba\x01\\\n" +
+	"\x06ip_net\x120value must be an IPv4 or IPv6 address or network\x1a this.isIp() || this.isIpPrefix()\xc8\x01\x01R\x05ipNet\x12C\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampB\b\xbaH\x05\xb2\x01\x02@\x01R\texpiresAt\x12'\n" +
+	"\x06reason\x18\x03 \x01(\tB\x0f\xbaH\x05r\x03\x18\xff\x01\xbaJ\x04\xa0\x01\xff\x01R\x06reason\x12.\n" +
+	"\n" +
+	"created_by\x18\x04 \x01(\tB\x0f\xbaH\x05r\x03\x18\xff\x01\xbaJ\x04\xa0\x01\xff\x01R\tcreatedBy\x129\n" +
+	"\x06source\x18\x05 \x01(\x0e2\x17.AuthLimiter.RuleSourceB\b\xbaH\x05\x82\x01\x02\x10\x01R\x06source\x12\x14\n" +
+	"\x05force\x18\x06 \x01(\bR\x05force:\v\xbaJ\bj\x06ip_net\"\xa3\x01\n" +
+	"\x16WhiteListDeleteRequest\x12|\n" +
+	"\x06ip_net\x18\x01 \x01(\tBe\xbaHb\xba\x01\\\n" +
+	"\x06ip_net\x120value must be an IPv4 or IPv6 address or network\x1a this.isIp() || this.isIpPrefix()\xc8\x01\x01R\x05ipNet:\v\xbaJ\bj\x06ip_net\"\x8f\x03\n" +
+	"\x13BlackListAddRequest\x12|\n" +
+	"\x06ip_net\x18\x01 \x01(\tBe\xbaHb\xba\x01\\\n" +
+	"\x06ip_net\x120value must be an IPv4 or IPv6 address or network\x1a this.isIp() || this.isIpPrefix()\xc8\x01\x01R\x05ipNet\x12C\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampB\b\xbaH\x05\xb2\x01\x02@\x01R\texpiresAt\x12'\n" +
+	"\x06reason\x18\x03 \x01(\tB\x0f\xbaH\x05r\x03\x18\xff\x01\xbaJ\x04\xa0\x01\xff\x01R\x06reason\x12.\n" +
+	"\n" +
+	"created_by\x18\x04 \x01(\tB\x0f\xbaH\x05r\x03\x18\xff\x01\xbaJ\x04\xa0\x01\xff\x01R\tcreatedBy\x129\n" +
+	"\x06source\x18\x05 \x01(\x0e2\x17.AuthLimiter.RuleSourceB\b\xbaH\x05\x82\x01\x02\x10\x01R\x06source\x12\x14\n" +
+	"\x05force\x18\x06 \x01(\bR\x05force:\v\xbaJ\bj\x06ip_net\"\xa3\x01\n" +
+	"\x16BlackListDeleteRequest\x12|\n" +
+	"\x06ip_net\x18\x01 \x01(\tBe\xbaHb\xba\x01\\\n" +
+	"\x06ip_net\x120value must be an IPv4 or IPv6 address or network\x1a this.isIp() || this.isIpPrefix()\xc8\x01\x01R\x05ipNet:\v\xbaJ\bj\x06ip_net\"\x9c\x01\n" +
+	"\x0fRuleFindRequest\x12|\n" +
+	"\x06ip_net\x18\x01 \x01(\tBe\xbaHb\xba\x01\\\n" +
+	"\x06ip_net\x120value must be an IPv4 or IPv6 address or network\x1a this.isIp() || this.isIpPrefix()\xc8\x01\x01R\x05ipNet:\v\xbaJ\bj\x06ip_net\"\xec\x01\n" +
+	"\x0fAuditLogRequest\x124\n" +
+	"\tpage_size\x18\x01 \x01(\x05B\x17\xbaH\a\x1a\x05\x18\xe8\a(\x00\xbaJ\n" +
+	"\x81\x01\x00\x00\x00\x00\x00@\x8f@R\bpageSize\x12,\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tB\r\xbaH\x04r\x02\x18\x14\xbaJ\x03\xa0\x01\x14R\tpageToken\x12%\n" +
+	"\x06action\x18\x03 \x01(\tB\r\xbaH\x04r\x02\x182\xbaJ\x03\xa0\x012R\x06action\x12%\n" +
+	"\x05actor\x18\x04 \x01(\tB\x0f\xbaH\x05r\x03\x18\xff\x01\xbaJ\x04\xa0\x01\xff\x01R\x05actor\x12'\n" +
+	"\x06target\x18\x05 \x01(\tB\x0f\xbaH\x05r\x03\x18\xff\x01\xbaJ\x04\xa0\x01\xff\x01R\x06target\"\x8c\x04\n" +
+	"\x0fRuleListRequest\x124\n" +
+	"\tpage_size\x18\x01 \x01(\x05B\x17\xbaH\a\x1a\x05\x18\xe8\a(\x00\xbaJ\n" +
+	"\x81\x01\x00\x00\x00\x00\x00@\x8f@R\bpageSize\x12,\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tB\r\xbaH\x04r\x02\x18\x14\xbaJ\x03\xa0\x01\x14R\tpageToken\x12v\n" +
+	"\vcontains_ip\x18\x03 \x01(\tBU\xbaHR\xba\x01O\n" +
+	"\vcontains_ip\x12%value must be an IPv4 or IPv6 address\x1a\x19this == '' || this.isIp()R\n" +
+	"containsIp\x12\x88\x01\n" +
+	"\x06prefix\x18\x04 \x01(\tBp\xbaHm\xba\x01j\n" +
+	"\x06prefix\x120value must be an IPv4 or IPv6 address or network\x1a.this == '' || this.isIp() || this.isIpPrefix()R\x06prefix\x12'\n" +
+	"\x06reason\x18\x05 \x01(\tB\x0f\xbaH\x05r\x03\x18\xff\x01\xbaJ\x04\xa0\x01\xff\x01R\x06reason\x12.\n" +
+	"\n" +
+	"created_by\x18\x06 \x01(\tB\x0f\xbaH\x05r\x03\x18\xff\x01\xbaJ\x04\xa0\x01\xff\x01R\tcreatedBy\x129\n" +
+	"\x06source\x18\a \x01(\x0e2\x17.AuthLimiter.RuleSourceB\b\xbaH\x05\x82\x01\x02\x10\x01R\x06source\"\x83\x01\n" +
+	"\x12ImportRulesRequest\x128\n" +
+	"\x06header\x18\x01 \x01(\v2\x1e.AuthLimiter.ImportRulesHeaderH\x00R\x06header\x12!\n" +
+	"\x05chunk\x18\x02 \x01(\fB\t\xbaH\x06z\x04\x18\x80\x80@H\x00R\x05chunkB\x10\n" +
+	"\apayload\x12\x05\xbaH\x02\b\x01\"\x90\x02\n" +
+	"\x11ImportRulesHeader\x12>\n" +
+	"\tlist_type\x18\x01 \x01(\x0e2\x15.AuthLimiter.ListTypeB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\blistType\x129\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x17.AuthLimiter.RuleFormatB\b\xbaH\x05\x82\x01\x02\x10\x01R\x06format\x125\n" +
+	"\x04mode\x18\x03 \x01(\x0e2\x17.AuthLimiter.ImportModeB\b\xbaH\x05\x82\x01\x02\x10\x01R\x04mode\x12 \n" +
+	"\x06reason\x18\x04 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\x06reason\x12'\n" +
+	"\n" +
+	"created_by\x18\x05 \x01(\tB\b\xbaH\x05r\x03\x18\xff\x01R\tcreatedBy\"\x8f\x01\n" +
+	"\x12ExportRulesRequest\x12>\n" +
+	"\tlist_type\x18\x01 \x01(\x0e2\x15.AuthLimiter.ListTypeB\n" +
+	"\xbaH\a\x82\x01\x04\x10\x01 \x00R\blistType\x129\n" +
+	"\x06format\x18\x02 \x01(\x0e2\x17.AuthLimiter.RuleFormatB\b\xbaH\x05\x82\x01\x02\x10\x01R\x06format\"\x89\x01\n" +
 	"\x12BucketResetRequest\x12-\n" +
 	"\x05login\x18\x01 \x01(\tB\x17\xbaH\n" +
-	"\xc8\x01\x01r\x05\x10\x01\x18\x80\x01\xbaJ\a\xa0\x01\x80\x01\xa8\x01\x01R\x05login\x12$\n" +
-	"\x02ip\x18\x02 \x01(\tB\x14\xbaH\a\xc8\x01\x01r\x02p\x01\xbaJ\a\xc2\x02\x04ipv4R\x02ip:\x0e\xbaJ\vj\x05loginj\x02ip\"\xb7\x01\n" +
+	"\xc8\x01\x01r\x05\x10\x01\x18\x80\x01\xbaJ\a\xa0\x01\x80\x01\xa8\x01\x01R\x05login\x124\n" +
+	"\x02ip\x18\x02 \x01(\tB$\xbaH\a\xc8\x01\x01r\x02p\x01\xbaJ\x17\xe2\x01\x14IPv4 or IPv6 addressR\x02ip:\x0e\xbaJ\vj\x05loginj\x02ip\"\xc7\x01\n" +
 	"\x11LimitCheckRequest\x12-\n" +
 	"\x05login\x18\x01 \x01(\tB\x17\xbaH\n" +
 	"\xc8\x01\x01r\x05\x10\x01\x18\x80\x01\xbaJ\a\xa0\x01\x80\x01\xa8\x01\x01R\x05login\x123\n" +
 	"\bpassword\x18\x02 \x01(\tB\x17\xbaH\n" +
-	"\xc8\x01\x01r\x05\x10\x01\x18\x80\x02\xbaJ\a\xa0\x01\x80\x02\xa8\x01\x01R\bpassword\x12$\n" +
-	"\x02ip\x18\x03 \x01(\tB\x14\xbaH\a\xc8\x01\x01r\x02p\x01\xbaJ\a\xc2\x02\x04ipv4R\x02ip:\x18\xbaJ\x15j\x05loginj\bpasswordj\x02ip\"\x16\n" +
+	"\xc8\x01\x01r\x05\x10\x01\x18\x80\x02\xbaJ\a\xa0\x01\x80\x02\xa8\x01\x01R\bpassword\x124\n" +
+	"\x02ip\x18\x03 \x01(\tB$\xbaH\a\xc8\x01\x01r\x02p\x01\xbaJ\x17\xe2\x01\x14IPv4 or IPv6 addressR\x02ip:\x18\xbaJ\x15j\x05loginj\bpasswordj\x02ip\"\xc4\x01\n" +
+	"\fCheckRequest\x12d\n" +
+	"\n" +
+	"dimensions\x18\x01 \x03(\v2).AuthLimiter.CheckRequest.DimensionsEntryB\x19\xbaH\x16\x9a\x01\x13\b\x01\x10 \"\x06r\x04\x10\x01\x182*\x05r\x03\x18\x80\x02R\n" +
+	"dimensions\x1a=\n" +
+	"\x0fDimensionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01:\x0f\xbaJ\fj\n" +
+	"dimensions\"a\n" +
+	"\x14ShadowModeSetRequest\x12\x18\n" +
+	"\aenabled\x18\x01 \x01(\bR\aenabled\x12/\n" +
+	"\vlimit_types\x18\x02 \x03(\tB\x0e\xbaH\v\x92\x01\b\"\x06r\x04\x10\x01\x182R\n" +
+	"limitTypes\"\x16\n" +
 	"\x14WhiteListAddResponse\"\x19\n" +
 	"\x17WhiteListDeleteResponse\"\x16\n" +
 	"\x14BlackListAddResponse\"\x19\n" +
 	"\x17BlackListDeleteResponse\"\x15\n" +
-	"\x13BucketResetResponse\".\n" +
+	"\x13BucketResetResponse\"\x17\n" +
+	"\x15ShadowModeSetResponse\"m\n" +
+	"\x10AuditLogResponse\x121\n" +
+	"\aentries\x18\x01 \x03(\v2\x17.AuthLimiter.AuditEntryR\aentries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xfe\x01\n" +
+	"\n" +
+	"AuditEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06action\x18\x02 \x01(\tR\x06action\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tR\trequestId\x12\x16\n" +
+	"\x06target\x18\x05 \x01(\tR\x06target\x12\x16\n" +
+	"\x06before\x18\x06 \x01(\tR\x06before\x12\x14\n" +
+	"\x05after\x18\a \x01(\tR\x05after\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x12\n" +
+	"\x04peer\x18\t \x01(\tR\x04peer\";\n" +
+	"\x10RuleFindResponse\x12'\n" +
+	"\x05rules\x18\x01 \x03(\v2\x11.AuthLimiter.RuleR\x05rules\"\xaf\x01\n" +
+	"\x13ImportRulesResponse\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\x05R\x05added\x12\x18\n" +
+	"\askipped\x18\x02 \x01(\x05R\askipped\x12\x18\n" +
+	"\ainvalid\x18\x03 \x01(\x05R\ainvalid\x12\x18\n" +
+	"\aremoved\x18\x04 \x01(\x05R\aremoved\x124\n" +
+	"\x06errors\x18\x05 \x03(\v2\x1c.AuthLimiter.ImportLineErrorR\x06errors\"?\n" +
+	"\x0fImportLineError\x12\x12\n" +
+	"\x04line\x18\x01 \x01(\x05R\x04line\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"+\n" +
+	"\x13ExportRulesResponse\x12\x14\n" +
+	"\x05chunk\x18\x01 \x01(\fR\x05chunk\"c\n" +
+	"\x10RuleListResponse\x12'\n" +
+	"\x05rules\x18\x01 \x03(\v2\x11.AuthLimiter.RuleR\x05rules\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xd3\x02\n" +
+	"\x04Rule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x15\n" +
+	"\x06ip_net\x18\x02 \x01(\tR\x05ipNet\x122\n" +
+	"\tlist_type\x18\x03 \x01(\x0e2\x15.AuthLimiter.ListTypeR\blistType\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x06 \x01(\tR\tcreatedBy\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12/\n" +
+	"\x06source\x18\b \x01(\x0e2\x17.AuthLimiter.RuleSourceR\x06source\x12\x12\n" +
+	"\x04feed\x18\t \x01(\tR\x04feed\"\xe8\x02\n" +
 	"\x12LimitCheckResponse\x12\x18\n" +
-	"\aallowed\x18\x01 \x01(\bR\aallowed2\x9c\a\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x1c\n" +
+	"\tremaining\x18\x02 \x01(\x05R\tremaining\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x1f\n" +
+	"\vretry_after\x18\x04 \x01(\x05R\n" +
+	"retryAfter\x12\x1d\n" +
+	"\n" +
+	"limit_type\x18\x05 \x01(\tR\tlimitType\x123\n" +
+	"\x06reason\x18\x06 \x01(\x0e2\x1b.AuthLimiter.DecisionReasonR\x06reason\x12\x17\n" +
+	"\arule_id\x18\a \x01(\x03R\x06ruleId\x12\x1e\n" +
+	"\vrule_ip_net\x18\b \x01(\tR\truleIpNet\x12\x16\n" +
+	"\x06shadow\x18\t \x01(\bR\x06shadow\x12\x1d\n" +
+	"\n" +
+	"limit_tier\x18\n" +
+	" \x01(\tR\tlimitTier\x12\x1f\n" +
+	"\vreset_after\x18\v \x01(\x05R\n" +
+	"resetAfter\"H\n" +
+	"\rRuleConflicts\x127\n" +
+	"\tconflicts\x18\x01 \x03(\v2\x19.AuthLimiter.RuleConflictR\tconflicts\"h\n" +
+	"\fRuleConflict\x121\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1d.AuthLimiter.RuleConflictKindR\x04kind\x12%\n" +
+	"\x04rule\x18\x02 \x01(\v2\x11.AuthLimiter.RuleR\x04rule*\xab\x01\n" +
+	"\x0eDecisionReason\x12\x1f\n" +
+	"\x1bDECISION_REASON_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16DECISION_REASON_PASSED\x10\x01\x12\x1e\n" +
+	"\x1aDECISION_REASON_RATE_LIMIT\x10\x02\x12\x1d\n" +
+	"\x19DECISION_REASON_BLACKLIST\x10\x03\x12\x1d\n" +
+	"\x19DECISION_REASON_WHITELIST\x10\x04*\x99\x01\n" +
+	"\x10RuleConflictKind\x12\"\n" +
+	"\x1eRULE_CONFLICT_KIND_UNSPECIFIED\x10\x00\x12 \n" +
+	"\x1cRULE_CONFLICT_KIND_DUPLICATE\x10\x01\x12\x1e\n" +
+	"\x1aRULE_CONFLICT_KIND_OVERLAP\x10\x02\x12\x1f\n" +
+	"\x1bRULE_CONFLICT_KIND_SUBSUMED\x10\x03*q\n" +
+	"\n" +
+	"RuleFormat\x12\x1b\n" +
+	"\x17RULE_FORMAT_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11RULE_FORMAT_PLAIN\x10\x01\x12\x13\n" +
+	"\x0fRULE_FORMAT_CSV\x10\x02\x12\x1a\n" +
+	"\x16RULE_FORMAT_JSON_LINES\x10\x03*Y\n" +
+	"\n" +
+	"ImportMode\x12\x1b\n" +
+	"\x17IMPORT_MODE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11IMPORT_MODE_MERGE\x10\x01\x12\x17\n" +
+	"\x13IMPORT_MODE_REPLACE\x10\x02*W\n" +
+	"\bListType\x12\x19\n" +
+	"\x15LIST_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13LIST_TYPE_WHITELIST\x10\x01\x12\x17\n" +
+	"\x13LIST_TYPE_BLACKLIST\x10\x02*\x9e\x01\n" +
+	"\n" +
+	"RuleSource\x12\x1b\n" +
+	"\x17RULE_SOURCE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12RULE_SOURCE_MANUAL\x10\x01\x12\x13\n" +
+	"\x0fRULE_SOURCE_CLI\x10\x02\x12\x18\n" +
+	"\x14RULE_SOURCE_AUTO_BAN\x10\x03\x12\x16\n" +
+	"\x12RULE_SOURCE_IMPORT\x10\x04\x12\x14\n" +
+	"\x10RULE_SOURCE_FEED\x10\x052\xe5\x10\n" +
 	"\vAuthLimiter\x12\x92\x01\n" +
 	"\fWhiteListAdd\x12 .AuthLimiter.WhiteListAddRequest\x1a!.AuthLimiter.WhiteListAddResponse\"=\xb2J\x0fB\x01*\"\n" +
 	"/whitelist\xbaJ(\n" +
-	"\tWhitelist\x12\x1bAdd IP network to whitelist\x12\x9d\x01\n" +
+	"\tWhitelist\x12\x1bAdd IP network to whitelist\x12\x81\x01\n" +
+	"\rWhiteListList\x12\x1c.AuthLimiter.RuleListRequest\x1a\x1d.AuthLimiter.RuleListResponse\"3\xb2J\f\x12\n" +
+	"/whitelist\xbaJ!\n" +
+	"\tWhitelist\x12\x14List whitelist rules\x12\x9d\x01\n" +
 	"\x0fWhiteListDelete\x12#.AuthLimiter.WhiteListDeleteRequest\x1a$.AuthLimiter.WhiteListDeleteResponse\"?\xb2J\f*\n" +
 	"/whitelist\xbaJ-\n" +
 	"\tWhitelist\x12 Remove IP network from whitelist\x12\x92\x01\n" +
 	"\fBlackListAdd\x12 .AuthLimiter.BlackListAddRequest\x1a!.AuthLimiter.BlackListAddResponse\"=\xb2J\x0fB\x01*\"\n" +
 	"/blacklist\xbaJ(\n" +
-	"\tBlacklist\x12\x1bAdd IP network to blacklist\x12\x9d\x01\n" +
+	"\tBlacklist\x12\x1bAdd IP network to blacklist\x12\x81\x01\n" +
+	"\rBlackListList\x12\x1c.AuthLimiter.RuleListRequest\x1a\x1d.AuthLimiter.RuleListResponse\"3\xb2J\f\x12\n" +
+	"/blacklist\xbaJ!\n" +
+	"\tBlacklist\x12\x14List blacklist rules\x12\x9d\x01\n" +
 	"\x0fBlackListDelete\x12#.AuthLimiter.BlackListDeleteRequest\x1a$.AuthLimiter.BlackListDeleteResponse\"?\xb2J\f*\n" +
 	"/blacklist\xbaJ-\n" +
-	"\tBlacklist\x12 Remove IP network from blacklist\x12\x85\x01\n" +
+	"\tBlacklist\x12 Remove IP network from blacklist\x12\xa0\x01\n" +
+	"\bRuleFind\x12\x1c.AuthLimiter.RuleFindRequest\x1a\x1d.AuthLimiter.RuleFindResponse\"W\xb2J\b\x12\x06/rules\xbaJI\n" +
+	"\tWhitelist\n" +
+	"\tBlacklist\x121Find whitelist and blacklist rules for IP network\x12R\n" +
+	"\vImportRules\x12\x1f.AuthLimiter.ImportRulesRequest\x1a .AuthLimiter.ImportRulesResponse(\x01\x12R\n" +
+	"\vExportRules\x12\x1f.AuthLimiter.ExportRulesRequest\x1a .AuthLimiter.ExportRulesResponse0\x01\x12\x85\x01\n" +
 	"\vBucketReset\x12\x1f.AuthLimiter.BucketResetRequest\x1a .AuthLimiter.BucketResetResponse\"3\xb2J\vB\x01*\"\x06/reset\xbaJ\"\n" +
 	"\aLimiter\x12\x17Reset rate limit bucket\x12\x9a\x01\n" +
 	"\n" +
 	"LimitCheck\x12\x1e.AuthLimiter.LimitCheckRequest\x1a\x1f.AuthLimiter.LimitCheckResponse\"K\xb2J\vB\x01*\"\x06/check\xbaJ:\n" +
-	"\aLimiter\x12/Check whether authentication attempt is allowedB\xcd\x01\xbaJ\x9a\x01\n" +
+	"\aLimiter\x12/Check whether authentication attempt is allowed\x12\xa8\x01\n" +
+	"\x05Check\x12\x19.AuthLimiter.CheckRequest\x1a\x1f.AuthLimiter.LimitCheckResponse\"c\xb2J\x16B\x01*\"\x11/check/dimensions\xbaJG\n" +
+	"\aLimiter\x12<Check whether a request with arbitrary dimensions is allowed\x12\xa0\x01\n" +
+	"\rShadowModeSet\x12!.AuthLimiter.ShadowModeSetRequest\x1a\".AuthLimiter.ShadowModeSetResponse\"H\xb2J\fB\x01*\"\a/shadow\xbaJ6\n" +
+	"\aLimiter\x12+Switch rate limits to shadow (dry-run) mode\x12\xa5\x02\n" +
+	"\bAuditLog\x12\x1c.AuthLimiter.AuditLogRequest\x1a\x1d.AuthLimiter.AuditLogResponse\"\xdb\x01\xb2J\b\x12\x06/audit\xbaJ\xcc\x01\n" +
+	"\x05Audit\x12)List administrative changes, newest first\x1a\x97\x01actor is self-reported by the caller (x-actor metadata or X-Forwarded-For) and is not authenticated; peer is the connection address seen by the server.B\xcd\x01\xbaJ\x9a\x01\n" +
 	"S\n" +
 	"\x10Auth Limiter API\x1a8Authentication rate limiter and abuse protection service:\x051.0.0\x12\x1e\n" +
 	"\x15http://localhost:8888\x12\x05Local:\v\n" +
@@ -602,39 +2519,108 @@ func file_proto_limiter_AuthLimiter_proto_rawDescGZIP() []byte {
 	return file_proto_limiter_AuthLimiter_proto_rawDescData
 }
 
-var file_proto_limiter_AuthLimiter_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_proto_limiter_AuthLimiter_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_proto_limiter_AuthLimiter_proto_msgTypes = make([]protoimpl.MessageInfo, 32)
 var file_proto_limiter_AuthLimiter_proto_goTypes = []any{
-	(*WhiteListAddRequest)(nil),     // 0: AuthLimiter.WhiteListAddRequest
-	(*WhiteListDeleteRequest)(nil),  // 1: AuthLimiter.WhiteListDeleteRequest
-	(*BlackListAddRequest)(nil),     // 2: AuthLimiter.BlackListAddRequest
-	(*BlackListDeleteRequest)(nil),  // 3: AuthLimiter.BlackListDeleteRequest
-	(*BucketResetRequest)(nil),      // 4: AuthLimiter.BucketResetRequest
-	(*LimitCheckRequest)(nil),       // 5: AuthLimiter.LimitCheckRequest
-	(*WhiteListAddResponse)(nil),    // 6: AuthLimiter.WhiteListAddResponse
-	(*WhiteListDeleteResponse)(nil), // 7: AuthLimiter.WhiteListDeleteResponse
-	(*BlackListAddResponse)(nil),    // 8: AuthLimiter.BlackListAddResponse
-	(*BlackListDeleteResponse)(nil), // 9: AuthLimiter.BlackListDeleteResponse
-	(*BucketResetResponse)(nil),     // 10: AuthLimiter.BucketResetResponse
-	(*LimitCheckResponse)(nil),      // 11: AuthLimiter.LimitCheckResponse
+	(DecisionReason)(0),             // 0: AuthLimiter.DecisionReason
+	(RuleConflictKind)(0),           // 1: AuthLimiter.RuleConflictKind
+	(RuleFormat)(0),                 // 2: AuthLimiter.RuleFormat
+	(ImportMode)(0),                 // 3: AuthLimiter.ImportMode
+	(ListType)(0),                   // 4: AuthLimiter.ListType
+	(RuleSource)(0),                 // 5: AuthLimiter.RuleSource
+	(*WhiteListAddRequest)(nil),     // 6: AuthLimiter.WhiteListAddRequest
+	(*WhiteListDeleteRequest)(nil),  // 7: AuthLimiter.WhiteListDeleteRequest
+	(*BlackListAddRequest)(nil),     // 8: AuthLimiter.BlackListAddRequest
+	(*BlackListDeleteRequest)(nil),  // 9: AuthLimiter.BlackListDeleteRequest
+	(*RuleFindRequest)(nil),         // 10: AuthLimiter.RuleFindRequest
+	(*AuditLogRequest)(nil),         // 11: AuthLimiter.AuditLogRequest
+	(*RuleListRequest)(nil),         // 12: AuthLimiter.RuleListRequest
+	(*ImportRulesRequest)(nil),      // 13: AuthLimiter.ImportRulesRequest
+	(*ImportRulesHeader)(nil),       // 14: AuthLimiter.ImportRulesHeader
+	(*ExportRulesRequest)(nil),      // 15: AuthLimiter.ExportRulesRequest
+	(*BucketResetRequest)(nil),      // 16: AuthLimiter.BucketResetRequest
+	(*LimitCheckRequest)(nil),       // 17: AuthLimiter.LimitCheckRequest
+	(*CheckRequest)(nil),            // 18: AuthLimiter.CheckRequest
+	(*ShadowModeSetRequest)(nil),    // 19: AuthLimiter.ShadowModeSetRequest
+	(*WhiteListAddResponse)(nil),    // 20: AuthLimiter.WhiteListAddResponse
+	(*WhiteListDeleteResponse)(nil), // 21: AuthLimiter.WhiteListDeleteResponse
+	(*BlackListAddResponse)(nil),    // 22: AuthLimiter.BlackListAddResponse
+	(*BlackListDeleteResponse)(nil), // 23: AuthLimiter.BlackListDeleteResponse
+	(*BucketResetResponse)(nil),     // 24: AuthLimiter.BucketResetResponse
+	(*ShadowModeSetResponse)(nil),   // 25: AuthLimiter.ShadowModeSetResponse
+	(*AuditLogResponse)(nil),        // 26: AuthLimiter.AuditLogResponse
+	(*AuditEntry)(nil),              // 27: AuthLimiter.AuditEntry
+	(*RuleFindResponse)(nil),        // 28: AuthLimiter.RuleFindResponse
+	(*ImportRulesResponse)(nil),     // 29: AuthLimiter.ImportRulesResponse
+	(*ImportLineError)(nil),         // 30: AuthLimiter.ImportLineError
+	(*ExportRulesResponse)(nil),     // 31: AuthLimiter.ExportRulesResponse
+	(*RuleListResponse)(nil),        // 32: AuthLimiter.RuleListResponse
+	(*Rule)(nil),                    // 33: AuthLimiter.Rule
+	(*LimitCheckResponse)(nil),      // 34: AuthLimiter.LimitCheckResponse
+	(*RuleConflicts)(nil),           // 35: AuthLimiter.RuleConflicts
+	(*RuleConflict)(nil),            // 36: AuthLimiter.RuleConflict
+	nil,                             // 37: AuthLimiter.CheckRequest.DimensionsEntry
+	(*timestamppb.Timestamp)(nil),   // 38: google.protobuf.Timestamp
 }
 var file_proto_limiter_AuthLimiter_proto_depIdxs = []int32{
-	0,  // 0: AuthLimiter.AuthLimiter.WhiteListAdd:input_type -> AuthLimiter.WhiteListAddRequest
-	1,  // 1: AuthLimiter.AuthLimiter.WhiteListDelete:input_type -> AuthLimiter.WhiteListDeleteRequest
-	2,  // 2: AuthLimiter.AuthLimiter.BlackListAdd:input_type -> AuthLimiter.BlackListAddRequest
-	3,  // 3: AuthLimiter.AuthLimiter.BlackListDelete:input_type -> AuthLimiter.BlackListDeleteRequest
-	4,  // 4: AuthLimiter.AuthLimiter.BucketReset:input_type -> AuthLimiter.BucketResetRequest
-	5,  // 5: AuthLimiter.AuthLimiter.LimitCheck:input_type -> AuthLimiter.LimitCheckRequest
-	6,  // 6: AuthLimiter.AuthLimiter.WhiteListAdd:output_type -> AuthLimiter.WhiteListAddResponse
-	7,  // 7: AuthLimiter.AuthLimiter.WhiteListDelete:output_type -> AuthLimiter.WhiteListDeleteResponse
-	8,  // 8: AuthLimiter.AuthLimiter.BlackListAdd:output_type -> AuthLimiter.BlackListAddResponse
-	9,  // 9: AuthLimiter.AuthLimiter.BlackListDelete:output_type -> AuthLimiter.BlackListDeleteResponse
-	10, // 10: AuthLimiter.AuthLimiter.BucketReset:output_type -> AuthLimiter.BucketResetResponse
-	11, // 11: AuthLimiter.AuthLimiter.LimitCheck:output_type -> AuthLimiter.LimitCheckResponse
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
-	0,  // [0:0] is the sub-list for extension type_name
-	0,  // [0:0] is the sub-list for extension extendee
-	0,  // [0:0] is the sub-list for field type_name
+	38, // 0: AuthLimiter.WhiteListAddRequest.expires_at:type_name -> google.protobuf.Timestamp
+	5,  // 1: AuthLimiter.WhiteListAddRequest.source:type_name -> AuthLimiter.RuleSource
+	38, // 2: AuthLimiter.BlackListAddRequest.expires_at:type_name -> google.protobuf.Timestamp
+	5,  // 3: AuthLimiter.BlackListAddRequest.source:type_name -> AuthLimiter.RuleSource
+	5,  // 4: AuthLimiter.RuleListRequest.source:type_name -> AuthLimiter.RuleSource
+	14, // 5: AuthLimiter.ImportRulesRequest.header:type_name -> AuthLimiter.ImportRulesHeader
+	4,  // 6: AuthLimiter.ImportRulesHeader.list_type:type_name -> AuthLimiter.ListType
+	2,  // 7: AuthLimiter.ImportRulesHeader.format:type_name -> AuthLimiter.RuleFormat
+	3,  // 8: AuthLimiter.ImportRulesHeader.mode:type_name -> AuthLimiter.ImportMode
+	4,  // 9: AuthLimiter.ExportRulesRequest.list_type:type_name -> AuthLimiter.ListType
+	2,  // 10: AuthLimiter.ExportRulesRequest.format:type_name -> AuthLimiter.RuleFormat
+	37, // 11: AuthLimiter.CheckRequest.dimensions:type_name -> AuthLimiter.CheckRequest.DimensionsEntry
+	27, // 12: AuthLimiter.AuditLogResponse.entries:type_name -> AuthLimiter.AuditEntry
+	38, // 13: AuthLimiter.AuditEntry.created_at:type_name -> google.protobuf.Timestamp
+	33, // 14: AuthLimiter.RuleFindResponse.rules:type_name -> AuthLimiter.Rule
+	30, // 15: AuthLimiter.ImportRulesResponse.errors:type_name -> AuthLimiter.ImportLineError
+	33, // 16: AuthLimiter.RuleListResponse.rules:type_name -> AuthLimiter.Rule
+	4,  // 17: AuthLimiter.Rule.list_type:type_name -> AuthLimiter.ListType
+	38, // 18: AuthLimiter.Rule.expires_at:type_name -> google.protobuf.Timestamp
+	38, // 19: AuthLimiter.Rule.created_at:type_name -> google.protobuf.Timestamp
+	5,  // 20: AuthLimiter.Rule.source:type_name -> AuthLimiter.RuleSource
+	0,  // 21: AuthLimiter.LimitCheckResponse.reason:type_name -> AuthLimiter.DecisionReason
+	36, // 22: AuthLimiter.RuleConflicts.conflicts:type_name -> AuthLimiter.RuleConflict
+	1,  // 23: AuthLimiter.RuleConflict.kind:type_name -> AuthLimiter.RuleConflictKind
+	33, // 24: AuthLimiter.RuleConflict.rule:type_name -> AuthLimiter.Rule
+	6,  // 25: AuthLimiter.AuthLimiter.WhiteListAdd:input_type -> AuthLimiter.WhiteListAddRequest
+	12, // 26: AuthLimiter.AuthLimiter.WhiteListList:input_type -> AuthLimiter.RuleListRequest
+	7,  // 27: AuthLimiter.AuthLimiter.WhiteListDelete:input_type -> AuthLimiter.WhiteListDeleteRequest
+	8,  // 28: AuthLimiter.AuthLimiter.BlackListAdd:input_type -> AuthLimiter.BlackListAddRequest
+	12, // 29: AuthLimiter.AuthLimiter.BlackListList:input_type -> AuthLimiter.RuleListRequest
+	9,  // 30: AuthLimiter.AuthLimiter.BlackListDelete:input_type -> AuthLimiter.BlackListDeleteRequest
+	10, // 31: AuthLimiter.AuthLimiter.RuleFind:input_type -> AuthLimiter.RuleFindRequest
+	13, // 32: AuthLimiter.AuthLimiter.ImportRules:input_type -> AuthLimiter.ImportRulesRequest
+	15, // 33: AuthLimiter.AuthLimiter.ExportRules:input_type -> AuthLimiter.ExportRulesRequest
+	16, // 34: AuthLimiter.AuthLimiter.BucketReset:input_type -> AuthLimiter.BucketResetRequest
+	17, // 35: AuthLimiter.AuthLimiter.LimitCheck:input_type -> AuthLimiter.LimitCheckRequest
+	18, // 36: AuthLimiter.AuthLimiter.Check:input_type -> AuthLimiter.CheckRequest
+	19, // 37: AuthLimiter.AuthLimiter.ShadowModeSet:input_type -> AuthLimiter.ShadowModeSetRequest
+	11, // 38: AuthLimiter.AuthLimiter.AuditLog:input_type -> AuthLimiter.AuditLogRequest
+	20, // 39: AuthLimiter.AuthLimiter.WhiteListAdd:output_type -> AuthLimiter.WhiteListAddResponse
+	32, // 40: AuthLimiter.AuthLimiter.WhiteListList:output_type -> AuthLimiter.RuleListResponse
+	21, // 41: AuthLimiter.AuthLimiter.WhiteListDelete:output_type -> AuthLimiter.WhiteListDeleteResponse
+	22, // 42: AuthLimiter.AuthLimiter.BlackListAdd:output_type -> AuthLimiter.BlackListAddResponse
+	32, // 43: AuthLimiter.AuthLimiter.BlackListList:output_type -> AuthLimiter.RuleListResponse
+	23, // 44: AuthLimiter.AuthLimiter.BlackListDelete:output_type -> AuthLimiter.BlackListDeleteResponse
+	28, // 45: AuthLimiter.AuthLimiter.RuleFind:output_type -> AuthLimiter.RuleFindResponse
+	29, // 46: AuthLimiter.AuthLimiter.ImportRules:output_type -> AuthLimiter.ImportRulesResponse
+	31, // 47: AuthLimiter.AuthLimiter.ExportRules:output_type -> AuthLimiter.ExportRulesResponse
+	24, // 48: AuthLimiter.AuthLimiter.BucketReset:output_type -> AuthLimiter.BucketResetResponse
+	34, // 49: AuthLimiter.AuthLimiter.LimitCheck:output_type -> AuthLimiter.LimitCheckResponse
+	34, // 50: AuthLimiter.AuthLimiter.Check:output_type -> AuthLimiter.LimitCheckResponse
+	25, // 51: AuthLimiter.AuthLimiter.ShadowModeSet:output_type -> AuthLimiter.ShadowModeSetResponse
+	26, // 52: AuthLimiter.AuthLimiter.AuditLog:output_type -> AuthLimiter.AuditLogResponse
+	39, // [39:53] is the sub-list for method output_type
+	25, // [25:39] is the sub-list for method input_type
+	25, // [25:25] is the sub-list for extension type_name
+	25, // [25:25] is the sub-list for extension extendee
+	0,  // [0:25] is the sub-list for field type_name
 }
 
 func init() { file_proto_limiter_AuthLimiter_proto_init() }
@@ -642,18 +2628,23 @@ func file_proto_limiter_AuthLimiter_proto_init() {
 	if File_proto_limiter_AuthLimiter_proto != nil {
 		return
 	}
+	file_proto_limiter_AuthLimiter_proto_msgTypes[7].OneofWrappers = []any{
+		(*ImportRulesRequest_Header)(nil),
+		(*ImportRulesRequest_Chunk)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_limiter_AuthLimiter_proto_rawDesc), len(file_proto_limiter_AuthLimiter_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   12,
+			NumEnums:      6,
+			NumMessages:   32,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_limiter_AuthLimiter_proto_goTypes,
 		DependencyIndexes: file_proto_limiter_AuthLimiter_proto_depIdxs,
+		EnumInfos:         file_proto_limiter_AuthLimiter_proto_enumTypes,
 		MessageInfos:      file_proto_limiter_AuthLimiter_proto_msgTypes,
 	}.Build()
 	File_proto_limiter_AuthLimiter_proto = out.File
@@ -20,11 +20,19 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	AuthLimiter_WhiteListAdd_FullMethodName    = "/AuthLimiter.AuthLimiter/WhiteListAdd"
+	AuthLimiter_WhiteListList_FullMethodName   = "/AuthLimiter.AuthLimiter/WhiteListList"
 	AuthLimiter_WhiteListDelete_FullMethodName = "/AuthLimiter.AuthLimiter/WhiteListDelete"
 	AuthLimiter_BlackListAdd_FullMethodName    = "/AuthLimiter.AuthLimiter/BlackListAdd"
+	AuthLimiter_BlackListList_FullMethodName   = "/AuthLimiter.AuthLimiter/BlackListList"
 	AuthLimiter_BlackListDelete_FullMethodName = "/AuthLimiter.AuthLimiter/BlackListDelete"
+	AuthLimiter_RuleFind_FullMethodName        = "/AuthLimiter.AuthLimiter/RuleFind"
+	AuthLimiter_ImportRules_FullMethodName     = "/AuthLimiter.AuthLimiter/ImportRules"
+	AuthLimiter_ExportRules_FullMethodName     = "/AuthLimiter.AuthLimiter/ExportRules"
 	AuthLimiter_BucketReset_FullMethodName     = "/AuthLimiter.AuthLimiter/BucketReset"
 	AuthLimiter_LimitCheck_FullMethodName      = "/AuthLimiter.AuthLimiter/LimitCheck"
+	AuthLimiter_Check_FullMethodName           = "/AuthLimiter.AuthLimiter/Check"
+	AuthLimiter_ShadowModeSet_FullMethodName   = "/AuthLimiter.AuthLimiter/ShadowModeSet"
+	AuthLimiter_AuditLog_FullMethodName        = "/AuthLimiter.AuthLimiter/AuditLog"
 )
 
 // AuthLimiterClient is the client API for AuthLimiter service.
@@ -36,11 +44,25 @@ const (
 // ////////////////////////////////////////////////////////
 type AuthLimiterClient interface {
 	WhiteListAdd(ctx context.Context, in *WhiteListAddRequest, opts ...grpc.CallOption) (*WhiteListAddResponse, error)
+	WhiteListList(ctx context.Context, in *RuleListRequest, opts ...grpc.CallOption) (*RuleListResponse, error)
 	WhiteListDelete(ctx context.Context, in *WhiteListDeleteRequest, opts ...grpc.CallOption) (*WhiteListDeleteResponse, error)
 	BlackListAdd(ctx context.Context, in *BlackListAddRequest, opts ...grpc.CallOption) (*BlackListAddResponse, error)
+	BlackListList(ctx context.Context, in *RuleListRequest, opts ...grpc.CallOption) (*RuleListResponse, error)
 	BlackListDelete(ctx context.Context, in *BlackListDeleteRequest, opts ...grpc.CallOption) (*BlackListDeleteResponse, error)
+	RuleFind(ctx context.Context, in *RuleFindRequest, opts ...grpc.CallOption) (*RuleFindResponse, error)
+	// Bulk import of rules: the first message carries the header, the following ones chunks of the file.
+	// REST: POST /rules/import with the file as the request body.
+	ImportRules(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportRulesRequest, ImportRulesResponse], error)
+	// Bulk export of active rules as chunks of the file. REST: GET /rules/export.
+	ExportRules(ctx context.Context, in *ExportRulesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportRulesResponse], error)
 	BucketReset(ctx context.Context, in *BucketResetRequest, opts ...grpc.CallOption) (*BucketResetResponse, error)
 	LimitCheck(ctx context.Context, in *LimitCheckRequest, opts ...grpc.CallOption) (*LimitCheckResponse, error)
+	// Generic check with arbitrary dimensions (login, ip, device, tenant, asn, ...) configured in the rate_limit table.
+	Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*LimitCheckResponse, error)
+	ShadowModeSet(ctx context.Context, in *ShadowModeSetRequest, opts ...grpc.CallOption) (*ShadowModeSetResponse, error)
+	// Administrative changes. The actor is self-reported by the caller (x-actor metadata or X-Forwarded-For)
+	// and is not authenticated; peer is the connection address seen by the server.
+	AuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (*AuditLogResponse, error)
 }
 
 type authLimiterClient struct {
@@ -61,6 +83,16 @@ func (c *authLimiterClient) WhiteListAdd(ctx context.Context, in *WhiteListAddRe
 	return out, nil
 }
 
+func (c *authLimiterClient) WhiteListList(ctx context.Context, in *RuleListRequest, opts ...grpc.CallOption) (*RuleListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RuleListResponse)
+	err := c.cc.Invoke(ctx, AuthLimiter_WhiteListList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authLimiterClient) WhiteListDelete(ctx context.Context, in *WhiteListDeleteRequest, opts ...grpc.CallOption) (*WhiteListDeleteResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(WhiteListDeleteResponse)
@@ -81,6 +113,16 @@ func (c *authLimiterClient) BlackListAdd(ctx context.Context, in *BlackListAddRe
 	return out, nil
 }
 
+func (c *authLimiterClient) BlackListList(ctx context.Context, in *RuleListRequest, opts ...grpc.CallOption) (*RuleListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RuleListResponse)
+	err := c.cc.Invoke(ctx, AuthLimiter_BlackListList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authLimiterClient) BlackListDelete(ctx context.Context, in *BlackListDeleteRequest, opts ...grpc.CallOption) (*BlackListDeleteResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BlackListDeleteResponse)
@@ -91,6 +133,48 @@ func (c *authLimiterClient) BlackListDelete(ctx context.Context, in *BlackListDe
 	return out, nil
 }
 
+func (c *authLimiterClient) RuleFind(ctx context.Context, in *RuleFindRequest, opts ...grpc.CallOption) (*RuleFindResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RuleFindResponse)
+	err := c.cc.Invoke(ctx, AuthLimiter_RuleFind_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authLimiterClient) ImportRules(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ImportRulesRequest, ImportRulesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuthLimiter_ServiceDesc.Streams[0], AuthLimiter_ImportRules_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ImportRulesRequest, ImportRulesResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuthLimiter_ImportRulesClient = grpc.ClientStreamingClient[ImportRulesRequest, ImportRulesResponse]
+
+func (c *authLimiterClient) ExportRules(ctx context.Context, in *ExportRulesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportRulesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AuthLimiter_ServiceDesc.Streams[1], AuthLimiter_ExportRules_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportRulesRequest, ExportRulesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuthLimiter_ExportRulesClient = grpc.ServerStreamingClient[ExportRulesResponse]
+
 func (c *authLimiterClient) BucketReset(ctx context.Context, in *BucketResetRequest, opts ...grpc.CallOption) (*BucketResetResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(BucketResetResponse)
@@ -111,6 +195,36 @@ func (c *authLimiterClient) LimitCheck(ctx context.Context, in *LimitCheckReques
 	return out, nil
 }
 
+func (c *authLimiterClient) Check(ctx context.Context, in *CheckRequest, opts ...grpc.CallOption) (*LimitCheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LimitCheckResponse)
+	err := c.cc.Invoke(ctx, AuthLimiter_Check_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authLimiterClient) ShadowModeSet(ctx context.Context, in *ShadowModeSetRequest, opts ...grpc.CallOption) (*ShadowModeSetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ShadowModeSetResponse)
+	err := c.cc.Invoke(ctx, AuthLimiter_ShadowModeSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authLimiterClient) AuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (*AuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuditLogResponse)
+	err := c.cc.Invoke(ctx, AuthLimiter_AuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthLimiterServer is the server API for AuthLimiter service.
 // All implementations must embed UnimplementedAuthLimiterServer
 // for forward compatibility.
@@ -120,11 +234,25 @@ func (c *authLimiterClient) LimitCheck(ctx context.Context, in *LimitCheckReques
 // ////////////////////////////////////////////////////////
 type AuthLimiterServer interface {
 	WhiteListAdd(context.Context, *WhiteListAddRequest) (*WhiteListAddResponse, error)
+	WhiteListList(context.Context, *RuleListRequest) (*RuleListResponse, error)
 	WhiteListDelete(context.Context, *WhiteListDeleteRequest) (*WhiteListDeleteResponse, error)
 	BlackListAdd(context.Context, *BlackListAddRequest) (*BlackListAddResponse, error)
+	BlackListList(context.Context, *RuleListRequest) (*RuleListResponse, error)
 	BlackListDelete(context.Context, *BlackListDeleteRequest) (*BlackListDeleteResponse, error)
+	RuleFind(context.Context, *RuleFindRequest) (*RuleFindResponse, error)
+	// Bulk import of rules: the first message carries the header, the following ones chunks of the file.
+	// REST: POST /rules/import with the file as the request body.
+	ImportRules(grpc.ClientStreamingServer[ImportRulesRequest, ImportRulesResponse]) error
+	// Bulk export of active rules as chunks of the file. REST: GET /rules/export.
+	ExportRules(*ExportRulesRequest, grpc.ServerStreamingServer[ExportRulesResponse]) error
 	BucketReset(context.Context, *BucketResetRequest) (*BucketResetResponse, error)
 	LimitCheck(context.Context, *LimitCheckRequest) (*LimitCheckResponse, error)
+	// Generic check with arbitrary dimensions (login, ip, device, tenant, asn, ...) configured in the rate_limit table.
+	Check(context.Context, *CheckRequest) (*LimitCheckResponse, error)
+	ShadowModeSet(context.Context, *ShadowModeSetRequest) (*ShadowModeSetResponse, error)
+	// Administrative changes. The actor is self-reported by the caller (x-actor metadata or X-Forwarded-For)
+	// and is not authenticated; peer is the connection address seen by the server.
+	AuditLog(context.Context, *AuditLogRequest) (*AuditLogResponse, error)
 	mustEmbedUnimplementedAuthLimiterServer()
 }
 
@@ -138,21 +266,45 @@ type UnimplementedAuthLimiterServer struct{}
 func (UnimplementedAuthLimiterServer) WhiteListAdd(context.Context, *WhiteListAddRequest) (*WhiteListAddResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method WhiteListAdd not implemented")
 }
+func (UnimplementedAuthLimiterServer) WhiteListList(context.Context, *RuleListRequest) (*RuleListResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method WhiteListList not implemented")
+}
 func (UnimplementedAuthLimiterServer) WhiteListDelete(context.Context, *WhiteListDeleteRequest) (*WhiteListDeleteResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method WhiteListDelete not implemented")
 }
 func (UnimplementedAuthLimiterServer) BlackListAdd(context.Context, *BlackListAddRequest) (*BlackListAddResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method BlackListAdd not implemented")
 }
+func (UnimplementedAuthLimiterServer) BlackListList(context.Context, *RuleListRequest) (*RuleListResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BlackListList not implemented")
+}
 func (UnimplementedAuthLimiterServer) BlackListDelete(context.Context, *BlackListDeleteRequest) (*BlackListDeleteResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method BlackListDelete not implemented")
 }
+func (UnimplementedAuthLimiterServer) RuleFind(context.Context, *RuleFindRequest) (*RuleFindResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RuleFind not implemented")
+}
+func (UnimplementedAuthLimiterServer) ImportRules(grpc.ClientStreamingServer[ImportRulesRequest, ImportRulesResponse]) error {
+	return status.Error(codes.Unimplemented, "method ImportRules not implemented")
+}
+func (UnimplementedAuthLimiterServer) ExportRules(*ExportRulesRequest, grpc.ServerStreamingServer[ExportRulesResponse]) error {
+	return status.Error(codes.Unimplemented, "method ExportRules not implemented")
+}
 func (UnimplementedAuthLimiterServer) BucketReset(context.Context, *BucketResetRequest) (*BucketResetResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method BucketReset not implemented")
 }
 func (UnimplementedAuthLimiterServer) LimitCheck(context.Context, *LimitCheckRequest) (*LimitCheckResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method LimitCheck not implemented")
 }
+func (UnimplementedAuthLimiterServer) Check(context.Context, *CheckRequest) (*LimitCheckResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Check not implemented")
+}
+func (UnimplementedAuthLimiterServer) ShadowModeSet(context.Context, *ShadowModeSetRequest) (*ShadowModeSetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ShadowModeSet not implemented")
+}
+func (UnimplementedAuthLimiterServer) AuditLog(context.Context, *AuditLogRequest) (*AuditLogResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AuditLog not implemented")
+}
 func (UnimplementedAuthLimiterServer) mustEmbedUnimplementedAuthLimiterServer() {}
 func (UnimplementedAuthLimiterServer) testEmbeddedByValue()                     {}
 
@@ -192,6 +344,24 @@ func _AuthLimiter_WhiteListAdd_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthLimiter_WhiteListList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuleListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthLimiterServer).WhiteListList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthLimiter_WhiteListList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthLimiterServer).WhiteListList(ctx, req.(*RuleListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthLimiter_WhiteListDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(WhiteListDeleteRequest)
 	if err := dec(in); err != nil {
@@ -228,6 +398,24 @@ func _AuthLimiter_BlackListAdd_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthLimiter_BlackListList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuleListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthLimiterServer).BlackListList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthLimiter_BlackListList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthLimiterServer).BlackListList(ctx, req.(*RuleListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthLimiter_BlackListDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BlackListDeleteRequest)
 	if err := dec(in); err != nil {
@@ -246,6 +434,42 @@ func _AuthLimiter_BlackListDelete_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthLimiter_RuleFind_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RuleFindRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthLimiterServer).RuleFind(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthLimiter_RuleFind_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthLimiterServer).RuleFind(ctx, req.(*RuleFindRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthLimiter_ImportRules_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AuthLimiterServer).ImportRules(&grpc.GenericServerStream[ImportRulesRequest, ImportRulesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuthLimiter_ImportRulesServer = grpc.ClientStreamingServer[ImportRulesRequest, ImportRulesResponse]
+
+func _AuthLimiter_ExportRules_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportRulesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthLimiterServer).ExportRules(m, &grpc.GenericServerStream[ExportRulesRequest, ExportRulesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AuthLimiter_ExportRulesServer = grpc.ServerStreamingServer[ExportRulesResponse]
+
 func _AuthLimiter_BucketReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(BucketResetRequest)
 	if err := dec(in); err != nil {
@@ -282,6 +506,60 @@ func _AuthLimiter_LimitCheck_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthLimiter_Check_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthLimiterServer).Check(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthLimiter_Check_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthLimiterServer).Check(ctx, req.(*CheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthLimiter_ShadowModeSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ShadowModeSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthLimiterServer).ShadowModeSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthLimiter_ShadowModeSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthLimiterServer).ShadowModeSet(ctx, req.(*ShadowModeSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthLimiter_AuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthLimiterServer).AuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthLimiter_AuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthLimiterServer).AuditLog(ctx, req.(*AuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthLimiter_ServiceDesc is the grpc.ServiceDesc for AuthLimiter service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -293,6 +571,10 @@ var AuthLimiter_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WhiteListAdd",
 			Handler:    _AuthLimiter_WhiteListAdd_Handler,
 		},
+		{
+			MethodName: "WhiteListList",
+			Handler:    _AuthLimiter_WhiteListList_Handler,
+		},
 		{
 			MethodName: "WhiteListDelete",
 			Handler:    _AuthLimiter_WhiteListDelete_Handler,
@@ -301,10 +583,18 @@ var AuthLimiter_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "BlackListAdd",
 			Handler:    _AuthLimiter_BlackListAdd_Handler,
 		},
+		{
+			MethodName: "BlackListList",
+			Handler:    _AuthLimiter_BlackListList_Handler,
+		},
 		{
 			MethodName: "BlackListDelete",
 			Handler:    _AuthLimiter_BlackListDelete_Handler,
 		},
+		{
+			MethodName: "RuleFind",
+			Handler:    _AuthLimiter_RuleFind_Handler,
+		},
 		{
 			MethodName: "BucketReset",
 			Handler:    _AuthLimiter_BucketReset_Handler,
@@ -313,7 +603,30 @@ var AuthLimiter_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "LimitCheck",
 			Handler:    _AuthLimiter_LimitCheck_Handler,
 		},
+		{
+			MethodName: "Check",
+			Handler:    _AuthLimiter_Check_Handler,
+		},
+		{
+			MethodName: "ShadowModeSet",
+			Handler:    _AuthLimiter_ShadowModeSet_Handler,
+		},
+		{
+			MethodName: "AuditLog",
+			Handler:    _AuthLimiter_AuditLog_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ImportRules",
+			Handler:       _AuthLimiter_ImportRules_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ExportRules",
+			Handler:       _AuthLimiter_ExportRules_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/limiter/AuthLimiter.proto",
 }
@@ -2,7 +2,10 @@ package grpc_test
 
 import (
 	"context"
+	"errors"
+	"io"
 	"os"
+	"strings"
 	"testing"
 
 	proto "github.com/rainb0w-clwn/go_auth_limiter/proto/limiter"
@@ -10,6 +13,7 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -43,6 +47,118 @@ func TestWhiteListAdd_OK(t *testing.T) {
 	})
 
 	require.NoError(t, err)
+
+	// повторное добавление той же подсети отклоняется как дубликат, поэтому правило удаляется после теста
+	t.Cleanup(func() {
+		_, _ = client.WhiteListDelete(ctx(), &proto.WhiteListDeleteRequest{IpNet: "10.10.10.0/24"})
+	})
+}
+
+func TestBlackListAdd_Metadata(t *testing.T) {
+	client := grpcClient(t)
+
+	_, err := client.BlackListAdd(ctx(), &proto.BlackListAddRequest{
+		IpNet:     "10.77.0.0/16",
+		Reason:    "credential stuffing",
+		CreatedBy: "soc",
+		Source:    proto.RuleSource_RULE_SOURCE_CLI,
+	})
+	require.NoError(t, err)
+
+	resp, err := client.RuleFind(ctx(), &proto.RuleFindRequest{IpNet: "10.77.0.0/16"})
+	require.NoError(t, err)
+	require.Len(t, resp.Rules, 1)
+
+	found := resp.Rules[0]
+	require.Equal(t, proto.ListType_LIST_TYPE_BLACKLIST, found.ListType)
+	require.Equal(t, "credential stuffing", found.Reason)
+	require.Equal(t, "soc", found.CreatedBy)
+	require.Equal(t, proto.RuleSource_RULE_SOURCE_CLI, found.Source)
+	require.NotNil(t, found.CreatedAt)
+}
+
+func TestWhiteListAdd_Conflict(t *testing.T) {
+	client := grpcClient(t)
+
+	_, err := client.BlackListAdd(ctx(), &proto.BlackListAddRequest{IpNet: "10.99.0.0/16"})
+	require.NoError(t, err)
+
+	_, err = client.WhiteListAdd(ctx(), &proto.WhiteListAddRequest{IpNet: "10.99.1.0/24"})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.BlackListAdd(ctx(), &proto.BlackListAddRequest{IpNet: "10.99.0.0/16"})
+	require.Equal(t, codes.AlreadyExists, status.Code(err))
+
+	_, err = client.WhiteListAdd(ctx(), &proto.WhiteListAddRequest{IpNet: "10.99.1.0/24", Force: true})
+	require.NoError(t, err)
+}
+
+func TestImportExportRules(t *testing.T) {
+	client := grpcClient(t)
+
+	stream, err := client.ImportRules(ctx())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&proto.ImportRulesRequest{Payload: &proto.ImportRulesRequest_Header{
+		Header: &proto.ImportRulesHeader{
+			ListType: proto.ListType_LIST_TYPE_BLACKLIST,
+			Format:   proto.RuleFormat_RULE_FORMAT_PLAIN,
+			Reason:   "feed",
+		},
+	}}))
+	require.NoError(t, stream.Send(&proto.ImportRulesRequest{Payload: &proto.ImportRulesRequest_Chunk{
+		Chunk: []byte("# feed\n10.98.1.0/24\n10.98.2.0/24\n10.98.1.0/24\nnot-an-ip\n"),
+	}}))
+
+	summary, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	require.Equal(t, int32(2), summary.Added)
+	require.Equal(t, int32(1), summary.Skipped)
+	require.Equal(t, int32(1), summary.Invalid)
+	require.Len(t, summary.Errors, 1)
+	require.Equal(t, int32(5), summary.Errors[0].Line)
+
+	export, err := client.ExportRules(ctx(), &proto.ExportRulesRequest{
+		ListType: proto.ListType_LIST_TYPE_BLACKLIST,
+		Format:   proto.RuleFormat_RULE_FORMAT_PLAIN,
+	})
+	require.NoError(t, err)
+
+	var out strings.Builder
+	for {
+		chunk, err := export.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		out.Write(chunk.Chunk)
+	}
+	require.Contains(t, out.String(), "10.98.1.0/24\n")
+	require.Contains(t, out.String(), "10.98.2.0/24\n")
+}
+
+func TestAuditLog(t *testing.T) {
+	client := grpcClient(t)
+	actorCtx := metadata.AppendToOutgoingContext(ctx(), "x-actor", "auditor", "x-request-id", "audit-req-1")
+
+	_, err := client.BlackListAdd(actorCtx, &proto.BlackListAddRequest{IpNet: "10.97.0.0/16", Reason: "audit"})
+	require.NoError(t, err)
+	_, err = client.BlackListDelete(actorCtx, &proto.BlackListDeleteRequest{IpNet: "10.97.0.0/16"})
+	require.NoError(t, err)
+
+	resp, err := client.AuditLog(ctx(), &proto.AuditLogRequest{Actor: "auditor", Target: "10.97.0.0/16"})
+	require.NoError(t, err)
+	require.Len(t, resp.Entries, 2)
+
+	deleted, added := resp.Entries[0], resp.Entries[1]
+	require.Equal(t, "blacklist.delete", deleted.Action)
+	require.Contains(t, deleted.Before, `"reason":"audit"`)
+	require.Empty(t, deleted.After)
+	require.Equal(t, "blacklist.add", added.Action)
+	require.Equal(t, "audit-req-1", added.RequestId)
+	// x-actor задает клиент: адрес соединения сохраняется рядом с ним
+	require.NotEmpty(t, added.Peer)
+	require.Empty(t, added.Before)
+	require.Contains(t, added.After, `"ip":"10.97.0.0/16"`)
 }
 
 func TestWhiteListAdd_InvalidArgument(t *testing.T) {
@@ -186,6 +302,29 @@ func TestLimitCheck_OK(t *testing.T) {
 	require.NotNil(t, resp)
 }
 
+func TestCheck_Dimensions(t *testing.T) {
+	client := grpcClient(t)
+
+	dimensions := map[string]string{
+		"login":    "dimensions",
+		"password": "secret",
+		"ip":       "127.0.0.2",
+		"device":   "fp-1",
+	}
+	resp, err := client.Check(ctx(), &proto.CheckRequest{Dimensions: dimensions})
+	require.NoError(t, err)
+	require.True(t, resp.Allowed)
+
+	delete(dimensions, "login")
+	_, err = client.Check(ctx(), &proto.CheckRequest{Dimensions: dimensions})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	dimensions["login"] = "dimensions"
+	dimensions["unknown"] = "value"
+	_, err = client.Check(ctx(), &proto.CheckRequest{Dimensions: dimensions})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestLimitCheck_InvalidArgument(t *testing.T) {
 	client := grpcClient(t)
 
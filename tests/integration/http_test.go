@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"testing"
 	"time"
 
@@ -67,7 +68,12 @@ type LimitCheckRequestDTO struct {
 }
 
 type LimitCheckResponseDTO struct {
-	Allowed bool `json:"allowed"`
+	Allowed    bool   `json:"allowed"`
+	Remaining  int    `json:"remaining"`
+	Limit      int    `json:"limit"`
+	RetryAfter int    `json:"retryAfter"`
+	ResetAfter int    `json:"resetAfter"`
+	LimitType  string `json:"limitType"`
 }
 
 func TestHTTP_WhiteListAdd_OK(t *testing.T) {
@@ -80,6 +86,60 @@ func TestHTTP_WhiteListAdd_OK(t *testing.T) {
 	defer resp.Body.Close()
 
 	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// повторное добавление той же подсети отклоняется как дубликат, поэтому правило удаляется после теста
+	t.Cleanup(func() {
+		resp, _ := doJSONRequest(t, http.MethodDelete, "/whitelist", IPNetRequest{IPNet: "10.10.10.0/24"})
+		resp.Body.Close()
+	})
+}
+
+func TestHTTP_BlackListAdd_IPv6(t *testing.T) {
+	resp, _ := doJSONRequest(
+		t,
+		http.MethodPost,
+		"/blacklist",
+		IPNetRequest{IPNet: "2001:db8:dead::/48"},
+	)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, body := doJSONRequest(
+		t,
+		http.MethodPost,
+		"/check",
+		LimitCheckRequestDTO{Login: "ipv6", Password: "ipv6", IP: "2001:db8:dead:1::1"},
+	)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result LimitCheckResponseDTO
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.False(t, result.Allowed)
+}
+
+func TestHTTP_BlackListList_ContainsIP(t *testing.T) {
+	resp, _ := doJSONRequest(
+		t,
+		http.MethodPost,
+		"/blacklist",
+		IPNetRequest{IPNet: "10.88.0.0/16"},
+	)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp, body := doJSONRequest(t, http.MethodGet, "/blacklist?containsIp=10.88.1.2&pageSize=10", nil)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var result struct {
+		Rules []struct {
+			IPNet string `json:"ipNet"`
+		} `json:"rules"`
+	}
+	require.NoError(t, json.Unmarshal(body, &result))
+	require.NotEmpty(t, result.Rules)
+	require.Equal(t, "10.88.0.0/16", result.Rules[0].IPNet)
 }
 
 func TestHTTP_WhiteListAdd_InvalidArgument(t *testing.T) {
@@ -230,6 +290,11 @@ func TestHTTP_LimitCheck_OK(t *testing.T) {
 
 	var parsed LimitCheckResponseDTO
 	require.NoError(t, json.Unmarshal(body, &parsed))
+	require.Equal(t, strconv.Itoa(parsed.Remaining), resp.Header.Get("RateLimit-Remaining"))
+	require.Equal(t, strconv.Itoa(parsed.Limit), resp.Header.Get("RateLimit-Limit"))
+	// разрешенный запрос израсходовал токен: лимит восстановится не сразу
+	require.Positive(t, parsed.ResetAfter)
+	require.Equal(t, strconv.Itoa(parsed.ResetAfter), resp.Header.Get("RateLimit-Reset"))
 }
 
 func TestHTTP_LimitCheck_InvalidArgument(t *testing.T) {